@@ -0,0 +1,60 @@
+package main
+
+import (
+	"TON/internal/chain"
+	"TON/pkg/logger"
+	"TON/pkg/tonwallet"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"flag"
+	"os"
+)
+
+const serviceName = "LiteServerStandIn"
+
+// A local lite-server serving a chain fixture file, for running the service with
+// CHAIN_PROVIDER=liteserver and LITESERVER_PROOF_CHECK=unsafe without network access.
+func main() {
+	fixturePath := flag.String("fixture", "conf/chain_fixture.json", "path to the chain fixture file")
+	listen := flag.String("listen", "127.0.0.1:46732", "address to listen on")
+	out := flag.String("config", "conf/standin.config.json", "path to write the global config to")
+	flag.Parse()
+
+	ctx := context.Background()
+	Logger := logger.New(serviceName)
+
+	fixture, err := chain.LoadFixtureProvider(*fixturePath, tonwallet.Options{})
+	if err != nil {
+		Logger.Error(ctx, "Error loaded fixture: "+err.Error())
+		return
+	}
+
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		Logger.Error(ctx, "Error generated key: "+err.Error())
+		return
+	}
+
+	server := chain.NewStandInLiteServer(key, fixture)
+	globalConfig, err := server.GlobalConfig(*listen)
+	if err != nil {
+		Logger.Error(ctx, "Error built global config: "+err.Error())
+		return
+	}
+
+	data, err := json.MarshalIndent(globalConfig, "", "  ")
+	if err != nil {
+		Logger.Error(ctx, "Error encoded global config: "+err.Error())
+		return
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		Logger.Error(ctx, "Error wrote global config: "+err.Error())
+		return
+	}
+
+	Logger.Info(ctx, "Lite-server stand-in listening on "+*listen+", global config written to "+*out)
+	if err := server.Listen(*listen); err != nil {
+		Logger.Error(ctx, "Error listen: "+err.Error())
+	}
+}
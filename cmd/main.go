@@ -1,6 +1,7 @@
 package main
 
 import (
+	"TON/internal/client"
 	"TON/internal/config"
 	"TON/internal/transport/http"
 	"TON/pkg/jwt"
@@ -10,6 +11,10 @@ import (
 
 const serviceName = "TonOauthService"
 
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description Admin token as "Bearer <AdminToken>".
 func main() {
 	ctx := context.Background()
 	Logger := logger.New(serviceName)
@@ -31,7 +36,17 @@ func main() {
 		Logger.Error(ctx, "Error loaded private key: "+err.Error())
 	}
 
-	e := http.New(Logger, cfg, privateKey, publicKey)
+	clients, err := client.Load(cfg.ClientsPath, cfg.ActivityScanLimit)
+	if err != nil {
+		Logger.Error(ctx, "Error loaded clients: "+err.Error())
+		return
+	}
+
+	e, err := http.New(Logger, cfg, privateKey, publicKey, clients)
+	if err != nil {
+		Logger.Error(ctx, "Error setup routes: "+err.Error())
+		return
+	}
 
 	httpServer := http.Start(e, Logger, cfg.HTTPServerPort)
 
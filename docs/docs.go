@@ -15,67 +15,103 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/oauth/authorize": {
+        "/1.0/identifiers/{did}": {
             "get": {
-                "description": "Generate a one-time nonce for TON OAuth.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Build the DID document of a TON wallet, following the Universal Resolver driver API. The document holds the ed25519 key of the wallet read from the chain\nand services from the site and storage records of its TON DNS name. Testnet wallets are resolved from did:ton:testnet identifiers.\nRequests accepting application/did+ld+json get the document alone, all others the resolution result. Failures are resolution results with an error.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "did"
                 ],
-                "summary": "Generate authorization challenge",
+                "summary": "Resolve a did:ton identifier",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Redirect URI",
-                        "name": "redirect_uri",
-                        "in": "query",
+                        "description": "DID, e.g. did:ton:0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                        "name": "did",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Scope",
-                        "name": "scope",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.AuthorizeResponseDTO"
+                            "$ref": "#/definitions/dto.DIDResolutionDTO"
                         }
                     },
                     "400": {
-                        "description": "Validation failed",
+                        "description": "invalidDid, the identifier is no valid DID",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                            "$ref": "#/definitions/dto.DIDResolutionDTO"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "notFound, the wallet does not exist",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                            "$ref": "#/definitions/dto.DIDResolutionDTO"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "500": {
+                        "description": "internalError, the chain could not be read",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                            "$ref": "#/definitions/dto.DIDResolutionDTO"
                         }
                     },
-                    "404": {
-                        "description": "Not found",
+                    "501": {
+                        "description": "methodNotSupported, the DID is no did:ton",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DIDResolutionDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/audit": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Get the latest audit events, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the audit trail",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events, 100 by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events whose action starts with the prefix, e.g. list.",
+                        "name": "action",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuditResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized, admin token required",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
@@ -83,43 +119,102 @@ const docTemplate = `{
                 }
             }
         },
-        "/oauth/jwks": {
+        "/admin/credentials": {
             "get": {
-                "description": "Get public keys to verify JWT tokens issued by TON OAuth.",
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Get the verifiable credentials that have not expired, with their status list index and revocation.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "jwks"
+                    "admin"
+                ],
+                "summary": "List issued credentials",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only credentials of the wallet",
+                        "name": "address",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get JSON Web Key Set (JWKS)",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.JWKSResponseDTO"
+                            "$ref": "#/definitions/dto.CredentialsResponseDTO"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid address",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Unauthorized, admin token required",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
+                    }
+                }
+            }
+        },
+        "/admin/credentials/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Set the bit of a verifiable credential in the status list. The change is recorded in the audit trail.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Revoke a credential",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Credential ID, a urn:uuid",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    {
+                        "type": "string",
+                        "description": "Reason of the revocation",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin for the audit trail",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Revoked"
+                    },
+                    "401": {
+                        "description": "Unauthorized, admin token required",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     },
                     "404": {
-                        "description": "Not found",
+                        "description": "Credential not found or expired",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "409": {
+                        "description": "Credential already revoked",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
@@ -133,9 +228,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/oauth/token": {
+        "/admin/fixture/transactions": {
             "post": {
-                "description": "Create JWT after successful verification of TON wallet.",
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Record an incoming transfer in the fixture chain provider, e.g. the transfer of a transaction mode login.\nOnly available with the fixture chain provider.",
                 "consumes": [
                     "application/json"
                 ],
@@ -143,63 +243,106 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Create JWT token",
+                "summary": "Add a transfer to the fixture chain",
                 "parameters": [
                     {
-                        "description": "Token request",
+                        "description": "Transfer",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.TokenRequestDTO"
+                            "$ref": "#/definitions/dto.FixtureTransactionRequestDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.TokenResponseDTO"
+                            "$ref": "#/definitions/dto.FixtureTransactionResponseDTO"
                         }
                     },
                     "400": {
-                        "description": "Bad request, invalid body or validation failed",
+                        "description": "Bad request, invalid body, network or address",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized, verification failed",
+                        "description": "Unauthorized, admin token required",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
+                    }
+                }
+            }
+        },
+        "/admin/lists/{kind}": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Get the wallets on the allow or deny list that have not expired.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List allow or deny list entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "List: allow or deny",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries of the client",
+                        "name": "client_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListEntriesResponseDTO"
+                        }
                     },
-                    "404": {
-                        "description": "Not found",
+                    "400": {
+                        "description": "Unknown list",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized, admin token required",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     }
                 }
-            }
-        },
-        "/oauth/verify": {
+            },
             "post": {
-                "description": "Verify signed message from TON wallet using ed25519.",
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Put a wallet on the allow or deny list of all clients or of one client, optionally until it expires.\nAn existing entry for the same wallet, list and client is replaced. The change is recorded in the audit trail.",
                 "consumes": [
                     "application/json"
                 ],
@@ -207,47 +350,119 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Verify TON wallet signature",
+                "summary": "Add a wallet to the allow or deny list",
                 "parameters": [
                     {
-                        "description": "Verify request",
+                        "type": "string",
+                        "description": "List: allow or deny",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin for the audit trail",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "List entry",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.VerifyRequestDTO"
+                            "$ref": "#/definitions/dto.ListEntryRequestDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.VerifyResponseDTO"
+                            "$ref": "#/definitions/dto.ListEntryDTO"
                         }
                     },
                     "400": {
-                        "description": "Bad request, invalid body or validation failed",
+                        "description": "Bad request, invalid body, list, client or expiry",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized, signature invalid",
+                        "description": "Unauthorized, admin token required",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/lists/{kind}/{address}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Take a wallet off the allow or deny list of all clients or of one client. The change is recorded in the audit trail.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Remove a wallet from the allow or deny list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "List: allow or deny",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client of the entry, empty for the global entry",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin for the audit trail",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Removed"
+                    },
+                    "400": {
+                        "description": "Unknown list or invalid address",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized, admin token required",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     },
                     "404": {
-                        "description": "Not found",
+                        "description": "Entry not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
@@ -261,9 +476,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/oauth/verify-token": {
+        "/admin/policies/dry-run": {
             "post": {
-                "description": "Verify JWT issued by TON OAuth service.",
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Evaluate the access policy of a client, or the policy in the request, for a wallet without logging in.\nReturns the decision together with the facts it was made on.",
                 "consumes": [
                     "application/json"
                 ],
@@ -271,17 +491,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Verify JWT token",
+                "summary": "Evaluate an access policy",
                 "parameters": [
                     {
-                        "description": "Token request",
+                        "description": "Dry-run request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.VerifyTokenRequestDTO"
+                            "$ref": "#/definitions/dto.PolicyDryRunRequestDTO"
                         }
                     }
                 ],
@@ -289,29 +509,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.VerifyTokenResponseDTO"
+                            "$ref": "#/definitions/dto.PolicyDryRunResponseDTO"
                         }
                     },
                     "400": {
-                        "description": "Bad request, invalid body or validation failed",
+                        "description": "Bad request, invalid body, client or policy",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized, token invalid",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponseDTO"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
+                        "description": "Unauthorized, admin token required",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
                     },
                     "404": {
-                        "description": "Not found",
+                        "description": "Client has no policy",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponseDTO"
                         }
@@ -324,123 +538,2204 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AuthorizeResponseDTO": {
-            "type": "object",
-            "properties": {
-                "challenge": {
-                    "description": "Unique challenge (nonce) to be signed by the user's TON wallet\nexample: nonce1234567890",
-                    "type": "string"
-                },
-                "client_id": {
-                    "description": "Client ID of the application requesting authorization\nexample: abc123def456",
-                    "type": "string"
-                },
+        },
+        "/bridge/events": {
+            "get": {
+                "description": "Open a server-sent events stream with messages for the given client ids.\nReconnecting with last_event_id acknowledges every message up to that id.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "bridge"
+                ],
+                "summary": "Subscribe to bridge events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated client ids",
+                        "name": "client_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of the last received event",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BridgeEventDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BridgeResponseDTO"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many connections",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BridgeResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/bridge/message": {
+            "post": {
+                "description": "Buffer an encrypted TonConnect message for the recipient session.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bridge"
+                ],
+                "summary": "Send a bridge message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sender client id",
+                        "name": "client_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recipient client id",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Message TTL in seconds",
+                        "name": "ttl",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message topic",
+                        "name": "topic",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Base64 encoded message",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BridgeResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BridgeResponseDTO"
+                        }
+                    },
+                    "413": {
+                        "description": "Message larger than 64 KB",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BridgeResponseDTO"
+                        }
+                    },
+                    "429": {
+                        "description": "Recipient buffer is full",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BridgeResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/clients/{client_id}/icon": {
+            "get": {
+                "description": "Get the icon referenced by the TonConnect manifest of a registered client.",
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Get client icon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client ID",
+                        "name": "client_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Icon not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/clients/{client_id}/tonconnect-manifest.json": {
+            "get": {
+                "description": "Get the tonconnect-manifest.json of a registered client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Get TonConnect manifest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client ID",
+                        "name": "client_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TonConnectManifestDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/authorize": {
+            "get": {
+                "description": "Generate a one-time nonce for TON OAuth.\nIn transaction mode the response carries a transfer to send from the wallet instead, and the challenge is polled at /oauth/transaction/{challenge}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Generate authorization challenge",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Redirect URI",
+                        "name": "redirect_uri",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Registered client ID",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Login mode: signature (default) or transaction",
+                        "name": "mode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthorizeResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many pending transaction challenges",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/credentials": {
+            "post": {
+                "description": "Verify a signed message or ton_proof like /oauth/token and issue a W3C verifiable credential in JWT format attesting control of the wallet.\nThe subject is the did:ton of the wallet or the did:key of its public key. The credential can be revoked and carries a StatusList2021 entry.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "credentials"
+                ],
+                "summary": "Issue a verifiable credential",
+                "parameters": [
+                    {
+                        "description": "Credential request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CredentialRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CredentialResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized, verification failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden, access_denied by the rules of the client",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "503": {
+                        "description": "Status list is full",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/credentials/status": {
+            "get": {
+                "description": "Get the StatusList2021 credential, a statuslist+jwt signed with the credential key, whose bits mark the revoked credentials.\nThe list is signed for ten minutes; fetch it again after it expired.",
+                "produces": [
+                    "application/jwt"
+                ],
+                "tags": [
+                    "credentials"
+                ],
+                "summary": "Get the credential status list",
+                "responses": {
+                    "200": {
+                        "description": "Status list credential",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/jwks": {
+            "get": {
+                "description": "Get public keys to verify JWT tokens issued by TON OAuth.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jwks"
+                ],
+                "summary": "Get JSON Web Key Set (JWKS)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JWKSResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/multisig": {
+            "post": {
+                "description": "Read the owners and threshold of a multisig contract and open a session collecting their signatures.\nShare the challenge with the owners; each signs the message, or a ton_proof with the challenge as payload.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "multisig"
+                ],
+                "summary": "Start a multisig login",
+                "parameters": [
+                    {
+                        "description": "Multisig contract",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MultisigStartRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MultisigSessionDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid body, client, network or no multisig contract",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many open sessions",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/multisig/{challenge}": {
+            "get": {
+                "description": "Get the owners of a multisig session, who signed and whether the threshold is reached.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "multisig"
+                ],
+                "summary": "Get a multisig login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session challenge",
+                        "name": "challenge",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MultisigSessionDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/multisig/{challenge}/signatures": {
+            "post": {
+                "description": "Verify the signature of an owner over the session message, or its ton_proof with the challenge as payload, and record it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "multisig"
+                ],
+                "summary": "Add an owner signature to a multisig login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session challenge",
+                        "name": "challenge",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Owner signature",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MultisigSignRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MultisigSessionDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid body, session expired or used",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized, signature invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden, key is not an owner",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/multisig/{challenge}/token": {
+            "post": {
+                "description": "Exchange a multisig session signed by enough owners for a JWT with the contract address as sub and the signers in the act and signers claims.\nThe owners are read again, so signers removed since the session started do not count. A session is exchanged once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "multisig"
+                ],
+                "summary": "Create JWT token for a multisig login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session challenge",
+                        "name": "challenge",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TokenResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Threshold not reached, session expired or used",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden, access_denied by the rules of the client",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/sign-data": {
+            "post": {
+                "description": "Verify the signature of a text, binary or cell payload signed with TonConnect signData against the key of the wallet.\nThe domain must be the manifest domain of the client and the timestamp recent. The response carries a JWT attesting that the wallet approved the payload.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sign-data"
+                ],
+                "summary": "Verify a TonConnect signData result",
+                "parameters": [
+                    {
+                        "description": "signData result",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SignDataRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SignDataResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid body, client, network or address",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized, signature invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/token": {
+            "post": {
+                "description": "Verify a signed message or ton_proof like /oauth/verify and create a JWT with the wallet address as sub and its network.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Create JWT token",
+                "parameters": [
+                    {
+                        "description": "Token request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TokenRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TokenResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized, verification failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden, access_denied by the rules of the client",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/transaction/{challenge}": {
+            "get": {
+                "description": "Get the state of a transaction mode login: pending until the transfer from the wallet is seen on chain, then approved.\nWith wait the request is held until the challenge leaves the pending state, for at most a minute.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get transaction challenge state",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Challenge returned by /oauth/authorize in transaction mode",
+                        "name": "challenge",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "How long to wait while pending, e.g. 30s",
+                        "name": "wait",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TransactionStatusResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Challenge not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/transaction/{challenge}/token": {
+            "post": {
+                "description": "Exchange an approved transaction mode login for a JWT with the address of the sending wallet as sub. A challenge is exchanged once.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Create JWT token for a transaction challenge",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Challenge returned by /oauth/authorize in transaction mode",
+                        "name": "challenge",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TokenResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Challenge pending, expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden, access_denied by the rules of the client",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Challenge not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/verify": {
+            "post": {
+                "description": "Verify signed message or TonConnect ton_proof from TON wallet using ed25519.\nA ton_proof must carry the domain of the manifest URL of the given client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Verify TON wallet signature",
+                "parameters": [
+                    {
+                        "description": "Verify request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized, signature invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden, access_denied by the rules of the client",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/verify-token": {
+            "post": {
+                "description": "Verify an access token issued by TON OAuth service. Tokens of another typ than at+jwt, such as attestations and credentials, are refused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Verify JWT token",
+                "parameters": [
+                    {
+                        "description": "Token request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyTokenRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyTokenResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request, invalid body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized, token invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/wallets": {
+            "get": {
+                "description": "List the wallet contracts controlled by a public key with version, balance and status.\nWallets are ordered by the wallet version preference; the first one is used when verify or token get no address.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List wallets of a public key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hex encoded public key",
+                        "name": "public_key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "TonConnect chain id (-239 or -3)",
+                        "name": "network",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Registered client ID",
+                        "name": "client_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WalletsResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponseDTO"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "did.Document": {
+            "type": "object",
+            "properties": {
+                "@context": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "assertionMethod": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "authentication": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "service": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/did.Service"
+                    }
+                },
+                "verificationMethod": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/did.VerificationMethod"
+                    }
+                }
+            }
+        },
+        "did.Metadata": {
+            "type": "object",
+            "properties": {
+                "accountStatus": {
+                    "description": "AccountStatus is the on-chain status: active, uninit or frozen.",
+                    "type": "string"
+                },
+                "canonicalId": {
+                    "description": "CanonicalID is the did:ton with the raw address, set when the DID was resolved\nfrom another address format.",
+                    "type": "string"
+                },
+                "deactivated": {
+                    "description": "Deactivated is set for frozen wallets, which can no longer sign.",
+                    "type": "boolean"
+                },
+                "dnsName": {
+                    "description": "DNSName is the TON DNS name of the wallet the services are read from.",
+                    "type": "string"
+                },
+                "walletVersion": {
+                    "description": "WalletVersion is the detected wallet contract version, empty when unknown.",
+                    "type": "string"
+                }
+            }
+        },
+        "did.Service": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "serviceEndpoint": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "did.VerificationMethod": {
+            "type": "object",
+            "properties": {
+                "controller": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "publicKeyMultibase": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AuditEventDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action that was taken\nrequired: true\nexample: list.add",
+                    "type": "string",
+                    "example": "list.add"
+                },
+                "actor": {
+                    "description": "Admin or component that caused the event\nrequired: true\nexample: alice",
+                    "type": "string",
+                    "example": "alice"
+                },
+                "client_id": {
+                    "description": "Client the action concerns\nexample: my-dapp",
+                    "type": "string",
+                    "example": "my-dapp"
+                },
+                "details": {
+                    "description": "Details of the action",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "ip": {
+                    "description": "IP address of the actor\nexample: 203.0.113.7",
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "target": {
+                    "description": "Wallet or object the action was taken on\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                },
+                "time": {
+                    "description": "Time of the event\nrequired: true\nexample: 2025-09-07T00:00:00Z",
+                    "type": "string",
+                    "example": "2025-09-07T00:00:00Z"
+                }
+            }
+        },
+        "dto.AuditResponseDTO": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "description": "Events, newest first\nrequired: true",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AuditEventDTO"
+                    }
+                }
+            }
+        },
+        "dto.AuthorizeResponseDTO": {
+            "type": "object",
+            "properties": {
+                "challenge": {
+                    "description": "Unique challenge (nonce) to be signed by the user's TON wallet\nexample: nonce1234567890",
+                    "type": "string"
+                },
+                "client_id": {
+                    "description": "Client ID of the application requesting authorization\nexample: abc123def456",
+                    "type": "string"
+                },
                 "expiresAt": {
                     "description": "Expiration time of the challenge\nexample: 2025-09-07T00:00:00Z",
                     "type": "string"
                 },
-                "redirect_uri": {
-                    "description": "Redirect URI to which the user will be sent after authorization\nexample: https://example.com/callback",
-                    "type": "string"
+                "pow": {
+                    "description": "Proof-of-work puzzle to solve before verifying, present when proof of work is enabled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.PoWChallengeDTO"
+                        }
+                    ]
+                },
+                "redirect_uri": {
+                    "description": "Redirect URI to which the user will be sent after authorization\nexample: https://example.com/callback",
+                    "type": "string"
+                },
+                "transaction": {
+                    "description": "Transfer to send in transaction mode; the challenge is then kept secret and used to\npoll /oauth/transaction/{challenge}",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TransactionChallengeDTO"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.BridgeEventDTO": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "Hex encoded public key of the sender session\nexample: 6b5f4a3cc0f1d53d3d0e1d8a9f7a3f1b2c4d5e6f708192a3b4c5d6e7f8091a2b",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Base64 encoded encrypted message body",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BridgeResponseDTO": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "Result message\nexample: OK",
+                    "type": "string",
+                    "example": "OK"
+                },
+                "statusCode": {
+                    "description": "HTTP status code\nexample: 200",
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.CredentialRecordDTO": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Raw address of the wallet\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                },
+                "clientId": {
+                    "description": "Client the wallet logged in to\nexample: my-dapp",
+                    "type": "string",
+                    "example": "my-dapp"
+                },
+                "expiresAt": {
+                    "description": "Expiration time\nexample: 2025-10-07T00:00:00Z",
+                    "type": "string",
+                    "example": "2025-10-07T00:00:00Z"
+                },
+                "id": {
+                    "description": "ID of the credential\nexample: urn:uuid:3978344f-8596-4c3a-a978-8fcaba3903c5",
+                    "type": "string",
+                    "example": "urn:uuid:3978344f-8596-4c3a-a978-8fcaba3903c5"
+                },
+                "issuedAt": {
+                    "description": "Issuance time\nexample: 2025-09-07T00:00:00Z",
+                    "type": "string",
+                    "example": "2025-09-07T00:00:00Z"
+                },
+                "reason": {
+                    "description": "Reason of the revocation\nexample: wallet compromised",
+                    "type": "string",
+                    "example": "wallet compromised"
+                },
+                "revokedAt": {
+                    "description": "Revocation time, empty while the credential is valid\nexample: 2025-09-08T00:00:00Z",
+                    "type": "string",
+                    "example": "2025-09-08T00:00:00Z"
+                },
+                "statusListIndex": {
+                    "description": "Index of the credential in the revocation status list\nexample: 42",
+                    "type": "integer",
+                    "example": 42
+                },
+                "subject": {
+                    "description": "DID of the subject\nexample: did:ton:0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "did:ton:0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                }
+            }
+        },
+        "dto.CredentialRequestDTO": {
+            "type": "object",
+            "required": [
+                "publicKey"
+            ],
+            "properties": {
+                "address": {
+                    "description": "Wallet address claimed by the user, required with proof\nIt is checked against the standard wallet contracts of the public key\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                },
+                "client_id": {
+                    "description": "ID of the registered client the wallet connected to, required with proof\nexample: my-dapp",
+                    "type": "string",
+                    "example": "my-dapp"
+                },
+                "facts": {
+                    "description": "Facts about the wallet to include: dns for its TON DNS name, nft for the collections\nmatched by the gating rules of the client. Facts the wallet lacks are left out.\nexample: [\"dns\",\"nft\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dns",
+                        "nft"
+                    ]
+                },
+                "message": {
+                    "description": "Original message that was signed, required unless proof is provided\nexample: TON OAuth challenge message",
+                    "type": "string",
+                    "example": "TON OAuth challenge message"
+                },
+                "network": {
+                    "description": "TonConnect chain id of the wallet network, defaults to the client or service network\nexample: -239",
+                    "type": "string",
+                    "enum": [
+                        "-239",
+                        "-3"
+                    ],
+                    "example": "-239"
+                },
+                "pow": {
+                    "description": "Solution of the proof-of-work puzzle of /oauth/authorize, required when proof of work is enabled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.PoWSolutionDTO"
+                        }
+                    ]
+                },
+                "proof": {
+                    "description": "TonConnect ton_proof returned by the wallet",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TonProofDTO"
+                        }
+                    ]
+                },
+                "publicKey": {
+                    "description": "Public key of the TON wallet in base64 format\nrequired: true\nexample: dGVzdF9wdWJsaWNfa2V5X2RhdGE=",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "dGVzdF9wdWJsaWNfa2V5X2RhdGE="
+                },
+                "scope": {
+                    "description": "Space separated scopes requested by the client, available to access policies\nexample: openid profile",
+                    "type": "string",
+                    "example": "openid profile"
+                },
+                "signature": {
+                    "description": "Signature of the message in base64 format, required unless proof is provided\nexample: c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA==",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA=="
+                },
+                "state_init": {
+                    "description": "StateInit BoC of the wallet in base64 format, as returned by TonConnect\nRequired to log in with a wallet that is not deployed yet\nexample: te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF...",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF..."
+                },
+                "subject": {
+                    "description": "DID the credential is issued to: ton for did:ton of the wallet (default) or key for\ndid:key of its public key\nexample: ton",
+                    "type": "string",
+                    "enum": [
+                        "ton",
+                        "key"
+                    ],
+                    "example": "ton"
+                }
+            }
+        },
+        "dto.CredentialResponseDTO": {
+            "type": "object",
+            "properties": {
+                "credential": {
+                    "description": "Verifiable credential in JWT format\nrequired: true\nexample: eyJhbGciOiJSUzI1NiIsImtpZCI6Im1haW4ta2V5IiwidHlwIjoiSldUIn0...",
+                    "type": "string",
+                    "example": "eyJhbGciOiJSUzI1NiIsImtpZCI6Im1haW4ta2V5IiwidHlwIjoiSldUIn0..."
+                },
+                "expiresAt": {
+                    "description": "Expiration time of the credential\nexample: 2025-10-07T00:00:00Z",
+                    "type": "string",
+                    "example": "2025-10-07T00:00:00Z"
+                },
+                "id": {
+                    "description": "ID of the credential\nexample: urn:uuid:3978344f-8596-4c3a-a978-8fcaba3903c5",
+                    "type": "string",
+                    "example": "urn:uuid:3978344f-8596-4c3a-a978-8fcaba3903c5"
+                },
+                "statusListIndex": {
+                    "description": "Index of the credential in the revocation status list\nexample: 42",
+                    "type": "integer",
+                    "example": 42
+                },
+                "subject": {
+                    "description": "DID of the subject\nexample: did:ton:0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "did:ton:0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                }
+            }
+        },
+        "dto.CredentialsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "credentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CredentialRecordDTO"
+                    }
+                }
+            }
+        },
+        "dto.DIDResolutionDTO": {
+            "type": "object",
+            "properties": {
+                "@context": {
+                    "description": "JSON-LD context of the resolution result\nexample: https://w3id.org/did-resolution/v1",
+                    "type": "string",
+                    "example": "https://w3id.org/did-resolution/v1"
+                },
+                "didDocument": {
+                    "description": "Resolved DID document, null when the resolution failed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/did.Document"
+                        }
+                    ]
+                },
+                "didDocumentMetadata": {
+                    "description": "Metadata of the wallet behind the document",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/did.Metadata"
+                        }
+                    ]
+                },
+                "didResolutionMetadata": {
+                    "$ref": "#/definitions/dto.DIDResolutionMetadataDTO"
+                }
+            }
+        },
+        "dto.DIDResolutionMetadataDTO": {
+            "type": "object",
+            "properties": {
+                "contentType": {
+                    "description": "Media type of the document\nexample: application/did+ld+json",
+                    "type": "string",
+                    "example": "application/did+ld+json"
+                },
+                "error": {
+                    "description": "Error code of a failed resolution: invalidDid, notFound, methodNotSupported or internalError\nexample: notFound",
+                    "type": "string",
+                    "example": "notFound"
+                },
+                "errorMessage": {
+                    "description": "Description of the error\nexample: DID not found: wallet does not exist",
+                    "type": "string",
+                    "example": "DID not found: wallet does not exist"
+                }
+            }
+        },
+        "dto.ErrorResponseDTO": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "description": "Optional detailed information about the error\nexample: \"field 'redirect_uri' is required\"",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Short error message\nexample: Validation failed",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FixtureTransactionRequestDTO": {
+            "type": "object",
+            "required": [
+                "address",
+                "from"
+            ],
+            "properties": {
+                "address": {
+                    "description": "Account receiving the transfer\nrequired: true\nexample: 0:77a6f2e1b9b4c0cd5a0a1b65e4c5f73a2f2f3c5fb0d1c0f0a3c7d5e8f9a0b1c2",
+                    "type": "string",
+                    "example": "0:77a6f2e1b9b4c0cd5a0a1b65e4c5f73a2f2f3c5fb0d1c0f0a3c7d5e8f9a0b1c2"
+                },
+                "comment": {
+                    "description": "Text comment of the transfer\nexample: ton-oauth-mfrggzdfmztwq2lk",
+                    "type": "string",
+                    "example": "ton-oauth-mfrggzdfmztwq2lk"
+                },
+                "from": {
+                    "description": "Wallet sending the transfer\nrequired: true\nexample: 0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869",
+                    "type": "string",
+                    "example": "0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869"
+                },
+                "network": {
+                    "description": "TonConnect chain id of the network, the default network when empty\nexample: -239",
+                    "type": "string",
+                    "example": "-239"
+                },
+                "value": {
+                    "description": "Value in nanotons\nrequired: true\nexample: 10000000",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 10000000
+                }
+            }
+        },
+        "dto.FixtureTransactionResponseDTO": {
+            "type": "object",
+            "properties": {
+                "hash": {
+                    "description": "Hash of the transaction in hex\nrequired: true",
+                    "type": "string"
+                },
+                "lt": {
+                    "description": "Logical time of the transaction, after every other transaction of the account\nrequired: true",
+                    "type": "integer"
+                },
+                "time": {
+                    "description": "Time of the transaction\nrequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JWK": {
+            "type": "object",
+            "required": [
+                "alg",
+                "e",
+                "kid",
+                "kty",
+                "n",
+                "use"
+            ],
+            "properties": {
+                "alg": {
+                    "description": "Algorithm\nrequired: true\nexample: RS256",
+                    "type": "string",
+                    "example": "RS256"
+                },
+                "e": {
+                    "description": "Exponent for RSA public key\nrequired: true\nexample: AQAB",
+                    "type": "string",
+                    "example": "AQAB"
+                },
+                "kid": {
+                    "description": "Key ID\nrequired: true\nexample: key1",
+                    "type": "string",
+                    "example": "key1"
+                },
+                "kty": {
+                    "description": "Key type\nrequired: true\nexample: RSA",
+                    "type": "string",
+                    "example": "RSA"
+                },
+                "n": {
+                    "description": "Modulus for RSA public key\nrequired: true\nexample: 0vx7agoebGcQSuuPiLJXZptNnP9Z...",
+                    "type": "string",
+                    "example": "0vx7agoebGcQSuuPiLJXZptNnP9Z..."
+                },
+                "use": {
+                    "description": "Intended use of the key\nrequired: true\nexample: sig",
+                    "type": "string",
+                    "example": "sig"
+                }
+            }
+        },
+        "dto.JWKSResponseDTO": {
+            "type": "object",
+            "required": [
+                "keys"
+            ],
+            "properties": {
+                "keys": {
+                    "description": "Array of JSON Web Keys\nrequired: true",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JWK"
+                    }
+                }
+            }
+        },
+        "dto.ListEntriesResponseDTO": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Entries that have not expired, oldest first\nrequired: true",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ListEntryDTO"
+                    }
+                }
+            }
+        },
+        "dto.ListEntryDTO": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Raw wallet address\nrequired: true\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                },
+                "client_id": {
+                    "description": "Client the entry applies to, empty for all clients\nexample: my-dapp",
+                    "type": "string",
+                    "example": "my-dapp"
+                },
+                "created_at": {
+                    "description": "Time the entry was created\nrequired: true\nexample: 2025-09-07T00:00:00Z",
+                    "type": "string",
+                    "example": "2025-09-07T00:00:00Z"
+                },
+                "created_by": {
+                    "description": "Admin who created the entry\nexample: alice",
+                    "type": "string",
+                    "example": "alice"
+                },
+                "expires_at": {
+                    "description": "Time the entry expires\nexample: 2026-01-01T00:00:00Z",
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "kind": {
+                    "description": "List of the entry: allow or deny\nrequired: true\nexample: deny",
+                    "type": "string",
+                    "example": "deny"
+                },
+                "reason": {
+                    "description": "Why the wallet is listed\nexample: spam registrations",
+                    "type": "string",
+                    "example": "spam registrations"
+                }
+            }
+        },
+        "dto.ListEntryRequestDTO": {
+            "type": "object",
+            "required": [
+                "address"
+            ],
+            "properties": {
+                "address": {
+                    "description": "Wallet address in any form\nrequired: true\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                },
+                "client_id": {
+                    "description": "Client the entry applies to, empty for all clients\nexample: my-dapp",
+                    "type": "string",
+                    "example": "my-dapp"
+                },
+                "expires_at": {
+                    "description": "Time the entry expires, it never expires when neither expires_at nor ttl is set\nexample: 2026-01-01T00:00:00Z",
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "reason": {
+                    "description": "Why the wallet is listed\nexample: spam registrations",
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "spam registrations"
+                },
+                "ttl": {
+                    "description": "Lifetime of the entry as a Go duration, an alternative to expires_at\nexample: 720h",
+                    "type": "string",
+                    "example": "720h"
+                }
+            }
+        },
+        "dto.MultisigSessionDTO": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Address of the multisig contract\nrequired: true",
+                    "type": "string",
+                    "example": "0:3b4c8f7e0a5d2c1b9e8f7a6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29"
+                },
+                "challenge": {
+                    "description": "Challenge identifying the session, shared with the owners\nrequired: true\nexample: JDcyHLLx39O7or8Qo-bV7_wrv95r5IeI50QlZhJq45g",
+                    "type": "string",
+                    "example": "JDcyHLLx39O7or8Qo-bV7_wrv95r5IeI50QlZhJq45g"
+                },
+                "expiresAt": {
+                    "description": "Expiration time of the session\nexample: 2025-09-07T00:15:00Z",
+                    "type": "string",
+                    "example": "2025-09-07T00:15:00Z"
+                },
+                "kind": {
+                    "description": "Multisig contract interface: v1 (owner keys) or v2 (signer wallets)\nexample: v2",
+                    "type": "string",
+                    "example": "v2"
+                },
+                "message": {
+                    "description": "Message the owners sign with their keys\nrequired: true\nexample: TON-OAUTH:multisig:0:3b4c8f7e0a5d2c1b9e8f7a6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29:JDcyHLLx39O7or8Qo-bV7_wrv95r5IeI50QlZhJq45g",
+                    "type": "string",
+                    "example": "TON-OAUTH:multisig:0:3b4c8f7e0a5d2c1b9e8f7a6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29:JDcyHLLx39O7or8Qo-bV7_wrv95r5IeI50QlZhJq45g"
+                },
+                "network": {
+                    "description": "Network of the multisig contract\nexample: -239",
+                    "type": "string",
+                    "example": "-239"
+                },
+                "signers": {
+                    "description": "Owners of the contract and whether they signed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MultisigSignerDTO"
+                    }
+                },
+                "status": {
+                    "description": "collecting, complete, expired or used\nexample: collecting",
+                    "type": "string",
+                    "example": "collecting"
+                },
+                "threshold": {
+                    "description": "Number of owner signatures required\nexample: 2",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "dto.MultisigSignRequestDTO": {
+            "type": "object",
+            "required": [
+                "publicKey"
+            ],
+            "properties": {
+                "address": {
+                    "description": "Wallet of the owner the ton_proof was made with, required with proof\nexample: 0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869",
+                    "type": "string",
+                    "example": "0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869"
+                },
+                "proof": {
+                    "description": "TonConnect ton_proof of the owner wallet with the challenge as payload",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TonProofDTO"
+                        }
+                    ]
+                },
+                "publicKey": {
+                    "description": "Public key of the owner in base64 format\nrequired: true\nexample: dGVzdF9wdWJsaWNfa2V5X2RhdGE=",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "dGVzdF9wdWJsaWNfa2V5X2RhdGE="
+                },
+                "signature": {
+                    "description": "Signature of the session message in base64 format, required unless proof is provided\nexample: c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA==",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA=="
+                }
+            }
+        },
+        "dto.MultisigSignerDTO": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Signer wallet of v2 contracts\nexample: 0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869",
+                    "type": "string",
+                    "example": "0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869"
+                },
+                "index": {
+                    "description": "Index of the owner in the contract\nexample: 0",
+                    "type": "integer",
+                    "example": 0
+                },
+                "publicKey": {
+                    "description": "Hex encoded public key of the owner, empty for v2 signers that are no standard wallets\nexample: 8a88e3dd7409f195fd52db2d3cba5d72ca6709bf1d94121bf3748801b40f6f5c",
+                    "type": "string",
+                    "example": "8a88e3dd7409f195fd52db2d3cba5d72ca6709bf1d94121bf3748801b40f6f5c"
+                },
+                "signed": {
+                    "description": "Whether the owner signed the session\nexample: true",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dto.MultisigStartRequestDTO": {
+            "type": "object",
+            "required": [
+                "address"
+            ],
+            "properties": {
+                "address": {
+                    "description": "Address of the multisig contract\nrequired: true\nexample: 0:3b4c8f7e0a5d2c1b9e8f7a6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29",
+                    "type": "string",
+                    "example": "0:3b4c8f7e0a5d2c1b9e8f7a6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29"
+                },
+                "client_id": {
+                    "description": "ID of the registered client, required for ton_proof signatures\nexample: my-dapp",
+                    "type": "string",
+                    "example": "my-dapp"
+                },
+                "network": {
+                    "description": "TonConnect chain id of the network, defaults to the client or service network\nexample: -239",
+                    "type": "string",
+                    "enum": [
+                        "-239",
+                        "-3"
+                    ],
+                    "example": "-239"
+                },
+                "scope": {
+                    "description": "Space separated scopes requested by the client, available to access policies\nexample: openid treasury",
+                    "type": "string",
+                    "example": "openid treasury"
+                }
+            }
+        },
+        "dto.PoWChallengeDTO": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "description": "Hash algorithm of the puzzle\nrequired: true\nexample: sha256",
+                    "type": "string",
+                    "example": "sha256"
+                },
+                "challenge": {
+                    "description": "Signed challenge, valid once and only from the IP address it was issued to\nrequired: true\nexample: q8Yk3n0cR1m2Z6bq0h6V7wAAAABo7c2AEHk0m1d3xq3uJb5kS9QqP2g",
+                    "type": "string",
+                    "example": "q8Yk3n0cR1m2Z6bq0h6V7wAAAABo7c2AEHk0m1d3xq3uJb5kS9QqP2g"
+                },
+                "difficulty": {
+                    "description": "Number of leading zero bits the hash must have\nrequired: true\nexample: 16",
+                    "type": "integer",
+                    "example": 16
+                },
+                "expiresAt": {
+                    "description": "Expiration time of the puzzle\nexample: 2025-09-07T00:00:00Z",
+                    "type": "string",
+                    "example": "2025-09-07T00:00:00Z"
+                }
+            }
+        },
+        "dto.PoWSolutionDTO": {
+            "type": "object",
+            "required": [
+                "challenge",
+                "nonce"
+            ],
+            "properties": {
+                "challenge": {
+                    "description": "Challenge returned by /oauth/authorize\nrequired: true\nexample: q8Yk3n0cR1m2Z6bq0h6V7wAAAABo7c2AEHk0m1d3xq3uJb5kS9QqP2g",
+                    "type": "string",
+                    "example": "q8Yk3n0cR1m2Z6bq0h6V7wAAAABo7c2AEHk0m1d3xq3uJb5kS9QqP2g"
+                },
+                "nonce": {
+                    "description": "Nonce whose hash with the challenge has the required zero bits\nrequired: true\nexample: 48213",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "48213"
+                }
+            }
+        },
+        "dto.PolicyDryRunRequestDTO": {
+            "type": "object",
+            "required": [
+                "address",
+                "client_id"
+            ],
+            "properties": {
+                "address": {
+                    "description": "Wallet address to evaluate the policy for\nrequired: true\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                },
+                "client_id": {
+                    "description": "ID of the client whose policy is evaluated\nrequired: true\nexample: my-dapp",
+                    "type": "string",
+                    "example": "my-dapp"
+                },
+                "ip": {
+                    "description": "IP address of the simulated login\nexample: 203.0.113.7",
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "network": {
+                    "description": "TonConnect chain id of the wallet network, defaults to the client or service network\nexample: -239",
+                    "type": "string",
+                    "enum": [
+                        "-239",
+                        "-3"
+                    ],
+                    "example": "-239"
+                },
+                "policy": {
+                    "description": "Policy to evaluate instead of the loaded policy of the client",
+                    "type": "object"
+                },
+                "scope": {
+                    "description": "Space separated scopes of the simulated login\nexample: openid profile",
+                    "type": "string",
+                    "example": "openid profile"
+                }
+            }
+        },
+        "dto.PolicyDryRunResponseDTO": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "description": "Whether the wallet would be allowed to log in\nrequired: true\nexample: true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "claims": {
+                    "description": "Claims that would be added to the token",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "facts": {
+                    "description": "Facts the policy was evaluated on",
+                    "type": "object"
+                },
+                "matched": {
+                    "description": "Names of the rules that applied\nexample: [\"holders\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "holders"
+                    ]
+                },
+                "reason": {
+                    "description": "Deny rule that rejected the wallet, \"allow\" when the allow expression was false\nexample: frozen-wallets",
+                    "type": "string",
+                    "example": "frozen-wallets"
+                },
+                "roles": {
+                    "description": "Roles that would be granted\nexample: [\"holder\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "holder"
+                    ]
+                },
+                "scopes": {
+                    "description": "Scopes that would be granted\nexample: [\"premium\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "premium"
+                    ]
                 }
             }
         },
-        "dto.ErrorResponseDTO": {
+        "dto.SignDataPayloadDTO": {
             "type": "object",
+            "required": [
+                "type"
+            ],
             "properties": {
-                "details": {
-                    "description": "Optional detailed information about the error\nexample: \"field 'redirect_uri' is required\"",
-                    "type": "string"
+                "bytes": {
+                    "description": "Bytes in base64 format, for binary payloads\nexample: aGVsbG8gd29ybGQ=",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "aGVsbG8gd29ybGQ="
                 },
-                "error": {
-                    "description": "Short error message\nexample: Validation failed",
-                    "type": "string"
+                "cell": {
+                    "description": "Cell BoC in base64 format, for cell payloads\nexample: te6cckEBAQEAEQAAHgAAAABIZWxsbywgVE9OIb7WCx4=",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "te6cckEBAQEAEQAAHgAAAABIZWxsbywgVE9OIb7WCx4="
+                },
+                "schema": {
+                    "description": "TL-B schema of the cell, for cell payloads\nexample: message#_ text:^Cell = Message;",
+                    "type": "string",
+                    "example": "message#_ text:^Cell = Message;"
+                },
+                "text": {
+                    "description": "Text shown to the user, for text payloads\nexample: Confirm the transfer of 10 TON to Alice",
+                    "type": "string",
+                    "example": "Confirm the transfer of 10 TON to Alice"
+                },
+                "type": {
+                    "description": "Payload type: text, binary or cell\nrequired: true\nexample: text",
+                    "type": "string",
+                    "enum": [
+                        "text",
+                        "binary",
+                        "cell"
+                    ],
+                    "example": "text"
                 }
             }
         },
-        "dto.JWK": {
+        "dto.SignDataRequestDTO": {
             "type": "object",
             "required": [
-                "alg",
-                "e",
-                "kid",
-                "kty",
-                "n",
-                "use"
+                "address",
+                "client_id",
+                "domain",
+                "payload",
+                "signature",
+                "timestamp"
             ],
             "properties": {
-                "alg": {
-                    "description": "Algorithm\nrequired: true\nexample: RS256",
+                "address": {
+                    "description": "Address of the wallet that signed the data\nrequired: true\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
                     "type": "string",
-                    "example": "RS256"
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
                 },
-                "e": {
-                    "description": "Exponent for RSA public key\nrequired: true\nexample: AQAB",
+                "client_id": {
+                    "description": "ID of the registered client whose manifest domain the data was signed for\nrequired: true\nexample: my-dapp",
                     "type": "string",
-                    "example": "AQAB"
+                    "example": "my-dapp"
                 },
-                "kid": {
-                    "description": "Key ID\nrequired: true\nexample: key1",
+                "domain": {
+                    "description": "Domain of the dApp the wallet signed the data for\nrequired: true\nexample: my-dapp.example.com",
                     "type": "string",
-                    "example": "key1"
+                    "maxLength": 253,
+                    "example": "my-dapp.example.com"
                 },
-                "kty": {
-                    "description": "Key type\nrequired: true\nexample: RSA",
+                "network": {
+                    "description": "TonConnect chain id of the wallet network, defaults to the client or service network\nexample: -239",
                     "type": "string",
-                    "example": "RSA"
+                    "enum": [
+                        "-239",
+                        "-3"
+                    ],
+                    "example": "-239"
                 },
-                "n": {
-                    "description": "Modulus for RSA public key\nrequired: true\nexample: 0vx7agoebGcQSuuPiLJXZptNnP9Z...",
+                "payload": {
+                    "description": "Payload the wallet was asked to sign\nrequired: true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.SignDataPayloadDTO"
+                        }
+                    ]
+                },
+                "publicKey": {
+                    "description": "Public key of the wallet in base64 format, required with state_init\nexample: dGVzdF9wdWJsaWNfa2V5X2RhdGE=",
                     "type": "string",
-                    "example": "0vx7agoebGcQSuuPiLJXZptNnP9Z..."
+                    "format": "base64",
+                    "example": "dGVzdF9wdWJsaWNfa2V5X2RhdGE="
                 },
-                "use": {
-                    "description": "Intended use of the key\nrequired: true\nexample: sig",
+                "signature": {
+                    "description": "Signature returned by the wallet in base64 format\nrequired: true\nexample: c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA==",
                     "type": "string",
-                    "example": "sig"
+                    "format": "base64",
+                    "example": "c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA=="
+                },
+                "state_init": {
+                    "description": "StateInit BoC of the wallet in base64 format, as returned by TonConnect\nRequired for wallets that are not deployed yet\nexample: te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF...",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF..."
+                },
+                "timestamp": {
+                    "description": "Unix time when the data was signed\nrequired: true\nexample: 1757203200",
+                    "type": "integer",
+                    "example": 1757203200
                 }
             }
         },
-        "dto.JWKSResponseDTO": {
+        "dto.SignDataResponseDTO": {
             "type": "object",
-            "required": [
-                "keys"
-            ],
             "properties": {
-                "keys": {
-                    "description": "Array of JSON Web Keys\nrequired: true",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.JWK"
-                    }
+                "address": {
+                    "description": "Raw address of the wallet that signed the data\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                },
+                "attestation": {
+                    "description": "JWT signed by the service attesting that the wallet approved the payload\nexample: eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9...",
+                    "type": "string",
+                    "example": "eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "expiresAt": {
+                    "description": "Expiration time of the attestation\nexample: 2025-09-08T00:00:00Z",
+                    "type": "string",
+                    "example": "2025-09-08T00:00:00Z"
+                },
+                "payload_hash": {
+                    "description": "Hex encoded hash of the payload: sha256 of text and bytes, the cell hash of cells\nexample: b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+                    "type": "string",
+                    "example": "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+                },
+                "valid": {
+                    "description": "Indicates that the signature verified against the key of the wallet\nrequired: true\nexample: true",
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
         "dto.TokenRequestDTO": {
             "type": "object",
             "required": [
-                "message",
-                "publicKey",
-                "signature"
+                "publicKey"
             ],
             "properties": {
+                "address": {
+                    "description": "Wallet address claimed by the user, required with proof\nIt is checked against the standard wallet contracts of the public key\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                },
+                "client_id": {
+                    "description": "ID of the registered client the wallet connected to, required with proof\nexample: my-dapp",
+                    "type": "string",
+                    "example": "my-dapp"
+                },
                 "message": {
-                    "description": "Original message that was signed\nrequired: true\nexample: TON OAuth challenge message",
+                    "description": "Original message that was signed, required unless proof is provided\nexample: TON OAuth challenge message",
                     "type": "string",
                     "example": "TON OAuth challenge message"
                 },
+                "network": {
+                    "description": "TonConnect chain id of the wallet network, defaults to the client or service network\nexample: -239",
+                    "type": "string",
+                    "enum": [
+                        "-239",
+                        "-3"
+                    ],
+                    "example": "-239"
+                },
+                "pow": {
+                    "description": "Solution of the proof-of-work puzzle of /oauth/authorize, required when proof of work is enabled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.PoWSolutionDTO"
+                        }
+                    ]
+                },
+                "proof": {
+                    "description": "TonConnect ton_proof returned by the wallet",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TonProofDTO"
+                        }
+                    ]
+                },
                 "publicKey": {
                     "description": "Public key of the TON wallet in base64 format\nrequired: true\nexample: dGVzdF9wdWJsaWNfa2V5X2RhdGE=",
                     "type": "string",
+                    "format": "base64",
                     "example": "dGVzdF9wdWJsaWNfa2V5X2RhdGE="
                 },
+                "scope": {
+                    "description": "Space separated scopes requested by the client, available to access policies\nexample: openid profile",
+                    "type": "string",
+                    "example": "openid profile"
+                },
                 "signature": {
-                    "description": "Signature of the message in base64 format\nrequired: true\nexample: c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA==",
+                    "description": "Signature of the message in base64 format, required unless proof is provided\nexample: c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA==",
                     "type": "string",
+                    "format": "base64",
                     "example": "c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA=="
+                },
+                "state_init": {
+                    "description": "StateInit BoC of the wallet in base64 format, as returned by TonConnect\nRequired to log in with a wallet that is not deployed yet\nexample: te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF...",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF..."
                 }
             }
         },
@@ -457,8 +2752,215 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.TonConnectManifestDTO": {
+            "type": "object",
+            "properties": {
+                "iconUrl": {
+                    "description": "URL of the dApp icon\nrequired: true\nexample: https://auth.example.com/clients/my-dapp/icon",
+                    "type": "string",
+                    "example": "https://auth.example.com/clients/my-dapp/icon"
+                },
+                "name": {
+                    "description": "Name of the dApp shown in the wallet\nrequired: true\nexample: Example dApp",
+                    "type": "string",
+                    "example": "Example dApp"
+                },
+                "privacyPolicyUrl": {
+                    "description": "Optional URL of the privacy policy\nexample: https://example.com/privacy",
+                    "type": "string",
+                    "example": "https://example.com/privacy"
+                },
+                "termsOfUseUrl": {
+                    "description": "Optional URL of the terms of use\nexample: https://example.com/terms",
+                    "type": "string",
+                    "example": "https://example.com/terms"
+                },
+                "url": {
+                    "description": "URL of the dApp, its host is expected as the ton_proof domain\nrequired: true\nexample: https://example.com",
+                    "type": "string",
+                    "example": "https://example.com"
+                }
+            }
+        },
+        "dto.TonProofDTO": {
+            "type": "object",
+            "required": [
+                "domain",
+                "payload",
+                "signature",
+                "timestamp"
+            ],
+            "properties": {
+                "domain": {
+                    "description": "Domain of the dApp the wallet connected to\nrequired: true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TonProofDomainDTO"
+                        }
+                    ]
+                },
+                "payload": {
+                    "description": "Payload (challenge) that was signed\nrequired: true\nexample: nonce1234567890",
+                    "type": "string",
+                    "example": "nonce1234567890"
+                },
+                "signature": {
+                    "description": "Signature of the proof in base64 format\nrequired: true\nexample: c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA==",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA=="
+                },
+                "timestamp": {
+                    "description": "Unix time when the proof was signed\nrequired: true\nexample: 1757203200",
+                    "type": "integer",
+                    "example": 1757203200
+                }
+            }
+        },
+        "dto.TonProofDomainDTO": {
+            "type": "object",
+            "required": [
+                "lengthBytes",
+                "value"
+            ],
+            "properties": {
+                "lengthBytes": {
+                    "description": "Length of the domain in bytes\nrequired: true\nexample: 11",
+                    "type": "integer",
+                    "example": 11
+                },
+                "value": {
+                    "description": "Domain value\nrequired: true\nexample: example.com",
+                    "type": "string",
+                    "example": "example.com"
+                }
+            }
+        },
+        "dto.TransactionChallengeDTO": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Address the transfer is sent to\nrequired: true\nexample: EQBvW8Z5huBkMJYdnfAEM5JqTNkuWX3diqYENkWsIL0XggGG",
+                    "type": "string",
+                    "example": "EQBvW8Z5huBkMJYdnfAEM5JqTNkuWX3diqYENkWsIL0XggGG"
+                },
+                "amount": {
+                    "description": "Least amount of the transfer in nanotons\nrequired: true\nexample: 10000000",
+                    "type": "string",
+                    "example": "10000000"
+                },
+                "comment": {
+                    "description": "Text comment the transfer must carry\nrequired: true\nexample: ton-oauth-mfrggzdfmztwq2lk",
+                    "type": "string",
+                    "example": "ton-oauth-mfrggzdfmztwq2lk"
+                },
+                "link": {
+                    "description": "ton:// transfer link with address, amount and comment filled in\nexample: ton://transfer/EQBvW8Z5huBkMJYdnfAEM5JqTNkuWX3diqYENkWsIL0XggGG?amount=10000000\u0026text=ton-oauth-mfrggzdfmztwq2lk",
+                    "type": "string",
+                    "example": "ton://transfer/EQBvW8Z5huBkMJYdnfAEM5JqTNkuWX3diqYENkWsIL0XggGG?amount=10000000\u0026text=ton-oauth-mfrggzdfmztwq2lk"
+                },
+                "network": {
+                    "description": "Network the transfer is expected on\nexample: -239",
+                    "type": "string",
+                    "example": "-239"
+                }
+            }
+        },
+        "dto.TransactionStatusResponseDTO": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "description": "Until when the transfer is awaited, or the approved challenge can be exchanged for a token\nexample: 2025-09-07T00:10:00Z",
+                    "type": "string",
+                    "example": "2025-09-07T00:10:00Z"
+                },
+                "status": {
+                    "description": "pending, approved, expired or used\nrequired: true\nexample: approved",
+                    "type": "string",
+                    "example": "approved"
+                },
+                "txHash": {
+                    "description": "Hash of the transfer transaction, present once approved\nexample: 3f1b0c3c1f7f6c6a5a2f0f0b6f7b5f0e2d4b6a8c0e2f4a6b8c0d2e4f6a8b0c2d",
+                    "type": "string",
+                    "example": "3f1b0c3c1f7f6c6a5a2f0f0b6f7b5f0e2d4b6a8c0e2f4a6b8c0d2e4f6a8b0c2d"
+                },
+                "wallet": {
+                    "description": "Wallet that sent the transfer, present once approved\nexample: 0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869",
+                    "type": "string",
+                    "example": "0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869"
+                }
+            }
+        },
         "dto.VerifyRequestDTO": {
-            "type": "object"
+            "type": "object",
+            "required": [
+                "publicKey"
+            ],
+            "properties": {
+                "address": {
+                    "description": "Wallet address claimed by the user, required with proof\nIt is checked against the standard wallet contracts of the public key\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                },
+                "client_id": {
+                    "description": "ID of the registered client the wallet connected to, required with proof\nexample: my-dapp",
+                    "type": "string",
+                    "example": "my-dapp"
+                },
+                "message": {
+                    "description": "Original message that was signed, required unless proof is provided\nexample: TON OAuth challenge message",
+                    "type": "string",
+                    "example": "TON OAuth challenge message"
+                },
+                "network": {
+                    "description": "TonConnect chain id of the wallet network, defaults to the client or service network\nexample: -239",
+                    "type": "string",
+                    "enum": [
+                        "-239",
+                        "-3"
+                    ],
+                    "example": "-239"
+                },
+                "pow": {
+                    "description": "Solution of the proof-of-work puzzle of /oauth/authorize, required when proof of work is enabled",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.PoWSolutionDTO"
+                        }
+                    ]
+                },
+                "proof": {
+                    "description": "TonConnect ton_proof returned by the wallet",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.TonProofDTO"
+                        }
+                    ]
+                },
+                "publicKey": {
+                    "description": "Public key of the TON wallet in base64 format\nrequired: true\nexample: dGVzdF9wdWJsaWNfa2V5X2RhdGE=",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "dGVzdF9wdWJsaWNfa2V5X2RhdGE="
+                },
+                "scope": {
+                    "description": "Space separated scopes requested by the client, available to access policies\nexample: openid profile",
+                    "type": "string",
+                    "example": "openid profile"
+                },
+                "signature": {
+                    "description": "Signature of the message in base64 format, required unless proof is provided\nexample: c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA==",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA=="
+                },
+                "state_init": {
+                    "description": "StateInit BoC of the wallet in base64 format, as returned by TonConnect\nRequired to log in with a wallet that is not deployed yet\nexample: te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF...",
+                    "type": "string",
+                    "format": "base64",
+                    "example": "te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF..."
+                }
+            }
         },
         "dto.VerifyResponseDTO": {
             "type": "object",
@@ -473,6 +2975,16 @@ const docTemplate = `{
                     "type": "string",
                     "example": "TON OAuth Service"
                 },
+                "name": {
+                    "description": "TON DNS name that resolves back to the wallet\nexample: alice.ton",
+                    "type": "string",
+                    "example": "alice.ton"
+                },
+                "network": {
+                    "description": "TonConnect chain id of the wallet network\nrequired: true\nexample: -239",
+                    "type": "string",
+                    "example": "-239"
+                },
                 "nonce": {
                     "description": "Nonce used in the signed message\nrequired: true\nexample: nonce1234567890",
                     "type": "string",
@@ -483,10 +2995,20 @@ const docTemplate = `{
                     "type": "boolean",
                     "example": true
                 },
+                "version": {
+                    "description": "Version of the wallet contract\nexample: v4r2",
+                    "type": "string",
+                    "example": "v4r2"
+                },
                 "wallet": {
-                    "description": "Wallet address of the signer\nrequired: true\nexample: EQC1234567890abcdef...",
+                    "description": "Wallet address of the signer, rendered in the address format of the client\nrequired: true\nexample: EQC1234567890abcdef...",
                     "type": "string",
                     "example": "EQC1234567890abcdef..."
+                },
+                "walletState": {
+                    "description": "Activation state of the wallet: active, uninit or nonexist\nrequired: true\nexample: active",
+                    "type": "string",
+                    "example": "active"
                 }
             }
         },
@@ -496,6 +3018,11 @@ const docTemplate = `{
                 "jwt"
             ],
             "properties": {
+                "client_id": {
+                    "description": "Client the token must be issued to, checked against its aud claim when set\nexample: my-dapp",
+                    "type": "string",
+                    "example": "my-dapp"
+                },
                 "jwt": {
                     "description": "JWT token string to verify\nrequired: true\nexample: eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9...",
                     "type": "string",
@@ -516,13 +3043,88 @@ const docTemplate = `{
                     "type": "string",
                     "example": "TON OAuth Service"
                 },
+                "network": {
+                    "description": "TonConnect chain id of the wallet network\nexample: -239",
+                    "type": "string",
+                    "example": "-239"
+                },
+                "preferred_username": {
+                    "description": "TON DNS name of the wallet\nexample: alice.ton",
+                    "type": "string",
+                    "example": "alice.ton"
+                },
+                "sub": {
+                    "description": "Raw address of the wallet the token was issued to\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                },
                 "valid": {
                     "description": "Indicates if the token is valid\nrequired: true\nexample: true",
                     "type": "boolean",
                     "example": true
+                },
+                "wallet_state": {
+                    "description": "Activation state of the wallet at login: active, uninit or nonexist\nexample: active",
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "dto.WalletDTO": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Address of the wallet in the address format of the client\nrequired: true\nexample: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8",
+                    "type": "string",
+                    "example": "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+                },
+                "balance": {
+                    "description": "Balance in nanotons\nrequired: true\nexample: 1500000000",
+                    "type": "integer",
+                    "example": 1500000000
+                },
+                "preferred": {
+                    "description": "Indicates the wallet used when no address is passed to verify or token\nrequired: true\nexample: true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "status": {
+                    "description": "Account status: active, uninit, frozen or nonexist\nrequired: true\nexample: active",
+                    "type": "string",
+                    "example": "active"
+                },
+                "version": {
+                    "description": "Version of the wallet contract, empty when unknown\nexample: v4r2",
+                    "type": "string",
+                    "example": "v4r2"
+                }
+            }
+        },
+        "dto.WalletsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "network": {
+                    "description": "TonConnect chain id of the network the wallets were looked up on\nrequired: true\nexample: -239",
+                    "type": "string",
+                    "example": "-239"
+                },
+                "wallets": {
+                    "description": "Candidate wallets, the one used by default first\nrequired: true",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.WalletDTO"
+                    }
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "Admin token as \"Bearer \u003cAdminToken\u003e\".",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
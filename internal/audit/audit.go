@@ -0,0 +1,132 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is an entry of the audit trail.
+type Event struct {
+	Time time.Time `json:"time"`
+	// Actor who caused the event, e.g. the admin named in the request or "system".
+	Actor string `json:"actor"`
+	IP    string `json:"ip,omitempty"`
+	// Action is a dotted name such as "list.add".
+	Action   string                 `json:"action"`
+	Target   string                 `json:"target,omitempty"`
+	ClientID string                 `json:"clientId,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// Log appends events as JSON lines to a file and keeps the latest ones in memory.
+type Log struct {
+	mu     sync.Mutex
+	file   *os.File
+	recent []Event
+	keep   int
+}
+
+// Open opens the audit file for appending and loads its last keep events. Without a
+// path events are only kept in memory.
+func Open(path string, keep int) (*Log, error) {
+	l := &Log{keep: keep}
+	if path == "" {
+		return l, nil
+	}
+
+	if err := l.load(path); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	l.file = f
+	return l, nil
+}
+
+func (l *Log) load(path string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		l.remember(e)
+	}
+	return scanner.Err()
+}
+
+// Record appends the event, setting its time when it is zero.
+func (l *Log) Record(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.remember(e)
+	if l.file == nil {
+		return nil
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = l.file.Write(append(line, '\n'))
+	return err
+}
+
+func (l *Log) remember(e Event) {
+	l.recent = append(l.recent, e)
+	if len(l.recent) > l.keep {
+		l.recent = l.recent[len(l.recent)-l.keep:]
+	}
+}
+
+// Recent returns up to limit of the latest events, newest first, optionally only those
+// whose action has the prefix.
+func (l *Log) Recent(limit int, actionPrefix string) []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := make([]Event, 0, min(limit, len(l.recent)))
+	for i := len(l.recent) - 1; i >= 0 && len(events) < limit; i-- {
+		e := l.recent[i]
+		if actionPrefix != "" && !strings.HasPrefix(e.Action, actionPrefix) {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
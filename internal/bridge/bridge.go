@@ -0,0 +1,300 @@
+package bridge
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidClientID      = errors.New("invalid client id")
+	ErrInvalidTTL           = errors.New("ttl must be positive")
+	ErrTTLTooLarge          = errors.New("ttl exceeds the allowed maximum")
+	ErrTooManyConnections   = errors.New("too many connections for client")
+	ErrMailboxFull          = errors.New("message buffer for recipient is full")
+	ErrEmptyMessage         = errors.New("message is empty")
+	ErrTooManySubscriptions = errors.New("too many client ids in one subscription")
+)
+
+// Config describes the limits the hub enforces.
+type Config struct {
+	// MaxTTL caps the TTL a sender may request for a message.
+	MaxTTL time.Duration
+	// MaxConnections is the number of concurrent event streams per client id.
+	MaxConnections int
+	// BufferSize is the number of undelivered messages kept per client id.
+	BufferSize int
+	// MaxClientIDs is the number of client ids one event stream may listen to.
+	MaxClientIDs int
+}
+
+// Message is a single bridge message waiting for its recipient.
+type Message struct {
+	ID        uint64
+	From      string
+	To        string
+	Topic     string
+	Body      string
+	ExpiresAt time.Time
+}
+
+type mailbox struct {
+	messages    []Message
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription is an open event stream of a wallet or dApp.
+type Subscription struct {
+	clientIDs []string
+	events    chan Message
+	done      chan struct{}
+	once      sync.Once
+}
+
+// Events returns the channel new messages are delivered to.
+func (s *Subscription) Events() <-chan Message {
+	return s.events
+}
+
+// Done is closed when the hub drops the subscription.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Hub buffers messages between TonConnect clients and fans them out to open event streams.
+type Hub struct {
+	cfg    Config
+	mu     sync.Mutex
+	boxes  map[string]*mailbox
+	lastID uint64
+	closed bool
+}
+
+func NewHub(cfg Config) *Hub {
+	return &Hub{
+		cfg:    cfg,
+		boxes:  make(map[string]*mailbox),
+		lastID: uint64(time.Now().UnixMicro()),
+	}
+}
+
+// ParseClientIDs splits a comma separated list of hex encoded client public keys.
+func ParseClientIDs(raw string) ([]string, error) {
+	var ids []string
+	for _, id := range strings.Split(raw, ",") {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if id == "" {
+			continue
+		}
+		if err := validateClientID(id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, ErrInvalidClientID
+	}
+	return ids, nil
+}
+
+func validateClientID(id string) error {
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) != 32 {
+		return ErrInvalidClientID
+	}
+	return nil
+}
+
+// Publish buffers a message for the recipient and pushes it to its open streams.
+func (h *Hub) Publish(from, to string, ttl time.Duration, topic, body string) (uint64, error) {
+	from, to = strings.ToLower(from), strings.ToLower(to)
+	if err := validateClientID(from); err != nil {
+		return 0, err
+	}
+	if err := validateClientID(to); err != nil {
+		return 0, err
+	}
+	if body == "" {
+		return 0, ErrEmptyMessage
+	}
+	if ttl <= 0 {
+		return 0, ErrInvalidTTL
+	}
+	if ttl > h.cfg.MaxTTL {
+		return 0, ErrTTLTooLarge
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	box := h.box(to)
+	box.dropExpired(time.Now())
+	if len(box.messages) >= h.cfg.BufferSize {
+		return 0, ErrMailboxFull
+	}
+
+	h.lastID++
+	msg := Message{
+		ID:        h.lastID,
+		From:      from,
+		To:        to,
+		Topic:     topic,
+		Body:      body,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	box.messages = append(box.messages, msg)
+
+	for sub := range box.subscribers {
+		select {
+		case sub.events <- msg:
+		default:
+			// The stream is not keeping up; it will get the message again after reconnecting.
+			h.unsubscribeLocked(sub)
+		}
+	}
+
+	return msg.ID, nil
+}
+
+// Subscribe opens an event stream for the given client ids. Messages up to lastEventID
+// are treated as delivered and removed from the buffer; the rest are returned as backlog.
+func (h *Hub) Subscribe(clientIDs []string, lastEventID uint64) (*Subscription, []Message, error) {
+	if len(clientIDs) > h.cfg.MaxClientIDs {
+		return nil, nil, ErrTooManySubscriptions
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, id := range clientIDs {
+		if box, ok := h.boxes[id]; ok && len(box.subscribers) >= h.cfg.MaxConnections {
+			return nil, nil, ErrTooManyConnections
+		}
+	}
+
+	sub := &Subscription{
+		clientIDs: clientIDs,
+		events:    make(chan Message, h.cfg.BufferSize),
+		done:      make(chan struct{}),
+	}
+	if h.closed {
+		sub.close()
+		return sub, nil, nil
+	}
+
+	now := time.Now()
+	var backlog []Message
+	for _, id := range clientIDs {
+		box := h.box(id)
+		box.ack(lastEventID)
+		box.dropExpired(now)
+		backlog = append(backlog, box.messages...)
+		box.subscribers[sub] = struct{}{}
+	}
+
+	return sub, backlog, nil
+}
+
+// Unsubscribe closes the stream and releases its connection slots.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.unsubscribeLocked(sub)
+}
+
+func (h *Hub) unsubscribeLocked(sub *Subscription) {
+	for _, id := range sub.clientIDs {
+		box, ok := h.boxes[id]
+		if !ok {
+			continue
+		}
+		delete(box.subscribers, sub)
+		h.releaseIfEmpty(id, box)
+	}
+	sub.close()
+}
+
+// Cleanup drops expired messages and empty mailboxes.
+func (h *Hub) Cleanup() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	for id, box := range h.boxes {
+		box.dropExpired(now)
+		h.releaseIfEmpty(id, box)
+	}
+}
+
+// Run periodically cleans up expired messages until stop is closed.
+func (h *Hub) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.Cleanup()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Close drops every open stream so that long-lived requests finish on shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, box := range h.boxes {
+		for sub := range box.subscribers {
+			sub.close()
+		}
+		box.subscribers = make(map[*Subscription]struct{})
+	}
+}
+
+func (h *Hub) box(id string) *mailbox {
+	box, ok := h.boxes[id]
+	if !ok {
+		box = &mailbox{subscribers: make(map[*Subscription]struct{})}
+		h.boxes[id] = box
+	}
+	return box
+}
+
+func (h *Hub) releaseIfEmpty(id string, box *mailbox) {
+	if len(box.messages) == 0 && len(box.subscribers) == 0 {
+		delete(h.boxes, id)
+	}
+}
+
+func (b *mailbox) ack(lastEventID uint64) {
+	if lastEventID == 0 {
+		return
+	}
+	kept := b.messages[:0]
+	for _, m := range b.messages {
+		if m.ID > lastEventID {
+			kept = append(kept, m)
+		}
+	}
+	b.messages = kept
+}
+
+func (b *mailbox) dropExpired(now time.Time) {
+	kept := b.messages[:0]
+	for _, m := range b.messages {
+		if now.Before(m.ExpiresAt) {
+			kept = append(kept, m)
+		}
+	}
+	b.messages = kept
+}
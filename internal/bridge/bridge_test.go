@@ -0,0 +1,192 @@
+package bridge
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	wallet = strings.Repeat("a", 64)
+	dapp   = strings.Repeat("b", 64)
+	other  = strings.Repeat("c", 64)
+)
+
+func newHub() *Hub {
+	return NewHub(Config{MaxTTL: time.Minute, MaxConnections: 2, BufferSize: 3, MaxClientIDs: 2})
+}
+
+// bodies returns the bodies of the messages in order.
+func bodies(messages []Message) string {
+	var out []string
+	for _, m := range messages {
+		out = append(out, m.Body)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestPublishDelivers(t *testing.T) {
+	h := newHub()
+	sub, backlog, err := h.Subscribe([]string{dapp}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backlog) != 0 {
+		t.Fatalf("backlog = %v, want none", backlog)
+	}
+
+	id, err := h.Publish(strings.ToUpper(wallet), dapp, time.Minute, "sendTransaction", "m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-sub.Events():
+		if msg.ID != id || msg.From != wallet || msg.To != dapp || msg.Topic != "sendTransaction" || msg.Body != "m1" {
+			t.Fatalf("event = %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered to the open stream")
+	}
+
+	// a stream of another client id gets nothing
+	if _, err := h.Publish(wallet, other, time.Minute, "", "m2"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-sub.Events():
+		t.Fatalf("stream received the message of another client: %+v", msg)
+	default:
+	}
+}
+
+func TestPublishErrors(t *testing.T) {
+	h := newHub()
+	tests := []struct {
+		name    string
+		from    string
+		ttl     time.Duration
+		body    string
+		wantErr error
+	}{
+		{"invalid sender", "abc", time.Minute, "m", ErrInvalidClientID},
+		{"empty body", wallet, time.Minute, "", ErrEmptyMessage},
+		{"zero ttl", wallet, 0, "m", ErrInvalidTTL},
+		{"ttl above max", wallet, time.Hour, "m", ErrTTLTooLarge},
+	}
+	for _, tt := range tests {
+		if _, err := h.Publish(tt.from, dapp, tt.ttl, "", tt.body); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Publish() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := h.Publish(wallet, dapp, time.Minute, "", "m"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := h.Publish(wallet, dapp, time.Minute, "", "m"); !errors.Is(err, ErrMailboxFull) {
+		t.Fatalf("Publish() over the buffer error = %v, want %v", err, ErrMailboxFull)
+	}
+}
+
+func TestSubscribeReplay(t *testing.T) {
+	h := newHub()
+	var ids []uint64
+	for _, body := range []string{"m1", "m2", "m3"} {
+		id, err := h.Publish(wallet, dapp, time.Minute, "", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	// a new stream replays the buffered messages
+	sub, backlog, err := h.Subscribe([]string{dapp}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bodies(backlog); got != "m1,m2,m3" {
+		t.Fatalf("backlog = %s, want m1,m2,m3", got)
+	}
+	h.Unsubscribe(sub)
+
+	// reconnecting with last_event_id acknowledges everything up to it
+	sub, backlog, err = h.Subscribe([]string{dapp}, ids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bodies(backlog); got != "m3" {
+		t.Fatalf("backlog after last_event_id = %s, want m3", got)
+	}
+	h.Unsubscribe(sub)
+
+	// acknowledged messages are gone for good and free the buffer
+	_, backlog, err = h.Subscribe([]string{dapp}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bodies(backlog); got != "m3" {
+		t.Fatalf("backlog after reconnect = %s, want m3", got)
+	}
+}
+
+func TestMessageExpiry(t *testing.T) {
+	h := newHub()
+	if _, err := h.Publish(wallet, dapp, 20*time.Millisecond, "", "short"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.Publish(wallet, dapp, time.Minute, "", "long"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	_, backlog, err := h.Subscribe([]string{dapp}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bodies(backlog); got != "long" {
+		t.Fatalf("backlog = %s, want long", got)
+	}
+
+	// cleanup releases the mailbox once its last message expired
+	h = newHub()
+	if _, err := h.Publish(wallet, other, 20*time.Millisecond, "", "short"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	h.Cleanup()
+	if len(h.boxes) != 0 {
+		t.Fatalf("Cleanup() kept %d mailboxes", len(h.boxes))
+	}
+}
+
+func TestSubscribeLimits(t *testing.T) {
+	h := newHub()
+	if _, _, err := h.Subscribe([]string{wallet, dapp, other}, 0); !errors.Is(err, ErrTooManySubscriptions) {
+		t.Fatalf("Subscribe() of three ids error = %v, want %v", err, ErrTooManySubscriptions)
+	}
+
+	first, _, err := h.Subscribe([]string{dapp}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _, err := h.Subscribe([]string{dapp}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := h.Subscribe([]string{dapp}, 0); !errors.Is(err, ErrTooManyConnections) {
+		t.Fatalf("Subscribe() over the connection limit error = %v, want %v", err, ErrTooManyConnections)
+	}
+	h.Unsubscribe(first)
+	if _, _, err := h.Subscribe([]string{dapp}, 0); err != nil {
+		t.Fatalf("Subscribe() after a stream closed error = %v", err)
+	}
+
+	h.Close()
+	select {
+	case <-second.Done():
+	default:
+		t.Fatal("Close() left a stream open")
+	}
+}
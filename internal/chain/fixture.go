@@ -0,0 +1,338 @@
+package chain
+
+import (
+	"TON/pkg/tonwallet"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/xssnick/tonutils-go/tlb"
+)
+
+// FixtureProvider is an in-memory chain used by tests and local development.
+// Unknown accounts are reported as nonexistent.
+type FixtureProvider struct {
+	mu         sync.RWMutex
+	walletOpts tonwallet.Options
+	accounts   map[string]*Account
+	wallets    map[string][]Wallet
+	getMethods map[string][]StackEntry
+	jettons    map[string]*JettonBalance
+	nfts       []NFTItem
+	dns        map[string]string
+	dnsRecords map[string]*DNSRecords
+	txs        map[string][]Transaction
+}
+
+type fixtureFile struct {
+	Accounts   []Account           `json:"accounts"`
+	Wallets    map[string][]Wallet `json:"wallets"`
+	GetMethods []struct {
+		Address string       `json:"address"`
+		Method  string       `json:"method"`
+		Stack   []StackEntry `json:"stack"`
+	} `json:"getMethods"`
+	Jettons []struct {
+		Owner string `json:"owner"`
+		JettonBalance
+	} `json:"jettons"`
+	NFTs []NFTItem         `json:"nfts"`
+	DNS  map[string]string `json:"dns"`
+	// DNSRecords are the site and storage records by domain.
+	DNSRecords map[string]DNSRecords `json:"dnsRecords"`
+	// Transactions by account address.
+	Transactions map[string][]Transaction `json:"transactions"`
+}
+
+func NewFixtureProvider(walletOpts tonwallet.Options) *FixtureProvider {
+	return &FixtureProvider{
+		walletOpts: walletOpts,
+		accounts:   make(map[string]*Account),
+		wallets:    make(map[string][]Wallet),
+		getMethods: make(map[string][]StackEntry),
+		jettons:    make(map[string]*JettonBalance),
+		dns:        make(map[string]string),
+		dnsRecords: make(map[string]*DNSRecords),
+		txs:        make(map[string][]Transaction),
+	}
+}
+
+// LoadFixtureProvider builds a fixture provider from a JSON file.
+func LoadFixtureProvider(path string, walletOpts tonwallet.Options) (*FixtureProvider, error) {
+	p := NewFixtureProvider(walletOpts)
+	if path == "" {
+		return p, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file fixtureFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture file: %w", err)
+	}
+
+	for _, acc := range file.Accounts {
+		p.SetAccount(acc)
+	}
+	for key, wallets := range file.Wallets {
+		pub, err := hex.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %w", key, err)
+		}
+		p.SetWallets(pub, wallets)
+	}
+	for _, m := range file.GetMethods {
+		p.SetGetMethod(m.Address, m.Method, m.Stack)
+	}
+	for _, j := range file.Jettons {
+		p.SetJettonBalance(j.Owner, j.JettonBalance)
+	}
+	for _, nft := range file.NFTs {
+		p.AddNFT(nft)
+	}
+	for domain, addr := range file.DNS {
+		p.SetDNS(domain, addr)
+	}
+	for domain, records := range file.DNSRecords {
+		p.SetDNSRecords(domain, records)
+	}
+	for addr, txs := range file.Transactions {
+		for _, tx := range txs {
+			p.AddTransaction(addr, tx)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *FixtureProvider) SetAccount(acc Account) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	acc.Address = normalizeAddress(acc.Address)
+	p.accounts[acc.Address] = &acc
+}
+
+func (p *FixtureProvider) SetWallets(pubKey ed25519.PublicKey, wallets []Wallet) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range wallets {
+		wallets[i].Address = normalizeAddress(wallets[i].Address)
+	}
+	p.wallets[hex.EncodeToString(pubKey)] = wallets
+}
+
+func (p *FixtureProvider) SetGetMethod(addr, method string, stack []StackEntry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.getMethods[normalizeAddress(addr)+"/"+method] = stack
+}
+
+func (p *FixtureProvider) SetJettonBalance(owner string, balance JettonBalance) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	balance.Master = normalizeAddress(balance.Master)
+	p.jettons[normalizeAddress(owner)+"/"+balance.Master] = &balance
+}
+
+func (p *FixtureProvider) AddNFT(item NFTItem) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	item.Address = normalizeAddress(item.Address)
+	item.Collection = normalizeAddress(item.Collection)
+	item.Owner = normalizeAddress(item.Owner)
+	p.nfts = append(p.nfts, item)
+}
+
+func (p *FixtureProvider) SetDNS(domain, addr string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dns[strings.ToLower(domain)] = normalizeAddress(addr)
+}
+
+func (p *FixtureProvider) SetDNSRecords(domain string, records DNSRecords) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dnsRecords[strings.ToLower(domain)] = &records
+}
+
+// GetWallets returns the wallets set for the key, or the known accounts of its
+// derived standard wallets.
+func (p *FixtureProvider) GetWallets(ctx context.Context, pubKey ed25519.PublicKey) ([]Wallet, error) {
+	p.mu.RLock()
+	wallets, ok := p.wallets[hex.EncodeToString(pubKey)]
+	p.mu.RUnlock()
+	if ok {
+		return append([]Wallet(nil), wallets...), nil
+	}
+	return walletsByDerivation(ctx, p, pubKey, p.walletOpts)
+}
+
+func (p *FixtureProvider) GetAccount(ctx context.Context, addr string) (*Account, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	addr = normalizeAddress(addr)
+	acc, ok := p.accounts[addr]
+	if !ok {
+		return &Account{Address: addr, Status: StatusNonexist}, nil
+	}
+	cp := *acc
+	return &cp, nil
+}
+
+func (p *FixtureProvider) GetBalance(ctx context.Context, addr string) (int64, error) {
+	acc, err := p.GetAccount(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
+	return acc.Balance, nil
+}
+
+func (p *FixtureProvider) RunGetMethod(ctx context.Context, addr, method string, args ...StackEntry) ([]StackEntry, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	stack, ok := p.getMethods[normalizeAddress(addr)+"/"+method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not defined for %s", ErrGetMethodFailed, method, addr)
+	}
+	return append([]StackEntry(nil), stack...), nil
+}
+
+// getMethodName finds the get-method of addr with the given TVM method id.
+func (p *FixtureProvider) getMethodName(addr string, id uint64) (string, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	prefix := normalizeAddress(addr) + "/"
+	for key := range p.getMethods {
+		name, ok := strings.CutPrefix(key, prefix)
+		if ok && tlb.MethodNameHash(name) == id {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func (p *FixtureProvider) GetJettonBalance(ctx context.Context, owner, master string) (*JettonBalance, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	master = normalizeAddress(master)
+	balance, ok := p.jettons[normalizeAddress(owner)+"/"+master]
+	if !ok {
+		return &JettonBalance{Master: master, Amount: new(big.Int)}, nil
+	}
+	cp := *balance
+	return &cp, nil
+}
+
+func (p *FixtureProvider) GetNFTs(ctx context.Context, owner, collection string) ([]NFTItem, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	owner = normalizeAddress(owner)
+	if collection != "" {
+		collection = normalizeAddress(collection)
+	}
+
+	var items []NFTItem
+	for _, item := range p.nfts {
+		if item.Owner != owner {
+			continue
+		}
+		if collection != "" && item.Collection != collection {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// AddTransaction records a transaction of the account. Addresses in it are normalized.
+func (p *FixtureProvider) AddTransaction(addr string, tx Transaction) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if tx.From != "" {
+		tx.From = normalizeAddress(tx.From)
+	}
+	for i := range tx.To {
+		tx.To[i] = normalizeAddress(tx.To[i])
+	}
+	if tx.Jetton != nil && tx.Jetton.Sender != "" {
+		tx.Jetton.Sender = normalizeAddress(tx.Jetton.Sender)
+	}
+	addr = normalizeAddress(addr)
+	txs := append(p.txs[addr], tx)
+	sort.Slice(txs, func(i, j int) bool { return txs[i].LT > txs[j].LT })
+	p.txs[addr] = txs
+}
+
+func (p *FixtureProvider) GetTransactions(ctx context.Context, addr string, before *Transaction, limit int) ([]Transaction, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	txs := []Transaction{}
+	for _, tx := range p.txs[normalizeAddress(addr)] {
+		if len(txs) == limit {
+			break
+		}
+		if before == nil || tx.LT < before.LT {
+			txs = append(txs, tx)
+		}
+	}
+	return txs, nil
+}
+
+func (p *FixtureProvider) ResolveDNS(ctx context.Context, domain string) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	addr, ok := p.dns[strings.ToLower(domain)]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return addr, nil
+}
+
+// DNSRecords returns the records set for the domain, empty records for domains that
+// only have a wallet.
+func (p *FixtureProvider) DNSRecords(ctx context.Context, domain string) (*DNSRecords, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	domain = strings.ToLower(domain)
+	if records, ok := p.dnsRecords[domain]; ok {
+		r := *records
+		return &r, nil
+	}
+	if _, ok := p.dns[domain]; ok {
+		return &DNSRecords{}, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (p *FixtureProvider) ReverseDNS(ctx context.Context, addr string) ([]string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	addr = normalizeAddress(addr)
+	var domains []string
+	for domain, target := range p.dns {
+		if target == addr {
+			domains = append(domains, domain)
+		}
+	}
+	sort.Strings(domains)
+	return domains, nil
+}
@@ -0,0 +1,351 @@
+package chain
+
+import (
+	"TON/pkg/tonwallet"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+var (
+	ErrGetMethodFailed = errors.New("get-method failed")
+	ErrUnknownWallet   = errors.New("unknown wallet contract")
+)
+
+// AddressEntry builds a slice stack entry holding an address.
+func AddressEntry(addr string) (StackEntry, error) {
+	a, err := ParseAddress(addr)
+	if err != nil {
+		return StackEntry{}, fmt.Errorf("invalid address: %w", err)
+	}
+	b := cell.BeginCell()
+	if err := b.StoreAddr(a); err != nil {
+		return StackEntry{}, err
+	}
+	return StackEntry{Type: StackSlice, Cell: b.EndCell().ToBOC()}, nil
+}
+
+// Int returns the numeric value of the entry.
+func (e StackEntry) Int() (*big.Int, error) {
+	if e.Type != StackNum || e.Num == nil {
+		return nil, fmt.Errorf("stack entry is %s, not a number", e.Type)
+	}
+	return e.Num, nil
+}
+
+// ToCell parses the BoC of a cell or slice entry.
+func (e StackEntry) ToCell() (*cell.Cell, error) {
+	if e.Type != StackCell && e.Type != StackSlice {
+		return nil, fmt.Errorf("stack entry is %s, not a cell", e.Type)
+	}
+	return cell.FromBOC(e.Cell)
+}
+
+// Address loads an address from a cell or slice entry.
+func (e StackEntry) Address() (string, error) {
+	c, err := e.ToCell()
+	if err != nil {
+		return "", err
+	}
+	addr, err := c.BeginParse().LoadAddr()
+	if err != nil {
+		return "", err
+	}
+	return addr.StringRaw(), nil
+}
+
+// PublicKey runs get_public_key on a wallet contract.
+func PublicKey(ctx context.Context, p ChainProvider, addr string) (ed25519.PublicKey, error) {
+	res, err := p.RunGetMethod(ctx, addr, "get_public_key")
+	if err != nil {
+		return nil, err
+	}
+	if len(res) < 1 {
+		return nil, fmt.Errorf("%w: get_public_key returned an empty stack", ErrGetMethodFailed)
+	}
+	n, err := res[0].Int()
+	if err != nil {
+		return nil, err
+	}
+	if n.Sign() < 0 || n.BitLen() > 256 {
+		return nil, fmt.Errorf("%w: get_public_key returned an invalid key", ErrGetMethodFailed)
+	}
+	return n.FillBytes(make([]byte, ed25519.PublicKeySize)), nil
+}
+
+// Seqno runs seqno on a wallet contract.
+func Seqno(ctx context.Context, p ChainProvider, addr string) (uint64, error) {
+	res, err := p.RunGetMethod(ctx, addr, "seqno")
+	if err != nil {
+		return 0, err
+	}
+	if len(res) < 1 {
+		return 0, fmt.Errorf("%w: seqno returned an empty stack", ErrGetMethodFailed)
+	}
+	n, err := res[0].Int()
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("%w: seqno returned an invalid value", ErrGetMethodFailed)
+	}
+	return n.Uint64(), nil
+}
+
+// WalletPublicKey returns the key that controls a deployed wallet and the wallet version
+// detected from its code. The key is read with get_public_key and, when the provider
+// cannot run it, from the contract data of the supported wallet versions.
+func WalletPublicKey(ctx context.Context, p ChainProvider, acc *Account) (ed25519.PublicKey, tonwallet.Version, error) {
+	if acc.Status != StatusActive {
+		return nil, "", fmt.Errorf("%w: account is %s", ErrUnknownWallet, acc.Status)
+	}
+
+	var version tonwallet.Version
+	if len(acc.Code) > 0 {
+		code, err := cell.FromBOC(acc.Code)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid account code: %w", err)
+		}
+		version, _ = tonwallet.VersionByCode(code)
+	}
+
+	key, err := PublicKey(ctx, p, acc.Address)
+	if err == nil {
+		return key, version, nil
+	}
+	if !errors.Is(err, ErrGetMethodFailed) && !errors.Is(err, ErrNotSupported) {
+		return nil, "", err
+	}
+
+	if version == "" || len(acc.Data) == 0 {
+		return nil, "", ErrUnknownWallet
+	}
+	data, err := cell.FromBOC(acc.Data)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid account data: %w", err)
+	}
+	key, err = tonwallet.PublicKeyFromData(version, data)
+	if err != nil {
+		return nil, "", err
+	}
+	return key, version, nil
+}
+
+// walletsByDerivation finds the deployed or funded standard wallets of a key for
+// providers without a public key index.
+func walletsByDerivation(ctx context.Context, p ChainProvider, pubKey ed25519.PublicKey, opts tonwallet.Options) ([]Wallet, error) {
+	derived, err := tonwallet.Derive(pubKey, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var wallets []Wallet
+	for _, w := range derived {
+		acc, err := p.GetAccount(ctx, w.Address.StringRaw())
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if acc.Status == StatusNonexist {
+			continue
+		}
+		wallets = append(wallets, Wallet{
+			Address: w.Address.StringRaw(),
+			Version: string(w.Version),
+			Balance: acc.Balance,
+			Status:  acc.Status,
+		})
+	}
+	return wallets, nil
+}
+
+// jettonBalanceByGetMethods reads a jetton balance with get_wallet_address on the master
+// and get_wallet_data on the owner jetton wallet.
+func jettonBalanceByGetMethods(ctx context.Context, p ChainProvider, owner, master string) (*JettonBalance, error) {
+	arg, err := AddressEntry(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.RunGetMethod(ctx, master, "get_wallet_address", arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jetton wallet address: %w", err)
+	}
+	if len(res) < 1 {
+		return nil, fmt.Errorf("%w: get_wallet_address returned an empty stack", ErrGetMethodFailed)
+	}
+	jettonWallet, err := res[0].Address()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jetton wallet address: %w", err)
+	}
+
+	balance := &JettonBalance{
+		Master: normalizeAddress(master),
+		Wallet: jettonWallet,
+		Amount: new(big.Int),
+	}
+
+	acc, err := p.GetAccount(ctx, jettonWallet)
+	if errors.Is(err, ErrNotFound) {
+		return balance, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if acc.Status != StatusActive {
+		return balance, nil
+	}
+
+	res, err = p.RunGetMethod(ctx, jettonWallet, "get_wallet_data")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jetton wallet data: %w", err)
+	}
+	if len(res) < 1 {
+		return nil, fmt.Errorf("%w: get_wallet_data returned an empty stack", ErrGetMethodFailed)
+	}
+	amount, err := res[0].Int()
+	if err != nil {
+		return nil, err
+	}
+	balance.Amount = amount
+
+	return balance, nil
+}
+
+// MainnetDNSRoot is the root TON DNS resolver of the mainnet.
+const MainnetDNSRoot = "-1:e56754f83426f69b09267bd876ac97c44821345b7e266bd956a7bfbfb98df35c"
+
+const (
+	dnsCategoryNextResolver = 0xba93
+	dnsCategoryContractAddr = 0x9fd3
+	dnsCategoryADNLAddr     = 0xad01
+	dnsCategoryStorageAddr  = 0x7473
+)
+
+// resolveDNSByGetMethods walks the dnsresolve chain starting at root and returns the
+// wallet record of the domain.
+func resolveDNSByGetMethods(ctx context.Context, p ChainProvider, root, domain string) (string, error) {
+	tag, s, err := resolveDNSRecord(ctx, p, root, domain, "wallet")
+	if err != nil {
+		return "", err
+	}
+	if tag != dnsCategoryContractAddr {
+		return "", ErrNotFound
+	}
+	addr, err := s.LoadAddr()
+	if err != nil {
+		return "", err
+	}
+	return addr.StringRaw(), nil
+}
+
+// dnsRecordsByGetMethods reads the site and storage records of the domain with
+// dnsresolve. Domains without such records yield empty records.
+func dnsRecordsByGetMethods(ctx context.Context, p ChainProvider, root, domain string) (*DNSRecords, error) {
+	records := &DNSRecords{}
+	for _, category := range []string{"site", "storage"} {
+		tag, s, err := resolveDNSRecord(ctx, p, root, domain, category)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tag {
+		case dnsCategoryADNLAddr:
+			adnl, err := s.LoadSlice(256)
+			if err != nil {
+				return nil, err
+			}
+			records.Sites = append(records.Sites, hex.EncodeToString(adnl))
+		case dnsCategoryStorageAddr:
+			bag, err := s.LoadSlice(256)
+			if err != nil {
+				return nil, err
+			}
+			records.Storage = hex.EncodeToString(bag)
+		}
+	}
+	return records, nil
+}
+
+// resolveDNSRecord walks the dnsresolve chain starting at root and returns the tag of
+// the domain record in the category and the rest of the record.
+func resolveDNSRecord(ctx context.Context, p ChainProvider, root, domain, category string) (uint64, *cell.Slice, error) {
+	if root == "" {
+		return 0, nil, ErrNotSupported
+	}
+
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(domain, ".")), ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	name := []byte(strings.Join(labels, "\x00") + "\x00")
+
+	resolver := root
+	for hops := 0; hops < 8; hops++ {
+		b := cell.BeginCell()
+		if err := b.StoreSlice(name, uint(len(name)*8)); err != nil {
+			return 0, nil, err
+		}
+		arg := StackEntry{Type: StackSlice, Cell: b.EndCell().ToBOC()}
+
+		res, err := p.RunGetMethod(ctx, resolver, "dnsresolve", arg, StackEntry{Type: StackNum, Num: dnsCategory(category)})
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to run dnsresolve: %w", err)
+		}
+		if len(res) < 2 {
+			return 0, nil, fmt.Errorf("%w: dnsresolve returned a short stack", ErrGetMethodFailed)
+		}
+		bits, err := res[0].Int()
+		if err != nil {
+			return 0, nil, err
+		}
+		if res[1].Type == StackNull {
+			return 0, nil, ErrNotFound
+		}
+		record, err := res[1].ToCell()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		resolved := int(bits.Int64() / 8)
+		s := record.BeginParse()
+		tag, err := s.LoadUInt(16)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if resolved < len(name) {
+			if tag != dnsCategoryNextResolver {
+				return 0, nil, fmt.Errorf("unexpected dns record category %x", tag)
+			}
+			next, err := s.LoadAddr()
+			if err != nil {
+				return 0, nil, err
+			}
+			resolver = next.StringRaw()
+			name = name[resolved:]
+			continue
+		}
+
+		return tag, s, nil
+	}
+
+	return 0, nil, errors.New("too many dns resolver hops")
+}
+
+func dnsCategory(name string) *big.Int {
+	h := sha256.Sum256([]byte(name))
+	return new(big.Int).SetBytes(h[:])
+}
@@ -0,0 +1,88 @@
+package chain
+
+import (
+	tonaddr "TON/pkg/address"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/xssnick/tonutils-go/address"
+)
+
+type httpClient struct {
+	baseURL string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (c *httpClient) getJSON(ctx context.Context, path string, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, nil, out)
+}
+
+func (c *httpClient) postJSON(ctx context.Context, path string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, path, body, out)
+}
+
+func (c *httpClient) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("provider returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ParseAddress parses an address in any form supported by pkg/address into the
+// tonutils representation, keeping its bounceable and testnet flags.
+func ParseAddress(s string) (*address.Address, error) {
+	a, err := tonaddr.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	addr := address.NewAddress(0, byte(a.Workchain), a.Hash[:])
+	addr.SetBounce(a.Bounceable)
+	addr.SetTestnetOnly(a.Testnet)
+	return addr, nil
+}
+
+// normalizeAddress converts an address to the raw form, leaving unparsable values as is.
+func normalizeAddress(s string) string {
+	raw, err := tonaddr.Normalize(s)
+	if err != nil {
+		return s
+	}
+	return raw
+}
@@ -0,0 +1,329 @@
+package chain
+
+import (
+	"TON/pkg/tonwallet"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// ProofCheck selects how much of the lite-server responses is verified.
+type ProofCheck string
+
+const (
+	// ProofCheckSecure verifies account proofs and the masterchain block chain
+	// starting from the init block of the global config.
+	ProofCheckSecure ProofCheck = "secure"
+	// ProofCheckFast verifies account proofs against the masterchain block the server reports.
+	ProofCheckFast ProofCheck = "fast"
+	// ProofCheckUnsafe trusts the server, required for stand-in servers without real proofs.
+	ProofCheckUnsafe ProofCheck = "unsafe"
+)
+
+func (p ProofCheck) policy() (ton.ProofCheckPolicy, error) {
+	switch p {
+	case ProofCheckSecure, "":
+		return ton.ProofCheckPolicySecure, nil
+	case ProofCheckFast:
+		return ton.ProofCheckPolicyFast, nil
+	case ProofCheckUnsafe:
+		return ton.ProofCheckPolicyUnsafe, nil
+	default:
+		return 0, fmt.Errorf("unknown proof check policy %q", p)
+	}
+}
+
+// LiteServerProvider reads the chain directly from TON lite-servers over ADNL.
+// Lite-servers have no indexes, so wallets are derived locally, jettons and DNS are
+// resolved through get-methods and NFT and reverse DNS lookups are not supported.
+type LiteServerProvider struct {
+	pool       *liteclient.ConnectionPool
+	api        ton.APIClientWrapped
+	walletOpts tonwallet.Options
+	dnsRoot    string
+}
+
+// NewLiteServerProvider connects to the lite-servers of a global config, given as a
+// file path or an http(s) URL.
+func NewLiteServerProvider(globalConfig string, proofCheck ProofCheck, timeout time.Duration, walletOpts tonwallet.Options, dnsRoot string) (*LiteServerProvider, error) {
+	policy, err := proofCheck.policy()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*timeout)
+	defer cancel()
+
+	var cfg *liteclient.GlobalConfig
+	if strings.HasPrefix(globalConfig, "http://") || strings.HasPrefix(globalConfig, "https://") {
+		cfg, err = liteclient.GetConfigFromUrl(ctx, globalConfig)
+	} else {
+		cfg, err = liteclient.GetConfigFromFile(globalConfig)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load global config: %w", err)
+	}
+
+	pool := liteclient.NewConnectionPool()
+	if err := pool.AddConnectionsFromConfig(ctx, cfg); err != nil {
+		return nil, fmt.Errorf("failed to connect to lite-servers: %w", err)
+	}
+
+	client := ton.NewAPIClient(pool, policy)
+	if policy == ton.ProofCheckPolicySecure {
+		client.SetTrustedBlockFromConfig(cfg)
+	}
+
+	return &LiteServerProvider{
+		pool:       pool,
+		api:        client.WithTimeout(timeout).WithRetry(2),
+		walletOpts: walletOpts,
+		dnsRoot:    dnsRoot,
+	}, nil
+}
+
+// Close drops the lite-server connections.
+func (p *LiteServerProvider) Close() error {
+	p.pool.Stop()
+	return nil
+}
+
+func (p *LiteServerProvider) GetWallets(ctx context.Context, pubKey ed25519.PublicKey) ([]Wallet, error) {
+	return walletsByDerivation(ctx, p, pubKey, p.walletOpts)
+}
+
+func (p *LiteServerProvider) GetAccount(ctx context.Context, addr string) (*Account, error) {
+	a, err := ParseAddress(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	ctx = p.pool.StickyContext(ctx)
+	block, err := p.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get masterchain block: %w", err)
+	}
+
+	state, err := p.api.GetAccount(ctx, block, a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account state: %w", err)
+	}
+
+	acc := &Account{Address: a.StringRaw(), Status: StatusNonexist}
+	if !state.IsActive || state.State == nil {
+		return acc, nil
+	}
+
+	acc.Balance = state.State.Balance.Nano().Int64()
+	switch state.State.Status {
+	case tlb.AccountStatusActive:
+		acc.Status = StatusActive
+		if state.Code != nil {
+			acc.Code = state.Code.ToBOC()
+		}
+		if state.Data != nil {
+			acc.Data = state.Data.ToBOC()
+		}
+	case tlb.AccountStatusFrozen:
+		acc.Status = StatusFrozen
+	default:
+		acc.Status = StatusUninit
+	}
+	return acc, nil
+}
+
+func (p *LiteServerProvider) GetBalance(ctx context.Context, addr string) (int64, error) {
+	acc, err := p.GetAccount(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
+	return acc.Balance, nil
+}
+
+func (p *LiteServerProvider) RunGetMethod(ctx context.Context, addr, method string, args ...StackEntry) ([]StackEntry, error) {
+	a, err := ParseAddress(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	params := make([]any, 0, len(args))
+	for _, arg := range args {
+		param, err := toTVMValue(arg)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+
+	ctx = p.pool.StickyContext(ctx)
+	block, err := p.api.CurrentMasterchainInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get masterchain block: %w", err)
+	}
+
+	res, err := p.api.RunGetMethod(ctx, block, a, method, params...)
+	if err != nil {
+		var execErr ton.ContractExecError
+		if errors.As(err, &execErr) {
+			return nil, fmt.Errorf("%w: %s exited with code %d", ErrGetMethodFailed, method, execErr.Code)
+		}
+		return nil, err
+	}
+
+	values := res.AsTuple()
+	stack := make([]StackEntry, 0, len(values))
+	for _, v := range values {
+		entry, err := fromTVMValue(v)
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, entry)
+	}
+	return stack, nil
+}
+
+func toTVMValue(e StackEntry) (any, error) {
+	switch e.Type {
+	case StackNum:
+		return e.Num, nil
+	case StackCell:
+		return cell.FromBOC(e.Cell)
+	case StackSlice:
+		c, err := cell.FromBOC(e.Cell)
+		if err != nil {
+			return nil, err
+		}
+		return c.BeginParse(), nil
+	case StackNull:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported argument type %s", e.Type)
+	}
+}
+
+func fromTVMValue(v any) (StackEntry, error) {
+	switch val := v.(type) {
+	case *big.Int:
+		return StackEntry{Type: StackNum, Num: val}, nil
+	case *cell.Cell:
+		return StackEntry{Type: StackCell, Cell: val.ToBOC()}, nil
+	case *cell.Slice:
+		c, err := val.ToCell()
+		if err != nil {
+			return StackEntry{}, err
+		}
+		return StackEntry{Type: StackSlice, Cell: c.ToBOC()}, nil
+	case *cell.Builder:
+		return StackEntry{Type: StackCell, Cell: val.EndCell().ToBOC()}, nil
+	default:
+		// Tuples are not used by the get-methods the service calls.
+		return StackEntry{Type: StackNull}, nil
+	}
+}
+
+func (p *LiteServerProvider) GetJettonBalance(ctx context.Context, owner, master string) (*JettonBalance, error) {
+	return jettonBalanceByGetMethods(ctx, p, owner, master)
+}
+
+func (p *LiteServerProvider) GetNFTs(ctx context.Context, owner, collection string) ([]NFTItem, error) {
+	return nil, ErrNotSupported
+}
+
+// liteServerTxPage is the most transactions a lite-server returns in one response.
+const liteServerTxPage = 16
+
+func (p *LiteServerProvider) GetTransactions(ctx context.Context, addr string, before *Transaction, limit int) ([]Transaction, error) {
+	a, err := ParseAddress(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+	ctx = p.pool.StickyContext(ctx)
+
+	// the listing starts with the transaction at lt and hash, which is dropped when it is before
+	var lt uint64
+	var hash []byte
+	if before != nil {
+		lt = before.LT
+		if hash, err = hex.DecodeString(before.Hash); err != nil {
+			return nil, fmt.Errorf("invalid transaction hash %q", before.Hash)
+		}
+	} else {
+		block, err := p.api.CurrentMasterchainInfo(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get masterchain block: %w", err)
+		}
+		state, err := p.api.GetAccount(ctx, block, a)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account state: %w", err)
+		}
+		lt, hash = state.LastTxLT, state.LastTxHash
+	}
+
+	txs := []Transaction{}
+	for lt != 0 && len(txs) < limit {
+		n := min(limit-len(txs)+1, liteServerTxPage)
+		list, err := p.api.ListTransactions(ctx, a, uint32(n), lt, hash)
+		if errors.Is(err, ton.ErrNoTransactionsWereFound) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list transactions: %w", err)
+		}
+
+		// lite-servers return the oldest transaction first
+		for i := len(list) - 1; i >= 0 && len(txs) < limit; i-- {
+			if before != nil && list[i].LT >= before.LT {
+				continue
+			}
+			txs = append(txs, fromTLBTransaction(list[i]))
+		}
+		lt, hash = list[0].PrevTxLT, list[0].PrevTxHash
+	}
+	return txs, nil
+}
+
+func fromTLBTransaction(t *tlb.Transaction) Transaction {
+	tx := Transaction{
+		Hash: hex.EncodeToString(t.Hash),
+		LT:   t.LT,
+		Time: time.Unix(int64(t.Now), 0).UTC(),
+	}
+	if in := t.IO.In; in != nil && in.MsgType == tlb.MsgTypeInternal {
+		msg := in.AsInternal()
+		tx.From = msg.SrcAddr.StringRaw()
+		tx.Value = msg.Amount.Nano().Int64()
+		tx.Comment = msg.Comment()
+		tx.Jetton = jettonNotification(msg.Body)
+	}
+	if t.IO.Out != nil {
+		out, _ := t.IO.Out.ToSlice()
+		for _, m := range out {
+			if m.MsgType == tlb.MsgTypeInternal {
+				tx.To = append(tx.To, m.AsInternal().DstAddr.StringRaw())
+			}
+		}
+	}
+	return tx
+}
+
+func (p *LiteServerProvider) ResolveDNS(ctx context.Context, domain string) (string, error) {
+	return resolveDNSByGetMethods(ctx, p, p.dnsRoot, domain)
+}
+
+func (p *LiteServerProvider) DNSRecords(ctx context.Context, domain string) (*DNSRecords, error) {
+	return dnsRecordsByGetMethods(ctx, p, p.dnsRoot, domain)
+}
+
+func (p *LiteServerProvider) ReverseDNS(ctx context.Context, addr string) ([]string, error) {
+	return nil, ErrNotSupported
+}
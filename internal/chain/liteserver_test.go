@@ -0,0 +1,147 @@
+package chain
+
+import (
+	"TON/pkg/tonwallet"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	sampleFixture = "../../conf/chain_fixture.json"
+	sampleWallet  = "0:960ab627408d5472d9d125b667cbe00ce17eeaa44e9dc6a86e93cdfef2c480d5"
+	sampleKey     = "a7a90c382278cf441d29db4abcbbe87e55e626f8ff826c36ea2cf1e089869d8b"
+	sampleUninit  = "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+)
+
+// startStandIn serves the sample fixture from a stand-in lite-server and connects a
+// LiteServerProvider to it.
+func startStandIn(t *testing.T) *LiteServerProvider {
+	t.Helper()
+
+	fixture, err := LoadFixtureProvider(sampleFixture, tonwallet.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	server := NewStandInLiteServer(key, fixture)
+	go func() { _ = server.Listen(addr) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	globalConfig, err := server.GlobalConfig(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(globalConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "global.config.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the server starts listening in the background
+	var provider *LiteServerProvider
+	for i := 0; i < 20; i++ {
+		provider, err = NewLiteServerProvider(path, ProofCheckUnsafe, 2*time.Second, tonwallet.Options{}, "")
+		if err == nil {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = provider.Close() })
+	return provider
+}
+
+func TestLiteServerProviderGetAccount(t *testing.T) {
+	p := startStandIn(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		addr    string
+		status  AccountStatus
+		balance int64
+	}{
+		{sampleWallet, StatusActive, 2500000000},
+		{sampleUninit, StatusUninit, 50000000},
+		{"0:1111111111111111111111111111111111111111111111111111111111111111", StatusNonexist, 0},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			acc, err := p.GetAccount(ctx, tt.addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if acc.Address != tt.addr || acc.Status != tt.status || acc.Balance != tt.balance {
+				t.Fatalf("GetAccount() = %s %s %d, want %s %s %d", acc.Address, acc.Status, acc.Balance, tt.addr, tt.status, tt.balance)
+			}
+			if tt.status == StatusActive && (len(acc.Code) == 0 || len(acc.Data) == 0) {
+				t.Fatal("GetAccount() returned an active account without code and data")
+			}
+		})
+	}
+}
+
+func TestLiteServerProviderGetMethods(t *testing.T) {
+	p := startStandIn(t)
+	ctx := context.Background()
+	want, _ := hex.DecodeString(sampleKey)
+
+	key, err := PublicKey(ctx, p, sampleWallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, want) {
+		t.Fatalf("get_public_key = %x, want %x", key, want)
+	}
+
+	res, err := p.RunGetMethod(ctx, sampleWallet, "seqno")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Type != StackNum || res[0].Num.Sign() != 0 {
+		t.Fatalf("seqno = %+v, want 0", res)
+	}
+
+	if _, err := p.RunGetMethod(ctx, sampleWallet, "get_subwallet_id"); !errors.Is(err, ErrGetMethodFailed) {
+		t.Fatalf("undefined get-method error = %v, want %v", err, ErrGetMethodFailed)
+	}
+	if _, err := p.RunGetMethod(ctx, sampleUninit, "seqno"); !errors.Is(err, ErrGetMethodFailed) {
+		t.Fatalf("get-method of an uninit account error = %v, want %v", err, ErrGetMethodFailed)
+	}
+
+	acc, err := p.GetAccount(ctx, sampleWallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, version, err := WalletPublicKey(ctx, p, acc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != tonwallet.V4R2 || !bytes.Equal(key, want) {
+		t.Fatalf("WalletPublicKey() = %x %s, want %x %s", key, version, want, tonwallet.V4R2)
+	}
+}
@@ -0,0 +1,56 @@
+package chain
+
+import (
+	"TON/pkg/tonwallet"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrNetworkNotSupported = errors.New("network is not supported")
+
+// Network is a TON network identified by its TonConnect chain id.
+type Network string
+
+const (
+	Mainnet Network = "-239"
+	Testnet Network = "-3"
+)
+
+// ParseNetwork accepts a TonConnect chain id or the network name.
+func ParseNetwork(s string) (Network, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case string(Mainnet), "mainnet":
+		return Mainnet, nil
+	case string(Testnet), "testnet":
+		return Testnet, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrNetworkNotSupported, s)
+	}
+}
+
+// GlobalID returns the global id W5 wallets embed into their wallet id.
+func (n Network) GlobalID() int32 {
+	if n == Testnet {
+		return tonwallet.TestnetGlobalID
+	}
+	return tonwallet.MainnetGlobalID
+}
+
+func (n Network) Name() string {
+	if n == Testnet {
+		return "testnet"
+	}
+	return "mainnet"
+}
+
+// Providers holds the chain provider of every enabled network.
+type Providers map[Network]ChainProvider
+
+func (p Providers) Get(n Network) (ChainProvider, error) {
+	provider, ok := p[n]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNetworkNotSupported, n.Name())
+	}
+	return provider, nil
+}
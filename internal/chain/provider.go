@@ -0,0 +1,174 @@
+package chain
+
+import (
+	"TON/pkg/tonwallet"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrNotSupported = errors.New("operation is not supported by the chain provider")
+)
+
+type AccountStatus string
+
+const (
+	StatusActive   AccountStatus = "active"
+	StatusUninit   AccountStatus = "uninit"
+	StatusNonexist AccountStatus = "nonexist"
+	StatusFrozen   AccountStatus = "frozen"
+)
+
+// Wallet is a wallet contract controlled by a public key.
+type Wallet struct {
+	Address string        `json:"address"`
+	Version string        `json:"version"`
+	Balance int64         `json:"balance"`
+	Status  AccountStatus `json:"status"`
+}
+
+// Account is the state of an account. Code and Data are BoC encoded and empty
+// unless the account is active.
+type Account struct {
+	Address string        `json:"address"`
+	Status  AccountStatus `json:"status"`
+	Balance int64         `json:"balance"`
+	Code    []byte        `json:"code,omitempty"`
+	Data    []byte        `json:"data,omitempty"`
+}
+
+// StackEntry is a TVM stack value passed to or returned from a get-method.
+// Cell holds a BoC for the cell and slice types.
+type StackEntry struct {
+	Type string   `json:"type"`
+	Num  *big.Int `json:"num,omitempty"`
+	Cell []byte   `json:"cell,omitempty"`
+}
+
+const (
+	StackNum   = "num"
+	StackCell  = "cell"
+	StackSlice = "slice"
+	StackNull  = "null"
+)
+
+type JettonBalance struct {
+	Master   string   `json:"master"`
+	Wallet   string   `json:"wallet,omitempty"`
+	Symbol   string   `json:"symbol,omitempty"`
+	Decimals int      `json:"decimals,omitempty"`
+	Amount   *big.Int `json:"amount"`
+}
+
+type NFTItem struct {
+	Address    string            `json:"address"`
+	Collection string            `json:"collection"`
+	Owner      string            `json:"owner"`
+	Name       string            `json:"name,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Transaction is a transaction of an account, described by its incoming message
+// and the destinations of its outgoing messages.
+type Transaction struct {
+	// Hash of the transaction in hex.
+	Hash string    `json:"hash"`
+	LT   uint64    `json:"lt"`
+	Time time.Time `json:"time"`
+	// From is the sender of the incoming internal message, empty for external messages.
+	From string `json:"from,omitempty"`
+	// Value of the incoming internal message in nanotons.
+	Value int64 `json:"value,omitempty"`
+	// Comment is the text comment of the incoming internal message.
+	Comment string `json:"comment,omitempty"`
+	// To lists the destinations of the outgoing internal messages.
+	To []string `json:"to,omitempty"`
+	// Jetton is set when the incoming message is a jetton transfer notification.
+	Jetton *JettonTransfer `json:"jetton,omitempty"`
+}
+
+// DNSRecords are the site and storage records of a TON DNS domain.
+type DNSRecords struct {
+	// Sites are the ADNL addresses of the site record in hex.
+	Sites []string `json:"sites,omitempty"`
+	// Storage is the TON Storage bag ID in hex, of the storage record or of a site
+	// hosted in TON Storage.
+	Storage string `json:"storage,omitempty"`
+}
+
+// ChainProvider reads the TON blockchain state needed for wallet login.
+// Addresses are accepted in any form and returned in the raw "wc:hex" form.
+type ChainProvider interface {
+	// GetWallets returns the wallet contracts controlled by the public key.
+	GetWallets(ctx context.Context, pubKey ed25519.PublicKey) ([]Wallet, error)
+	GetAccount(ctx context.Context, addr string) (*Account, error)
+	GetBalance(ctx context.Context, addr string) (int64, error)
+	RunGetMethod(ctx context.Context, addr, method string, args ...StackEntry) ([]StackEntry, error)
+	// GetJettonBalance returns the owner balance of the jetton master, zero if the owner has no jetton wallet.
+	GetJettonBalance(ctx context.Context, owner, master string) (*JettonBalance, error)
+	// GetNFTs returns the items owned by owner, limited to the collection when it is not empty.
+	GetNFTs(ctx context.Context, owner, collection string) ([]NFTItem, error)
+	// GetTransactions returns up to limit transactions of the account older than before,
+	// newest first. A nil before starts with the latest transaction.
+	GetTransactions(ctx context.Context, addr string, before *Transaction, limit int) ([]Transaction, error)
+	// ResolveDNS returns the wallet a .ton or .t.me domain points to.
+	ResolveDNS(ctx context.Context, domain string) (string, error)
+	// ReverseDNS returns the domains owned by the address.
+	ReverseDNS(ctx context.Context, addr string) ([]string, error)
+	// DNSRecords returns the site and storage records of a domain.
+	DNSRecords(ctx context.Context, domain string) (*DNSRecords, error)
+}
+
+type Kind string
+
+const (
+	KindTonAPI      Kind = "tonapi"
+	KindToncenterV2 Kind = "toncenter-v2"
+	KindToncenterV3 Kind = "toncenter-v3"
+	KindFixture     Kind = "fixture"
+	KindLiteServer  Kind = "liteserver"
+)
+
+type Config struct {
+	Kind          Kind
+	Network       Network
+	URL           string
+	APIKey        string
+	FixturePath   string
+	GlobalConfig  string
+	ProofCheck    ProofCheck
+	DNSRoot       string
+	Timeout       time.Duration
+	WalletOptions tonwallet.Options
+}
+
+// New builds the provider selected in cfg. Without a DNSRoot, mainnet providers
+// resolve domains from MainnetDNSRoot.
+func New(cfg Config) (ChainProvider, error) {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.DNSRoot == "" && cfg.Network != Testnet {
+		cfg.DNSRoot = MainnetDNSRoot
+	}
+
+	switch cfg.Kind {
+	case KindTonAPI:
+		return NewTonAPIProvider(cfg.URL, cfg.APIKey, cfg.Timeout), nil
+	case KindToncenterV2:
+		return NewToncenterV2Provider(cfg.URL, cfg.APIKey, cfg.Timeout, cfg.WalletOptions, cfg.DNSRoot), nil
+	case KindToncenterV3:
+		return NewToncenterV3Provider(cfg.URL, cfg.APIKey, cfg.Timeout, cfg.WalletOptions, cfg.DNSRoot), nil
+	case KindFixture:
+		return LoadFixtureProvider(cfg.FixturePath, cfg.WalletOptions)
+	case KindLiteServer:
+		return NewLiteServerProvider(cfg.GlobalConfig, cfg.ProofCheck, cfg.Timeout, cfg.WalletOptions, cfg.DNSRoot)
+	default:
+		return nil, fmt.Errorf("unknown chain provider %q", cfg.Kind)
+	}
+}
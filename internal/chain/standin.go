@@ -0,0 +1,281 @@
+package chain
+
+import (
+	"TON/pkg/tonwallet"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/adnl"
+	"github.com/xssnick/tonutils-go/liteclient"
+	"github.com/xssnick/tonutils-go/tl"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+const (
+	exitCodeMethodNotFound  = 11
+	lsErrorCodeNotSupported = 1
+)
+
+// StandInLiteServer is a local lite-server that answers masterchain info, account
+// state and get-method queries from a FixtureProvider. Its responses carry no real
+// proofs, so clients have to connect with ProofCheckUnsafe.
+type StandInLiteServer struct {
+	key     ed25519.PrivateKey
+	fixture *FixtureProvider
+	server  *liteclient.Server
+	block   *ton.BlockIDExt
+}
+
+func NewStandInLiteServer(key ed25519.PrivateKey, fixture *FixtureProvider) *StandInLiteServer {
+	s := &StandInLiteServer{
+		key:     key,
+		fixture: fixture,
+		server:  liteclient.NewServer([]ed25519.PrivateKey{key}),
+		block: &ton.BlockIDExt{
+			Workchain: address.MasterchainID,
+			Shard:     -1 << 63,
+			SeqNo:     1,
+			RootHash:  make([]byte, 32),
+			FileHash:  make([]byte, 32),
+		},
+	}
+	s.server.SetMessageHandler(s.handle)
+	return s
+}
+
+// Listen serves connections on addr until Close is called.
+func (s *StandInLiteServer) Listen(addr string) error {
+	return s.server.Listen(addr)
+}
+
+func (s *StandInLiteServer) Close() error {
+	return s.server.Close()
+}
+
+// GlobalConfig returns a global config that points clients to the server listening on addr.
+func (s *StandInLiteServer) GlobalConfig(addr string) (*liteclient.GlobalConfig, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", portStr)
+	}
+	ip := net.ParseIP(host).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("lite-server address must be an IPv4 address, got %q", host)
+	}
+
+	return &liteclient.GlobalConfig{
+		Type: "config.global",
+		Liteservers: []liteclient.LiteserverConfig{{
+			IP:   int64(ip[0])<<24 | int64(ip[1])<<16 | int64(ip[2])<<8 | int64(ip[3]),
+			Port: port,
+			ID: liteclient.ServerID{
+				Type: "pub.ed25519",
+				Key:  base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey)),
+			},
+		}},
+	}, nil
+}
+
+func (s *StandInLiteServer) handle(ctx context.Context, client *liteclient.ServerClient, msg tl.Serializable) error {
+	switch m := msg.(type) {
+	case adnl.MessageQuery:
+		q, ok := m.Data.(liteclient.LiteServerQuery)
+		if !ok {
+			return fmt.Errorf("unexpected query %T", m.Data)
+		}
+		return client.Send(adnl.MessageAnswer{ID: m.ID, Data: s.answer(ctx, q.Data)})
+	case liteclient.TCPPing:
+		return client.Send(liteclient.TCPPong{RandomID: m.RandomID})
+	case liteclient.TCPAuthenticate:
+		return client.Send(liteclient.TCPAuthenticationNonce{Nonce: make([]byte, 32)})
+	case liteclient.TCPAuthenticationComplete:
+		return nil
+	default:
+		return fmt.Errorf("unexpected message %T", msg)
+	}
+}
+
+func (s *StandInLiteServer) answer(ctx context.Context, query any) tl.Serializable {
+	var (
+		res tl.Serializable
+		err error
+	)
+	switch q := query.(type) {
+	case ton.GetMasterchainInf:
+		res = ton.MasterchainInfo{
+			Last:          s.block,
+			StateRootHash: make([]byte, 32),
+			Init: &ton.ZeroStateIDExt{
+				Workchain: address.MasterchainID,
+				RootHash:  make([]byte, 32),
+				FileHash:  make([]byte, 32),
+			},
+		}
+	case ton.GetAccountState:
+		res, err = s.accountState(ctx, q.Account)
+	case ton.RunSmcMethod:
+		res, err = s.runMethod(ctx, q)
+	default:
+		return ton.LSError{Code: lsErrorCodeNotSupported, Text: fmt.Sprintf("%T is not supported by the stand-in", query)}
+	}
+	if err != nil {
+		return ton.LSError{Code: lsErrorCodeNotSupported, Text: err.Error()}
+	}
+	return res
+}
+
+func (s *StandInLiteServer) accountState(ctx context.Context, id ton.AccountID) (tl.Serializable, error) {
+	addr := address.NewAddress(0, byte(id.Workchain), id.ID)
+	acc, err := s.fixture.GetAccount(ctx, addr.StringRaw())
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := cell.NewDict(256)
+	var state *cell.Cell
+	if acc.Status != StatusNonexist {
+		if state, err = accountCell(addr, acc); err != nil {
+			return nil, err
+		}
+		value := cell.BeginCell().
+			MustStoreUInt(0, 5). // depth
+			MustStoreBigCoins(big.NewInt(acc.Balance)).
+			MustStoreDict(nil).
+			MustStoreRef(state).
+			MustStoreSlice(make([]byte, 32), 256). // last transaction hash
+			MustStoreUInt(1, 64)                   // last transaction lt
+		if err := accounts.Set(cell.BeginCell().MustStoreSlice(id.ID, 256).EndCell(), value.EndCell()); err != nil {
+			return nil, err
+		}
+	} else {
+		// The client rejects proofs without shard accounts, so an absent account is
+		// proven by a dictionary holding a different key.
+		placeholder := make([]byte, 32)
+		placeholder[0] = ^id.ID[0]
+		if err := accounts.Set(cell.BeginCell().MustStoreSlice(placeholder, 256).EndCell(), cell.BeginCell().EndCell()); err != nil {
+			return nil, err
+		}
+	}
+
+	shardState := cell.BeginCell().
+		MustStoreUInt(0x9023afe2, 32).
+		MustStoreInt(int64(tonwallet.MainnetGlobalID), 32).
+		MustStoreUInt(0, 2).MustStoreUInt(0, 6).MustStoreInt(int64(id.Workchain), 32).MustStoreUInt(1<<63, 64). // shard ident
+		MustStoreUInt(uint64(s.block.SeqNo), 32).
+		MustStoreUInt(0, 32).                     // vert seqno
+		MustStoreUInt(0, 32).                     // gen utime
+		MustStoreUInt(1, 64).                     // gen lt
+		MustStoreUInt(0, 32).                     // min ref mc seqno
+		MustStoreRef(cell.BeginCell().EndCell()). // out msg queue info
+		MustStoreBoolBit(false).
+		MustStoreRef(cell.BeginCell().MustStoreDict(accounts).EndCell()).
+		MustStoreRef(cell.BeginCell().EndCell()). // stats
+		MustStoreMaybeRef(nil).
+		EndCell()
+
+	return ton.AccountState{
+		ID:    s.block,
+		Shard: s.block,
+		Proof: []*cell.Cell{
+			cell.BeginCell().EndCell(),
+			cell.BeginCell().MustStoreRef(shardState).EndCell(),
+		},
+		State: state,
+	}, nil
+}
+
+// accountCell serializes the account the way it is stored in the shard state.
+func accountCell(addr *address.Address, acc *Account) (*cell.Cell, error) {
+	storage := cell.BeginCell().
+		MustStoreUInt(1, 64). // last transaction lt
+		MustStoreBigCoins(big.NewInt(acc.Balance)).
+		MustStoreDict(nil)
+
+	switch acc.Status {
+	case StatusActive:
+		state := &tlb.StateInit{}
+		var err error
+		if len(acc.Code) > 0 {
+			if state.Code, err = cell.FromBOC(acc.Code); err != nil {
+				return nil, fmt.Errorf("invalid account code: %w", err)
+			}
+		}
+		if len(acc.Data) > 0 {
+			if state.Data, err = cell.FromBOC(acc.Data); err != nil {
+				return nil, fmt.Errorf("invalid account data: %w", err)
+			}
+		}
+		c, err := tlb.ToCell(state)
+		if err != nil {
+			return nil, err
+		}
+		storage.MustStoreBoolBit(true).MustStoreBuilder(c.ToBuilder())
+	case StatusFrozen:
+		storage.MustStoreUInt(0b01, 2).MustStoreSlice(make([]byte, 32), 256)
+	default:
+		storage.MustStoreUInt(0b00, 2)
+	}
+
+	return cell.BeginCell().
+		MustStoreBoolBit(true).
+		MustStoreAddr(addr).
+		MustStoreVarUInt(0, 7).MustStoreVarUInt(0, 7). // storage used
+		MustStoreUInt(0, 3).                           // no storage extra
+		MustStoreUInt(0, 32).                          // last paid
+		MustStoreBoolBit(false).                       // no due payment
+		MustStoreBuilder(storage).
+		EndCell(), nil
+}
+
+func (s *StandInLiteServer) runMethod(ctx context.Context, q ton.RunSmcMethod) (tl.Serializable, error) {
+	addr := address.NewAddress(0, byte(q.Account.Workchain), q.Account.ID).StringRaw()
+	res := ton.RunMethodResult{
+		Mode:       1 << 2,
+		ID:         s.block,
+		ShardBlock: s.block,
+	}
+
+	acc, err := s.fixture.GetAccount(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if acc.Status != StatusActive {
+		res.ExitCode = ton.ErrCodeContractNotInitialized
+		return res, nil
+	}
+
+	method, ok := s.fixture.getMethodName(addr, q.MethodID)
+	if !ok {
+		res.ExitCode = exitCodeMethodNotFound
+		return res, nil
+	}
+	entries, err := s.fixture.RunGetMethod(ctx, addr, method)
+	if err != nil {
+		return nil, err
+	}
+
+	var stack tlb.Stack
+	for i := len(entries) - 1; i >= 0; i-- {
+		v, err := toTVMValue(entries[i])
+		if err != nil {
+			return nil, err
+		}
+		stack.Push(v)
+	}
+	if res.Result, err = stack.ToCell(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,355 @@
+package chain
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TonAPIProvider reads the chain through the tonapi.io REST API.
+type TonAPIProvider struct {
+	http *httpClient
+}
+
+func NewTonAPIProvider(apiURL, apiKey string, timeout time.Duration) *TonAPIProvider {
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["Authorization"] = "Bearer " + apiKey
+	}
+	return &TonAPIProvider{
+		http: &httpClient{
+			baseURL: strings.TrimRight(apiURL, "/"),
+			headers: headers,
+			client:  &http.Client{Timeout: timeout},
+		},
+	}
+}
+
+func (p *TonAPIProvider) GetWallets(ctx context.Context, pubKey ed25519.PublicKey) ([]Wallet, error) {
+	var data struct {
+		Wallets []struct {
+			Address    string   `json:"address"`
+			Balance    int64    `json:"balance"`
+			Status     string   `json:"status"`
+			Interfaces []string `json:"interfaces"`
+		} `json:"wallets"`
+	}
+	if err := p.http.getJSON(ctx, fmt.Sprintf("/v2/wallets?public_key=%x", []byte(pubKey)), &data); err != nil {
+		return nil, err
+	}
+
+	wallets := make([]Wallet, 0, len(data.Wallets))
+	for _, w := range data.Wallets {
+		wallets = append(wallets, Wallet{
+			Address: normalizeAddress(w.Address),
+			Version: tonapiWalletVersion(w.Interfaces),
+			Balance: w.Balance,
+			Status:  AccountStatus(w.Status),
+		})
+	}
+	return wallets, nil
+}
+
+func (p *TonAPIProvider) GetAccount(ctx context.Context, addr string) (*Account, error) {
+	var data struct {
+		Address string `json:"address"`
+		Balance int64  `json:"balance"`
+		Status  string `json:"status"`
+		Code    string `json:"code"`
+		Data    string `json:"data"`
+	}
+	if err := p.http.getJSON(ctx, "/v2/blockchain/accounts/"+url.PathEscape(addr), &data); err != nil {
+		return nil, err
+	}
+
+	code, err := hex.DecodeString(data.Code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account code: %w", err)
+	}
+	accData, err := hex.DecodeString(data.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account data: %w", err)
+	}
+
+	return &Account{
+		Address: normalizeAddress(data.Address),
+		Status:  AccountStatus(data.Status),
+		Balance: data.Balance,
+		Code:    code,
+		Data:    accData,
+	}, nil
+}
+
+func (p *TonAPIProvider) GetBalance(ctx context.Context, addr string) (int64, error) {
+	acc, err := p.GetAccount(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
+	return acc.Balance, nil
+}
+
+type tonapiStackRecord struct {
+	Type  string              `json:"type"`
+	Num   string              `json:"num"`
+	Cell  string              `json:"cell"`
+	Slice string              `json:"slice"`
+	Tuple []tonapiStackRecord `json:"tuple"`
+}
+
+func (p *TonAPIProvider) RunGetMethod(ctx context.Context, addr, method string, args ...StackEntry) ([]StackEntry, error) {
+	query := url.Values{}
+	for _, arg := range args {
+		switch arg.Type {
+		case StackNum:
+			query.Add("args", "0x"+arg.Num.Text(16))
+		case StackCell, StackSlice:
+			query.Add("args", hex.EncodeToString(arg.Cell))
+		default:
+			return nil, fmt.Errorf("unsupported argument type %s", arg.Type)
+		}
+	}
+
+	path := fmt.Sprintf("/v2/blockchain/accounts/%s/methods/%s", url.PathEscape(addr), url.PathEscape(method))
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var data struct {
+		Success  bool                `json:"success"`
+		ExitCode int                 `json:"exit_code"`
+		Stack    []tonapiStackRecord `json:"stack"`
+	}
+	if err := p.http.getJSON(ctx, path, &data); err != nil {
+		return nil, err
+	}
+	if !data.Success {
+		return nil, fmt.Errorf("%w: %s exited with code %d", ErrGetMethodFailed, method, data.ExitCode)
+	}
+
+	stack := make([]StackEntry, 0, len(data.Stack))
+	for _, r := range data.Stack {
+		entry, err := r.toEntry()
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, entry)
+	}
+	return stack, nil
+}
+
+func (r tonapiStackRecord) toEntry() (StackEntry, error) {
+	switch r.Type {
+	case "num":
+		return parseNumEntry(r.Num)
+	case "cell":
+		boc, err := hex.DecodeString(r.Cell)
+		return StackEntry{Type: StackCell, Cell: boc}, err
+	case "slice":
+		boc, err := hex.DecodeString(r.Slice)
+		return StackEntry{Type: StackSlice, Cell: boc}, err
+	default:
+		return StackEntry{Type: StackNull}, nil
+	}
+}
+
+func (p *TonAPIProvider) GetJettonBalance(ctx context.Context, owner, master string) (*JettonBalance, error) {
+	var data struct {
+		Balance       string `json:"balance"`
+		WalletAddress struct {
+			Address string `json:"address"`
+		} `json:"wallet_address"`
+		Jetton struct {
+			Address  string `json:"address"`
+			Symbol   string `json:"symbol"`
+			Decimals int    `json:"decimals"`
+		} `json:"jetton"`
+	}
+	path := fmt.Sprintf("/v2/accounts/%s/jettons/%s", url.PathEscape(owner), url.PathEscape(master))
+	err := p.http.getJSON(ctx, path, &data)
+	if errors.Is(err, ErrNotFound) {
+		return &JettonBalance{Master: normalizeAddress(master), Amount: new(big.Int)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	amount, ok := new(big.Int).SetString(data.Balance, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid jetton balance %q", data.Balance)
+	}
+
+	return &JettonBalance{
+		Master:   normalizeAddress(master),
+		Wallet:   normalizeAddress(data.WalletAddress.Address),
+		Symbol:   data.Jetton.Symbol,
+		Decimals: data.Jetton.Decimals,
+		Amount:   amount,
+	}, nil
+}
+
+func (p *TonAPIProvider) GetNFTs(ctx context.Context, owner, collection string) ([]NFTItem, error) {
+	query := url.Values{}
+	query.Set("limit", "1000")
+	query.Set("indirect_ownership", "false")
+	if collection != "" {
+		query.Set("collection", collection)
+	}
+
+	var data struct {
+		Items []struct {
+			Address string `json:"address"`
+			Owner   struct {
+				Address string `json:"address"`
+			} `json:"owner"`
+			Collection struct {
+				Address string `json:"address"`
+			} `json:"collection"`
+			Metadata struct {
+				Name       string `json:"name"`
+				Attributes []struct {
+					TraitType string      `json:"trait_type"`
+					Value     interface{} `json:"value"`
+				} `json:"attributes"`
+			} `json:"metadata"`
+		} `json:"nft_items"`
+	}
+	path := fmt.Sprintf("/v2/accounts/%s/nfts?%s", url.PathEscape(owner), query.Encode())
+	if err := p.http.getJSON(ctx, path, &data); err != nil {
+		return nil, err
+	}
+
+	items := make([]NFTItem, 0, len(data.Items))
+	for _, it := range data.Items {
+		attrs := make(map[string]string, len(it.Metadata.Attributes))
+		for _, a := range it.Metadata.Attributes {
+			attrs[a.TraitType] = fmt.Sprint(a.Value)
+		}
+		items = append(items, NFTItem{
+			Address:    normalizeAddress(it.Address),
+			Collection: normalizeAddress(it.Collection.Address),
+			Owner:      normalizeAddress(it.Owner.Address),
+			Name:       it.Metadata.Name,
+			Attributes: attrs,
+		})
+	}
+	return items, nil
+}
+
+func (p *TonAPIProvider) GetTransactions(ctx context.Context, addr string, before *Transaction, limit int) ([]Transaction, error) {
+	var data struct {
+		Transactions []struct {
+			Hash  string `json:"hash"`
+			LT    uint64 `json:"lt"`
+			Utime int64  `json:"utime"`
+			InMsg *struct {
+				MsgType string `json:"msg_type"`
+				Value   int64  `json:"value"`
+				Source  *struct {
+					Address string `json:"address"`
+				} `json:"source"`
+				DecodedOpName string `json:"decoded_op_name"`
+				DecodedBody   struct {
+					Text string `json:"text"`
+				} `json:"decoded_body"`
+				// RawBody is the body BoC in hex.
+				RawBody string `json:"raw_body"`
+			} `json:"in_msg"`
+			OutMsgs []struct {
+				Destination *struct {
+					Address string `json:"address"`
+				} `json:"destination"`
+			} `json:"out_msgs"`
+		} `json:"transactions"`
+	}
+	path := fmt.Sprintf("/v2/blockchain/accounts/%s/transactions?limit=%d&sort_order=desc", url.PathEscape(addr), limit)
+	if before != nil {
+		path += fmt.Sprintf("&before_lt=%d", before.LT)
+	}
+	if err := p.http.getJSON(ctx, path, &data); err != nil {
+		return nil, err
+	}
+
+	txs := make([]Transaction, 0, len(data.Transactions))
+	for _, t := range data.Transactions {
+		tx := Transaction{Hash: t.Hash, LT: t.LT, Time: time.Unix(t.Utime, 0).UTC()}
+		if in := t.InMsg; in != nil && in.MsgType == "int_msg" {
+			if in.Source != nil {
+				tx.From = normalizeAddress(in.Source.Address)
+			}
+			tx.Value = in.Value
+			if in.DecodedOpName == "text_comment" {
+				tx.Comment = in.DecodedBody.Text
+			}
+			if in.DecodedOpName == "jetton_notify" {
+				body, _ := hex.DecodeString(in.RawBody)
+				tx.Jetton = jettonNotificationBoC(body)
+			}
+		}
+		for _, out := range t.OutMsgs {
+			if out.Destination != nil {
+				tx.To = append(tx.To, normalizeAddress(out.Destination.Address))
+			}
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+func (p *TonAPIProvider) ResolveDNS(ctx context.Context, domain string) (string, error) {
+	var data struct {
+		Wallet *struct {
+			Address string `json:"address"`
+		} `json:"wallet"`
+	}
+	if err := p.http.getJSON(ctx, "/v2/dns/"+url.PathEscape(domain)+"/resolve", &data); err != nil {
+		return "", err
+	}
+	if data.Wallet == nil || data.Wallet.Address == "" {
+		return "", ErrNotFound
+	}
+	return normalizeAddress(data.Wallet.Address), nil
+}
+
+func (p *TonAPIProvider) DNSRecords(ctx context.Context, domain string) (*DNSRecords, error) {
+	var data struct {
+		Sites   []string `json:"sites"`
+		Storage string   `json:"storage"`
+	}
+	if err := p.http.getJSON(ctx, "/v2/dns/"+url.PathEscape(domain)+"/resolve", &data); err != nil {
+		return nil, err
+	}
+	return &DNSRecords{Sites: data.Sites, Storage: data.Storage}, nil
+}
+
+func (p *TonAPIProvider) ReverseDNS(ctx context.Context, addr string) ([]string, error) {
+	var data struct {
+		Domains []string `json:"domains"`
+	}
+	if err := p.http.getJSON(ctx, "/v2/accounts/"+url.PathEscape(addr)+"/dns/backresolve", &data); err != nil {
+		return nil, err
+	}
+	return data.Domains, nil
+}
+
+func tonapiWalletVersion(interfaces []string) string {
+	for _, i := range interfaces {
+		switch i {
+		case "wallet_v3r1":
+			return "v3r1"
+		case "wallet_v3r2":
+			return "v3r2"
+		case "wallet_v4r2":
+			return "v4r2"
+		case "wallet_v5r1":
+			return "v5r1"
+		}
+	}
+	return ""
+}
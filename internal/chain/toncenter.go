@@ -0,0 +1,616 @@
+package chain
+
+import (
+	"TON/pkg/tonwallet"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ToncenterV2Provider reads the chain through the toncenter.com HTTP API v2.
+// The API has no public key or NFT index, so wallets are derived locally and
+// NFT lookups are not supported.
+type ToncenterV2Provider struct {
+	http       *httpClient
+	walletOpts tonwallet.Options
+	dnsRoot    string
+}
+
+func NewToncenterV2Provider(apiURL, apiKey string, timeout time.Duration, walletOpts tonwallet.Options, dnsRoot string) *ToncenterV2Provider {
+	return &ToncenterV2Provider{
+		http:       newToncenterClient(strings.TrimRight(apiURL, "/")+"/api/v2", apiKey, timeout),
+		walletOpts: walletOpts,
+		dnsRoot:    dnsRoot,
+	}
+}
+
+// ToncenterV3Provider reads the chain through the toncenter.com indexer API v3.
+type ToncenterV3Provider struct {
+	http       *httpClient
+	walletOpts tonwallet.Options
+	dnsRoot    string
+}
+
+func NewToncenterV3Provider(apiURL, apiKey string, timeout time.Duration, walletOpts tonwallet.Options, dnsRoot string) *ToncenterV3Provider {
+	return &ToncenterV3Provider{
+		http:       newToncenterClient(strings.TrimRight(apiURL, "/")+"/api/v3", apiKey, timeout),
+		walletOpts: walletOpts,
+		dnsRoot:    dnsRoot,
+	}
+}
+
+func newToncenterClient(baseURL, apiKey string, timeout time.Duration) *httpClient {
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["X-API-Key"] = apiKey
+	}
+	return &httpClient{
+		baseURL: baseURL,
+		headers: headers,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// v2
+
+type toncenterV2Response struct {
+	OK     bool            `json:"ok"`
+	Result json.RawMessage `json:"result"`
+	Error  string          `json:"error"`
+}
+
+func (p *ToncenterV2Provider) call(ctx context.Context, method string, path string, in, out interface{}) error {
+	var resp toncenterV2Response
+	var err error
+	if method == http.MethodPost {
+		err = p.http.postJSON(ctx, path, in, &resp)
+	} else {
+		err = p.http.getJSON(ctx, path, &resp)
+	}
+	if err != nil {
+		return err
+	}
+	if !resp.OK {
+		return fmt.Errorf("toncenter error: %s", resp.Error)
+	}
+	return json.Unmarshal(resp.Result, out)
+}
+
+func (p *ToncenterV2Provider) GetWallets(ctx context.Context, pubKey ed25519.PublicKey) ([]Wallet, error) {
+	return walletsByDerivation(ctx, p, pubKey, p.walletOpts)
+}
+
+func (p *ToncenterV2Provider) GetAccount(ctx context.Context, addr string) (*Account, error) {
+	var data struct {
+		Balance           string `json:"balance"`
+		Code              string `json:"code"`
+		Data              string `json:"data"`
+		State             string `json:"state"`
+		LastTransactionID struct {
+			LT string `json:"lt"`
+		} `json:"last_transaction_id"`
+	}
+	if err := p.call(ctx, http.MethodGet, "/getAddressInformation?address="+url.QueryEscape(addr), nil, &data); err != nil {
+		return nil, err
+	}
+
+	balance, err := strconv.ParseInt(data.Balance, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid balance %q", data.Balance)
+	}
+
+	acc := &Account{
+		Address: normalizeAddress(addr),
+		Balance: balance,
+	}
+	switch data.State {
+	case "active":
+		acc.Status = StatusActive
+		if acc.Code, err = base64.StdEncoding.DecodeString(data.Code); err != nil {
+			return nil, fmt.Errorf("invalid account code: %w", err)
+		}
+		if acc.Data, err = base64.StdEncoding.DecodeString(data.Data); err != nil {
+			return nil, fmt.Errorf("invalid account data: %w", err)
+		}
+	case "frozen":
+		acc.Status = StatusFrozen
+	default:
+		acc.Status = StatusUninit
+		if data.LastTransactionID.LT == "" || data.LastTransactionID.LT == "0" {
+			acc.Status = StatusNonexist
+		}
+	}
+	return acc, nil
+}
+
+func (p *ToncenterV2Provider) GetBalance(ctx context.Context, addr string) (int64, error) {
+	acc, err := p.GetAccount(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
+	return acc.Balance, nil
+}
+
+func (p *ToncenterV2Provider) RunGetMethod(ctx context.Context, addr, method string, args ...StackEntry) ([]StackEntry, error) {
+	stack := make([][]string, 0, len(args))
+	for _, arg := range args {
+		switch arg.Type {
+		case StackNum:
+			stack = append(stack, []string{"num", "0x" + arg.Num.Text(16)})
+		case StackCell:
+			stack = append(stack, []string{"tvm.Cell", base64.StdEncoding.EncodeToString(arg.Cell)})
+		case StackSlice:
+			stack = append(stack, []string{"tvm.Slice", base64.StdEncoding.EncodeToString(arg.Cell)})
+		default:
+			return nil, fmt.Errorf("unsupported argument type %s", arg.Type)
+		}
+	}
+
+	req := map[string]interface{}{
+		"address": addr,
+		"method":  method,
+		"stack":   stack,
+	}
+	var data struct {
+		ExitCode int                 `json:"exit_code"`
+		Stack    [][]json.RawMessage `json:"stack"`
+	}
+	if err := p.call(ctx, http.MethodPost, "/runGetMethod", req, &data); err != nil {
+		return nil, err
+	}
+	if data.ExitCode != 0 && data.ExitCode != 1 {
+		return nil, fmt.Errorf("%w: %s exited with code %d", ErrGetMethodFailed, method, data.ExitCode)
+	}
+
+	res := make([]StackEntry, 0, len(data.Stack))
+	for _, item := range data.Stack {
+		entry, err := parseToncenterV2Entry(item)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, entry)
+	}
+	return res, nil
+}
+
+func parseToncenterV2Entry(item []json.RawMessage) (StackEntry, error) {
+	if len(item) != 2 {
+		return StackEntry{}, errors.New("invalid stack entry")
+	}
+	var typ string
+	if err := json.Unmarshal(item[0], &typ); err != nil {
+		return StackEntry{}, err
+	}
+
+	switch typ {
+	case "num":
+		var num string
+		if err := json.Unmarshal(item[1], &num); err != nil {
+			return StackEntry{}, err
+		}
+		return parseNumEntry(num)
+	case "cell", "slice":
+		var value struct {
+			Bytes string `json:"bytes"`
+		}
+		if err := json.Unmarshal(item[1], &value); err != nil {
+			return StackEntry{}, err
+		}
+		boc, err := base64.StdEncoding.DecodeString(value.Bytes)
+		if err != nil {
+			return StackEntry{}, err
+		}
+		entryType := StackCell
+		if typ == "slice" {
+			entryType = StackSlice
+		}
+		return StackEntry{Type: entryType, Cell: boc}, nil
+	default:
+		return StackEntry{Type: StackNull}, nil
+	}
+}
+
+func (p *ToncenterV2Provider) GetJettonBalance(ctx context.Context, owner, master string) (*JettonBalance, error) {
+	return jettonBalanceByGetMethods(ctx, p, owner, master)
+}
+
+func (p *ToncenterV2Provider) GetNFTs(ctx context.Context, owner, collection string) ([]NFTItem, error) {
+	return nil, ErrNotSupported
+}
+
+func (p *ToncenterV2Provider) GetTransactions(ctx context.Context, addr string, before *Transaction, limit int) ([]Transaction, error) {
+	type message struct {
+		Source      string `json:"source"`
+		Destination string `json:"destination"`
+		Value       string `json:"value"`
+		Message     string `json:"message"`
+		MsgData     struct {
+			Type string `json:"@type"`
+			// Body is the body BoC in base64 for msg.dataRaw.
+			Body string `json:"body"`
+		} `json:"msg_data"`
+	}
+	var data []struct {
+		TransactionID struct {
+			LT   string `json:"lt"`
+			Hash string `json:"hash"`
+		} `json:"transaction_id"`
+		Utime   int64     `json:"utime"`
+		InMsg   *message  `json:"in_msg"`
+		OutMsgs []message `json:"out_msgs"`
+	}
+	path := fmt.Sprintf("/getTransactions?address=%s&limit=%d&archival=true", url.QueryEscape(addr), limit)
+	if before != nil {
+		// the listing starts with the transaction at lt and hash, which is dropped below
+		hash, err := hex.DecodeString(before.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction hash %q", before.Hash)
+		}
+		path = fmt.Sprintf("/getTransactions?address=%s&limit=%d&lt=%d&hash=%s&archival=true",
+			url.QueryEscape(addr), limit+1, before.LT, url.QueryEscape(base64.StdEncoding.EncodeToString(hash)))
+	}
+	if err := p.call(ctx, http.MethodGet, path, nil, &data); err != nil {
+		return nil, err
+	}
+
+	txs := make([]Transaction, 0, len(data))
+	for _, t := range data {
+		tx, err := toncenterTransaction(t.TransactionID.Hash, t.TransactionID.LT, t.Utime)
+		if err != nil {
+			return nil, err
+		}
+		if before != nil && tx.LT >= before.LT {
+			continue
+		}
+		if in := t.InMsg; in != nil && in.Source != "" {
+			tx.From = normalizeAddress(in.Source)
+			tx.Value, _ = strconv.ParseInt(in.Value, 10, 64)
+			if in.MsgData.Type == "msg.dataText" {
+				tx.Comment = in.Message
+			}
+			if in.MsgData.Type == "msg.dataRaw" {
+				body, _ := base64.StdEncoding.DecodeString(in.MsgData.Body)
+				tx.Jetton = jettonNotificationBoC(body)
+			}
+		}
+		for _, out := range t.OutMsgs {
+			if out.Destination != "" {
+				tx.To = append(tx.To, normalizeAddress(out.Destination))
+			}
+		}
+		txs = append(txs, tx)
+	}
+	if len(txs) > limit {
+		txs = txs[:limit]
+	}
+	return txs, nil
+}
+
+// toncenterTransaction converts the base64 hash and decimal lt of toncenter responses.
+func toncenterTransaction(hash, lt string, utime int64) (Transaction, error) {
+	h, err := base64.StdEncoding.DecodeString(hash)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid transaction hash %q", hash)
+	}
+	l, err := strconv.ParseUint(lt, 10, 64)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid transaction lt %q", lt)
+	}
+	return Transaction{Hash: hex.EncodeToString(h), LT: l, Time: time.Unix(utime, 0).UTC()}, nil
+}
+
+func (p *ToncenterV2Provider) ResolveDNS(ctx context.Context, domain string) (string, error) {
+	return resolveDNSByGetMethods(ctx, p, p.dnsRoot, domain)
+}
+
+func (p *ToncenterV2Provider) DNSRecords(ctx context.Context, domain string) (*DNSRecords, error) {
+	return dnsRecordsByGetMethods(ctx, p, p.dnsRoot, domain)
+}
+
+func (p *ToncenterV2Provider) ReverseDNS(ctx context.Context, addr string) ([]string, error) {
+	return nil, ErrNotSupported
+}
+
+// v3
+
+func (p *ToncenterV3Provider) GetWallets(ctx context.Context, pubKey ed25519.PublicKey) ([]Wallet, error) {
+	return walletsByDerivation(ctx, p, pubKey, p.walletOpts)
+}
+
+func (p *ToncenterV3Provider) GetAccount(ctx context.Context, addr string) (*Account, error) {
+	var data struct {
+		Accounts []struct {
+			Address string `json:"address"`
+			Status  string `json:"status"`
+			Balance string `json:"balance"`
+			CodeBoc string `json:"code_boc"`
+			DataBoc string `json:"data_boc"`
+		} `json:"accounts"`
+	}
+	path := "/accountStates?include_boc=true&address=" + url.QueryEscape(addr)
+	if err := p.http.getJSON(ctx, path, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Accounts) == 0 {
+		return &Account{Address: normalizeAddress(addr), Status: StatusNonexist}, nil
+	}
+
+	a := data.Accounts[0]
+	balance, err := strconv.ParseInt(a.Balance, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid balance %q", a.Balance)
+	}
+	acc := &Account{
+		Address: normalizeAddress(a.Address),
+		Status:  AccountStatus(a.Status),
+		Balance: balance,
+	}
+	if acc.Code, err = base64.StdEncoding.DecodeString(a.CodeBoc); err != nil {
+		return nil, fmt.Errorf("invalid account code: %w", err)
+	}
+	if acc.Data, err = base64.StdEncoding.DecodeString(a.DataBoc); err != nil {
+		return nil, fmt.Errorf("invalid account data: %w", err)
+	}
+	return acc, nil
+}
+
+func (p *ToncenterV3Provider) GetBalance(ctx context.Context, addr string) (int64, error) {
+	acc, err := p.GetAccount(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
+	return acc.Balance, nil
+}
+
+type toncenterV3StackEntry struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func (p *ToncenterV3Provider) RunGetMethod(ctx context.Context, addr, method string, args ...StackEntry) ([]StackEntry, error) {
+	stack := make([]toncenterV3StackEntry, 0, len(args))
+	for _, arg := range args {
+		switch arg.Type {
+		case StackNum:
+			stack = append(stack, toncenterV3StackEntry{Type: "num", Value: "0x" + arg.Num.Text(16)})
+		case StackCell, StackSlice:
+			stack = append(stack, toncenterV3StackEntry{Type: arg.Type, Value: base64.StdEncoding.EncodeToString(arg.Cell)})
+		default:
+			return nil, fmt.Errorf("unsupported argument type %s", arg.Type)
+		}
+	}
+
+	req := map[string]interface{}{
+		"address": addr,
+		"method":  method,
+		"stack":   stack,
+	}
+	var data struct {
+		ExitCode int                     `json:"exit_code"`
+		Stack    []toncenterV3StackEntry `json:"stack"`
+	}
+	if err := p.http.postJSON(ctx, "/runGetMethod", req, &data); err != nil {
+		return nil, err
+	}
+	if data.ExitCode != 0 && data.ExitCode != 1 {
+		return nil, fmt.Errorf("%w: %s exited with code %d", ErrGetMethodFailed, method, data.ExitCode)
+	}
+
+	res := make([]StackEntry, 0, len(data.Stack))
+	for _, item := range data.Stack {
+		switch item.Type {
+		case "num":
+			entry, err := parseNumEntry(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, entry)
+		case "cell", "slice":
+			boc, err := base64.StdEncoding.DecodeString(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, StackEntry{Type: item.Type, Cell: boc})
+		default:
+			res = append(res, StackEntry{Type: StackNull})
+		}
+	}
+	return res, nil
+}
+
+type toncenterV3Metadata map[string]struct {
+	TokenInfo []struct {
+		Name   string                 `json:"name"`
+		Symbol string                 `json:"symbol"`
+		Extra  map[string]interface{} `json:"extra"`
+	} `json:"token_info"`
+}
+
+func (p *ToncenterV3Provider) GetJettonBalance(ctx context.Context, owner, master string) (*JettonBalance, error) {
+	query := url.Values{}
+	query.Set("owner_address", owner)
+	query.Set("jetton_address", master)
+	query.Set("limit", "1")
+
+	var data struct {
+		JettonWallets []struct {
+			Address string `json:"address"`
+			Balance string `json:"balance"`
+			Jetton  string `json:"jetton"`
+		} `json:"jetton_wallets"`
+		Metadata toncenterV3Metadata `json:"metadata"`
+	}
+	if err := p.http.getJSON(ctx, "/jetton/wallets?"+query.Encode(), &data); err != nil {
+		return nil, err
+	}
+
+	balance := &JettonBalance{Master: normalizeAddress(master), Amount: new(big.Int)}
+	if len(data.JettonWallets) == 0 {
+		return balance, nil
+	}
+
+	w := data.JettonWallets[0]
+	amount, ok := new(big.Int).SetString(w.Balance, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid jetton balance %q", w.Balance)
+	}
+	balance.Wallet = normalizeAddress(w.Address)
+	balance.Amount = amount
+
+	if meta, ok := data.Metadata[w.Jetton]; ok && len(meta.TokenInfo) > 0 {
+		balance.Symbol = meta.TokenInfo[0].Symbol
+		if d, ok := meta.TokenInfo[0].Extra["decimals"]; ok {
+			balance.Decimals, _ = strconv.Atoi(fmt.Sprint(d))
+		}
+	}
+	return balance, nil
+}
+
+func (p *ToncenterV3Provider) GetNFTs(ctx context.Context, owner, collection string) ([]NFTItem, error) {
+	query := url.Values{}
+	query.Set("owner_address", owner)
+	query.Set("limit", "1000")
+	if collection != "" {
+		query.Set("collection_address", collection)
+	}
+
+	var data struct {
+		Items []struct {
+			Address           string `json:"address"`
+			CollectionAddress string `json:"collection_address"`
+			OwnerAddress      string `json:"owner_address"`
+		} `json:"nft_items"`
+		Metadata toncenterV3Metadata `json:"metadata"`
+	}
+	if err := p.http.getJSON(ctx, "/nft/items?"+query.Encode(), &data); err != nil {
+		return nil, err
+	}
+
+	items := make([]NFTItem, 0, len(data.Items))
+	for _, it := range data.Items {
+		item := NFTItem{
+			Address:    normalizeAddress(it.Address),
+			Collection: normalizeAddress(it.CollectionAddress),
+			Owner:      normalizeAddress(it.OwnerAddress),
+			Attributes: map[string]string{},
+		}
+		if meta, ok := data.Metadata[it.Address]; ok && len(meta.TokenInfo) > 0 {
+			item.Name = meta.TokenInfo[0].Name
+			if attrs, ok := meta.TokenInfo[0].Extra["attributes"].([]interface{}); ok {
+				for _, a := range attrs {
+					if m, ok := a.(map[string]interface{}); ok {
+						item.Attributes[fmt.Sprint(m["trait_type"])] = fmt.Sprint(m["value"])
+					}
+				}
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (p *ToncenterV3Provider) GetTransactions(ctx context.Context, addr string, before *Transaction, limit int) ([]Transaction, error) {
+	type message struct {
+		Source         *string `json:"source"`
+		Destination    *string `json:"destination"`
+		Value          *string `json:"value"`
+		MessageContent *struct {
+			// Body is the body BoC in base64.
+			Body    string `json:"body"`
+			Decoded *struct {
+				Type    string `json:"type"`
+				Comment string `json:"comment"`
+			} `json:"decoded"`
+		} `json:"message_content"`
+	}
+	var data struct {
+		Transactions []struct {
+			Hash    string    `json:"hash"`
+			LT      string    `json:"lt"`
+			Now     int64     `json:"now"`
+			InMsg   *message  `json:"in_msg"`
+			OutMsgs []message `json:"out_msgs"`
+		} `json:"transactions"`
+	}
+	query := url.Values{}
+	query.Set("account", addr)
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("sort", "desc")
+	if before != nil {
+		query.Set("end_lt", strconv.FormatUint(before.LT-1, 10))
+	}
+	if err := p.http.getJSON(ctx, "/transactions?"+query.Encode(), &data); err != nil {
+		return nil, err
+	}
+
+	txs := make([]Transaction, 0, len(data.Transactions))
+	for _, t := range data.Transactions {
+		tx, err := toncenterTransaction(t.Hash, t.LT, t.Now)
+		if err != nil {
+			return nil, err
+		}
+		if in := t.InMsg; in != nil && in.Source != nil {
+			tx.From = normalizeAddress(*in.Source)
+			if in.Value != nil {
+				tx.Value, _ = strconv.ParseInt(*in.Value, 10, 64)
+			}
+			if c := in.MessageContent; c != nil {
+				if c.Decoded != nil && c.Decoded.Type == "text_comment" {
+					tx.Comment = c.Decoded.Comment
+				}
+				body, _ := base64.StdEncoding.DecodeString(c.Body)
+				tx.Jetton = jettonNotificationBoC(body)
+			}
+		}
+		for _, out := range t.OutMsgs {
+			if out.Destination != nil {
+				tx.To = append(tx.To, normalizeAddress(*out.Destination))
+			}
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+func (p *ToncenterV3Provider) ResolveDNS(ctx context.Context, domain string) (string, error) {
+	return resolveDNSByGetMethods(ctx, p, p.dnsRoot, domain)
+}
+
+func (p *ToncenterV3Provider) DNSRecords(ctx context.Context, domain string) (*DNSRecords, error) {
+	return dnsRecordsByGetMethods(ctx, p, p.dnsRoot, domain)
+}
+
+func (p *ToncenterV3Provider) ReverseDNS(ctx context.Context, addr string) ([]string, error) {
+	var data struct {
+		Records []struct {
+			Domain string `json:"domain"`
+		} `json:"records"`
+	}
+	if err := p.http.getJSON(ctx, "/dns/records?wallet="+url.QueryEscape(addr), &data); err != nil {
+		return nil, err
+	}
+
+	domains := make([]string, 0, len(data.Records))
+	for _, r := range data.Records {
+		domains = append(domains, r.Domain)
+	}
+	return domains, nil
+}
+
+func parseNumEntry(s string) (StackEntry, error) {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return StackEntry{}, fmt.Errorf("invalid number %q", s)
+	}
+	return StackEntry{Type: StackNum, Num: n}, nil
+}
@@ -0,0 +1,63 @@
+package chain
+
+import (
+	"math/big"
+
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton/jetton"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// JettonTransfer is an incoming jetton transfer, decoded from the transfer_notification
+// the jetton wallet of the recipient sends. Only the sender of the notification, the
+// From of the transaction, tells which jetton was received.
+type JettonTransfer struct {
+	// Sender is the owner of the jetton wallet the jettons came from.
+	Sender string `json:"sender,omitempty"`
+	// Amount in base units of the jetton.
+	Amount *big.Int `json:"amount"`
+	// Comment is the text comment of the forward payload.
+	Comment string `json:"comment,omitempty"`
+}
+
+// jettonNotification decodes a transfer_notification message body, nil for other bodies.
+func jettonNotification(body *cell.Cell) *JettonTransfer {
+	if body == nil {
+		return nil
+	}
+	var n jetton.TransferNotification
+	if err := tlb.LoadFromCell(&n, body.BeginParse()); err != nil {
+		return nil
+	}
+
+	t := &JettonTransfer{Amount: n.Amount.Nano(), Comment: textComment(n.ForwardPayload)}
+	if n.Sender != nil && !n.Sender.IsAddrNone() {
+		t.Sender = n.Sender.StringRaw()
+	}
+	return t
+}
+
+// jettonNotificationBoC decodes a message body serialized as a BoC.
+func jettonNotificationBoC(boc []byte) *JettonTransfer {
+	if len(boc) == 0 {
+		return nil
+	}
+	body, err := cell.FromBOC(boc)
+	if err != nil {
+		return nil
+	}
+	return jettonNotification(body)
+}
+
+// textComment reads a payload that starts with the zero op of text comments.
+func textComment(payload *cell.Cell) string {
+	if payload == nil {
+		return ""
+	}
+	s := payload.BeginParse()
+	if op, err := s.LoadUInt(32); err != nil || op != 0 {
+		return ""
+	}
+	comment, _ := s.LoadStringSnake()
+	return comment
+}
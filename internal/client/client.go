@@ -0,0 +1,161 @@
+package client
+
+import (
+	"TON/internal/chain"
+	"TON/internal/gating"
+	"TON/pkg/address"
+	"TON/pkg/tonwallet"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+var (
+	ErrClientNotFound     = errors.New("client not found")
+	ErrRedirectNotAllowed = errors.New("redirect_uri is not registered for client")
+)
+
+// Client is a dApp registered with the service.
+type Client struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	URL              string   `json:"url"`
+	IconURL          string   `json:"iconUrl,omitempty"`
+	IconPath         string   `json:"iconPath,omitempty"`
+	TermsOfUseURL    string   `json:"termsOfUseUrl,omitempty"`
+	PrivacyPolicyURL string   `json:"privacyPolicyUrl,omitempty"`
+	RedirectURIs     []string `json:"redirectUris,omitempty"`
+	// Network pins the client to a TonConnect chain id ("-239" or "-3").
+	Network string `json:"network,omitempty"`
+	// AddressFormat of wallet addresses in responses: raw, bounceable or non-bounceable.
+	AddressFormat string `json:"addressFormat,omitempty"`
+	// WalletVersions overrides the wallet version preference of the service.
+	WalletVersions []string `json:"walletVersions,omitempty"`
+	// AllowlistOnly admits only wallets on the global allow list or the one of the client.
+	AllowlistOnly bool `json:"allowlistOnly,omitempty"`
+	// RequireDNS rejects wallets without a TON DNS name resolving back to them.
+	RequireDNS bool `json:"requireDns,omitempty"`
+	// Gating holds the login requirements of the client and the claims they grant.
+	Gating *gating.Rules `json:"gating,omitempty"`
+
+	preference tonwallet.Preference
+}
+
+// Domain returns the host of the client manifest URL, which wallets put into ton_proof.
+func (c *Client) Domain() string {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// Preference returns the wallet version preference of the client, nil when it uses the default.
+func (c *Client) Preference() tonwallet.Preference {
+	return c.preference
+}
+
+// AllowsRedirect reports whether the redirect URI is registered for the client.
+// Clients without registered redirect URIs accept any.
+func (c *Client) AllowsRedirect(uri string) bool {
+	if len(c.RedirectURIs) == 0 {
+		return true
+	}
+	for _, allowed := range c.RedirectURIs {
+		if allowed == uri {
+			return true
+		}
+	}
+	return false
+}
+
+type Registry struct {
+	clients map[string]*Client
+}
+
+// Load reads registered clients from a JSON file. A missing file yields an empty registry.
+// Gating rules are checked against the activityScanLimit of the evaluator.
+func Load(path string, activityScanLimit int) (*Registry, error) {
+	r := &Registry{clients: make(map[string]*Client)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return r, nil
+		}
+		return nil, err
+	}
+
+	var file struct {
+		Clients []*Client `json:"clients"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse clients file: %w", err)
+	}
+
+	for _, c := range file.Clients {
+		if err := c.validate(activityScanLimit); err != nil {
+			return nil, fmt.Errorf("client %q: %w", c.ID, err)
+		}
+		if _, exists := r.clients[c.ID]; exists {
+			return nil, fmt.Errorf("client %q is registered twice", c.ID)
+		}
+		r.clients[c.ID] = c
+	}
+
+	return r, nil
+}
+
+func (r *Registry) Get(id string) (*Client, error) {
+	c, ok := r.clients[id]
+	if !ok {
+		return nil, ErrClientNotFound
+	}
+	return c, nil
+}
+
+func (c *Client) validate(activityScanLimit int) error {
+	if c.ID == "" {
+		return errors.New("id is required")
+	}
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return errors.New("url must be an absolute URL")
+	}
+	if c.IconURL == "" && c.IconPath == "" {
+		return errors.New("either iconUrl or iconPath is required")
+	}
+	if c.Network != "" {
+		network, err := chain.ParseNetwork(c.Network)
+		if err != nil {
+			return err
+		}
+		c.Network = string(network)
+	}
+	if c.AddressFormat != "" {
+		format, err := address.ParseFormat(c.AddressFormat)
+		if err != nil {
+			return fmt.Errorf("addressFormat: %w", err)
+		}
+		c.AddressFormat = string(format)
+	}
+	if len(c.WalletVersions) > 0 {
+		preference, err := tonwallet.ParsePreferenceList(c.WalletVersions)
+		if err != nil {
+			return fmt.Errorf("walletVersions: %w", err)
+		}
+		c.preference = preference
+	}
+	if c.Gating != nil {
+		if err := c.Gating.Prepare(activityScanLimit); err != nil {
+			return fmt.Errorf("gating: %w", err)
+		}
+	}
+	return nil
+}
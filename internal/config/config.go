@@ -2,18 +2,120 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
 	HTTPServerPort int    `env:"HTTP_SERVER_PORT" env-default:"8080"`
+	TrustedProxies string `env:"TRUSTED_PROXIES" env-default:""`
 	PrivateKeyPath string `env:"PRIVATE_KEY_PATH" env-default:"key/private.pem"`
 	PublicKeyPath  string `env:"PUBLIC_KEY_PATH" env-default:"key/public.pem"`
 	Issuer         string `env:"ISSUER" env-default:"TON-OAUTH"`
 	KeyName        string `env:"KEY_NAME" env-default:"main-key"`
 	ApiKey         string `env:"API_KEY" env-default:"api-key"`
 	ApiURL         string `env:"API_URL" env-default:"https://tonapi.io"`
+	PublicURL      string `env:"PUBLIC_URL" env-default:"http://localhost:8080"`
+	ClientsPath    string `env:"CLIENTS_PATH" env-default:"conf/clients.json"`
+
+	ChainProvider        string `env:"CHAIN_PROVIDER" env-default:"tonapi"`
+	ToncenterURL         string `env:"TONCENTER_URL" env-default:"https://toncenter.com"`
+	ToncenterAPIKey      string `env:"TONCENTER_API_KEY" env-default:""`
+	ChainFixturePath     string `env:"CHAIN_FIXTURE_PATH" env-default:""`
+	LiteServerConfig     string `env:"LITESERVER_CONFIG" env-default:"https://ton-blockchain.github.io/global.config.json"`
+	LiteServerProofCheck string `env:"LITESERVER_PROOF_CHECK" env-default:"secure"`
+
+	DefaultNetwork          string `env:"DEFAULT_NETWORK" env-default:"-239"`
+	TestnetEnabled          bool   `env:"TESTNET_ENABLED" env-default:"false"`
+	TestnetApiURL           string `env:"TESTNET_API_URL" env-default:"https://testnet.tonapi.io"`
+	TestnetToncenterURL     string `env:"TESTNET_TONCENTER_URL" env-default:"https://testnet.toncenter.com"`
+	TestnetToncenterAPIKey  string `env:"TESTNET_TONCENTER_API_KEY" env-default:""`
+	TestnetLiteServerConfig string `env:"TESTNET_LITESERVER_CONFIG" env-default:"https://ton-blockchain.github.io/testnet-global.config.json"`
+	TestnetDNSRoot          string `env:"TESTNET_DNS_ROOT" env-default:""`
+
+	WalletWorkchain     int8   `env:"WALLET_WORKCHAIN" env-default:"0"`
+	WalletSubwalletID   uint32 `env:"WALLET_SUBWALLET_ID" env-default:"698983191"`
+	WalletV5SubwalletID uint16 `env:"WALLET_V5_SUBWALLET_ID" env-default:"0"`
+	WalletPreference    string `env:"WALLET_PREFERENCE" env-default:"v5r1,v4r2,v3r2,v3r1"`
+	AddressFormat       string `env:"ADDRESS_FORMAT" env-default:"raw"`
+
+	BalanceMaxAge time.Duration `env:"BALANCE_MAX_AGE" env-default:"1m"`
+
+	ActivityMaxAge    time.Duration `env:"ACTIVITY_MAX_AGE" env-default:"1h"`
+	ActivityScanLimit int           `env:"ACTIVITY_SCAN_LIMIT" env-default:"1000"`
+
+	PaymentsMaxAge    time.Duration `env:"PAYMENTS_MAX_AGE" env-default:"30s"`
+	PaymentsScanLimit int           `env:"PAYMENTS_SCAN_LIMIT" env-default:"10000"`
+
+	DNSClaims bool          `env:"DNS_CLAIMS" env-default:"true"`
+	DNSMaxAge time.Duration `env:"DNS_MAX_AGE" env-default:"10m"`
+
+	PoliciesPath string `env:"POLICIES_PATH" env-default:"conf/policies"`
+	AdminToken   string `env:"ADMIN_TOKEN" env-default:""`
+
+	ListsPath     string `env:"LISTS_PATH" env-default:"data/lists.json"`
+	AllowlistOnly bool   `env:"ALLOWLIST_ONLY" env-default:"false"`
+	AuditLogPath  string `env:"AUDIT_LOG_PATH" env-default:"data/audit.log"`
+
+	ScreeningEnabled            bool          `env:"SCREENING_ENABLED" env-default:"false"`
+	ScreeningPath               string        `env:"SCREENING_PATH" env-default:"conf/screening"`
+	ScreeningRefresh            time.Duration `env:"SCREENING_REFRESH" env-default:"10m"`
+	ScreeningAction             string        `env:"SCREENING_ACTION" env-default:"block"`
+	ScreeningListActions        string        `env:"SCREENING_LIST_ACTIONS" env-default:""`
+	ScreeningCounterparties     bool          `env:"SCREENING_COUNTERPARTIES" env-default:"false"`
+	ScreeningCounterpartyAction string        `env:"SCREENING_COUNTERPARTY_ACTION" env-default:"flag"`
+	ScreeningCounterpartyLimit  int           `env:"SCREENING_COUNTERPARTY_LIMIT" env-default:"50"`
+
+	PoWEnabled       bool   `env:"POW_ENABLED" env-default:"false"`
+	PoWSecret        string `env:"POW_SECRET" env-default:""`
+	PoWDifficulty    int    `env:"POW_DIFFICULTY" env-default:"16"`
+	PoWMaxDifficulty int    `env:"POW_MAX_DIFFICULTY" env-default:"24"`
+	PoWLoadThreshold int    `env:"POW_LOAD_THRESHOLD" env-default:"600"`
+	PoWIPThreshold   int    `env:"POW_IP_THRESHOLD" env-default:"10"`
+
+	TxAuthEnabled    bool          `env:"TX_AUTH_ENABLED" env-default:"false"`
+	TxAuthAddress    string        `env:"TX_AUTH_ADDRESS" env-default:""`
+	TxAuthAmount     string        `env:"TX_AUTH_AMOUNT" env-default:"0.01"`
+	TxAuthTTL        time.Duration `env:"TX_AUTH_TTL" env-default:"10m"`
+	TxAuthPoll       time.Duration `env:"TX_AUTH_POLL" env-default:"5s"`
+	TxAuthMaxPending int           `env:"TX_AUTH_MAX_PENDING" env-default:"10000"`
+
+	MultisigEnabled     bool          `env:"MULTISIG_ENABLED" env-default:"false"`
+	MultisigTTL         time.Duration `env:"MULTISIG_TTL" env-default:"15m"`
+	MultisigMaxSessions int           `env:"MULTISIG_MAX_SESSIONS" env-default:"1000"`
+
+	SignDataMaxAge         time.Duration `env:"SIGN_DATA_MAX_AGE" env-default:"15m"`
+	SignDataAttestationTTL time.Duration `env:"SIGN_DATA_ATTESTATION_TTL" env-default:"24h"`
+
+	CredentialTTL        time.Duration `env:"CREDENTIAL_TTL" env-default:"720h"`
+	CredentialKeyPath    string        `env:"CREDENTIAL_KEY_PATH" env-default:""`
+	CredentialKeyName    string        `env:"CREDENTIAL_KEY_NAME" env-default:"credential-key"`
+	CredentialStatusPath string        `env:"CREDENTIAL_STATUS_PATH" env-default:"data/credentials.json"`
+	CredentialStatusSize int           `env:"CREDENTIAL_STATUS_SIZE" env-default:"131072"`
+
+	BridgeEnabled        bool          `env:"BRIDGE_ENABLED" env-default:"false"`
+	BridgeMaxTTL         time.Duration `env:"BRIDGE_MAX_TTL" env-default:"5m"`
+	BridgeHeartbeat      time.Duration `env:"BRIDGE_HEARTBEAT" env-default:"15s"`
+	BridgeMaxConnections int           `env:"BRIDGE_MAX_CONNECTIONS" env-default:"5"`
+	BridgeBufferSize     int           `env:"BRIDGE_BUFFER_SIZE" env-default:"100"`
+	BridgeMaxClientIDs   int           `env:"BRIDGE_MAX_CLIENT_IDS" env-default:"10"`
+}
+
+// redacted replaces the secrets when the configuration is printed.
+const redacted = "[REDACTED]"
+
+// String formats the configuration with the API keys, the admin token and the proof of
+// work secret redacted, so it can be logged.
+func (c Config) String() string {
+	type plain Config
+	p := plain(c)
+	for _, secret := range []*string{&p.ApiKey, &p.ToncenterAPIKey, &p.TestnetToncenterAPIKey, &p.AdminToken, &p.PoWSecret} {
+		if *secret != "" {
+			*secret = redacted
+		}
+	}
+	return fmt.Sprintf("%+v", p)
 }
 
 func New() *Config {
@@ -24,6 +126,6 @@ func New() *Config {
 		return nil
 	}
 
-	fmt.Printf("Загружена конфигурация: %+v\n", cfg)
+	fmt.Printf("Загружена конфигурация: %v\n", cfg)
 	return &cfg
 }
@@ -0,0 +1,81 @@
+package credential
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	contextV1         = "https://www.w3.org/2018/credentials/v1"
+	contextStatusList = "https://w3id.org/vc/status-list/2021/v1"
+
+	// Type of the credentials attesting control of a TON wallet.
+	Type = "TonWalletCredential"
+)
+
+// NewID returns a random urn:uuid credential ID.
+func NewID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// Claims returns the JWT claims of a verifiable credential in the JWT encoding of the
+// VC data model 1.1, with a StatusList2021 revocation entry in the list at statusList.
+func Claims(issuer, statusList string, r *Record, subject map[string]interface{}) map[string]interface{} {
+	credentialSubject := map[string]interface{}{"id": r.Subject}
+	for k, v := range subject {
+		credentialSubject[k] = v
+	}
+
+	return map[string]interface{}{
+		"iss": issuer,
+		"sub": r.Subject,
+		"jti": r.ID,
+		"iat": r.IssuedAt.Unix(),
+		"nbf": r.IssuedAt.Unix(),
+		"exp": r.ExpiresAt.Unix(),
+		"vc": map[string]interface{}{
+			"@context":          []string{contextV1, contextStatusList},
+			"type":              []string{"VerifiableCredential", Type},
+			"credentialSubject": credentialSubject,
+			"credentialStatus": map[string]interface{}{
+				"id":                   statusList + "#" + strconv.Itoa(r.Index),
+				"type":                 "StatusList2021Entry",
+				"statusPurpose":        "revocation",
+				"statusListIndex":      strconv.Itoa(r.Index),
+				"statusListCredential": statusList,
+			},
+		},
+	}
+}
+
+// StatusListClaims returns the JWT claims of the StatusList2021 credential published at
+// statusList, valid until exp.
+func StatusListClaims(issuer, statusList, encodedList string, now, exp time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"iss": issuer,
+		"sub": statusList,
+		"jti": statusList,
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+		"exp": exp.Unix(),
+		"vc": map[string]interface{}{
+			"@context": []string{contextV1, contextStatusList},
+			"id":       statusList,
+			"type":     []string{"VerifiableCredential", "StatusList2021Credential"},
+			"credentialSubject": map[string]interface{}{
+				"id":            statusList + "#list",
+				"type":          "StatusList2021",
+				"statusPurpose": "revocation",
+				"encodedList":   encodedList,
+			},
+		},
+	}
+}
@@ -0,0 +1,233 @@
+package credential
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("credential not found")
+	ErrRevoked  = errors.New("credential already revoked")
+	// ErrFull is returned when every index of the status list belongs to a credential
+	// that has not expired.
+	ErrFull = errors.New("status list is full")
+)
+
+// MinSize is the smallest status list, 16KB as required for herd privacy.
+const MinSize = 131072
+
+// Record is an issued credential and its index in the status list.
+type Record struct {
+	// ID of the credential, a urn:uuid.
+	ID    string `json:"id"`
+	Index int    `json:"index"`
+	// Subject is the DID the credential was issued to.
+	Subject string `json:"subject"`
+	// Address of the wallet in the raw "wc:hex" form.
+	Address   string     `json:"address"`
+	ClientID  string     `json:"clientId,omitempty"`
+	IssuedAt  time.Time  `json:"issuedAt"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+}
+
+// Expired reports whether the credential is no longer valid at now.
+func (r *Record) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// Store keeps the issued credentials in a JSON file and hands out their status list
+// indexes. The index of an expired credential is handed out again.
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	size    int
+	next    int
+	records map[string]*Record
+	indexes map[int]*Record
+}
+
+type storeFile struct {
+	Next    int       `json:"next"`
+	Records []*Record `json:"records"`
+}
+
+// Open loads the credentials from path. A missing file yields an empty store; without
+// a path the credentials are not persisted. size is raised to MinSize and rounded up to
+// whole bytes.
+func Open(path string, size int) (*Store, error) {
+	s := &Store{
+		path:    path,
+		size:    (max(size, MinSize) + 7) / 8 * 8,
+		records: make(map[string]*Record),
+		indexes: make(map[int]*Record),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	s.next = file.Next
+	for _, r := range file.Records {
+		if r.Index < 0 || r.Index >= s.size {
+			return nil, fmt.Errorf("credential %s: index %d outside of the status list", r.ID, r.Index)
+		}
+		s.records[r.ID] = r
+		s.indexes[r.Index] = r
+	}
+	return s, nil
+}
+
+// Issue assigns the next free status list index to the credential and stores it.
+func (s *Store) Issue(r Record) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune(time.Now())
+	index := -1
+	for i := 0; i < s.size; i++ {
+		if candidate := (s.next + i) % s.size; s.indexes[candidate] == nil {
+			index = candidate
+			break
+		}
+	}
+	if index < 0 {
+		return nil, ErrFull
+	}
+
+	r.Index = index
+	s.next = index + 1
+	s.records[r.ID] = &r
+	s.indexes[index] = &r
+	if err := s.save(); err != nil {
+		return nil, err
+	}
+	c := r
+	return &c, nil
+}
+
+// Revoke sets the status bit of the credential.
+func (s *Store) Revoke(id, reason string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.records[id]
+	if !ok || r.Expired(time.Now()) {
+		return nil, ErrNotFound
+	}
+	if r.RevokedAt != nil {
+		return nil, ErrRevoked
+	}
+	now := time.Now().UTC()
+	r.RevokedAt, r.Reason = &now, reason
+	if err := s.save(); err != nil {
+		return nil, err
+	}
+	c := *r
+	return &c, nil
+}
+
+// List returns the credentials that have not expired, of every wallet when addr is empty.
+func (s *Store) List(addr string) []Record {
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]Record, 0)
+	for _, r := range s.records {
+		if r.Expired(now) || (addr != "" && r.Address != addr) {
+			continue
+		}
+		records = append(records, *r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].IssuedAt.Before(records[j].IssuedAt)
+	})
+	return records
+}
+
+// EncodedList returns the revocation bitstring as StatusList2021 encodedList: gzip
+// compressed and base64url encoded, the bit of index 0 first.
+func (s *Store) EncodedList() (string, error) {
+	bits := make([]byte, s.size/8)
+	now := time.Now()
+
+	s.mu.RLock()
+	for index, r := range s.indexes {
+		if r.RevokedAt != nil && !r.Expired(now) {
+			bits[index/8] |= 0x80 >> (index % 8)
+		}
+	}
+	s.mu.RUnlock()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(bits); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// prune forgets expired credentials, freeing their indexes.
+func (s *Store) prune(now time.Time) {
+	for id, r := range s.records {
+		if r.Expired(now) {
+			delete(s.records, id)
+			delete(s.indexes, r.Index)
+		}
+	}
+}
+
+func (s *Store) save() error {
+	s.prune(time.Now())
+	if s.path == "" {
+		return nil
+	}
+
+	file := storeFile{Next: s.next, Records: make([]*Record, 0, len(s.records))}
+	for _, r := range s.records {
+		file.Records = append(file.Records, r)
+	}
+	sort.Slice(file.Records, func(i, j int) bool {
+		return file.Records[i].Index < file.Records[j].Index
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	// write a temporary file first, so a crash never leaves truncated records behind
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
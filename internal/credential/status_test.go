@@ -0,0 +1,112 @@
+package credential
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// decodeList reverses EncodedList: base64url, then gzip.
+func decodeList(t *testing.T, encoded string) []byte {
+	t.Helper()
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bits
+}
+
+func TestEncodedList(t *testing.T) {
+	now := time.Now().UTC()
+	revoked := now.Add(-time.Minute)
+	record := func(index int, expiresAt time.Time, revokedAt *time.Time) *Record {
+		return &Record{ID: fmt.Sprintf("urn:uuid:%d", index), Index: index, IssuedAt: now, ExpiresAt: expiresAt, RevokedAt: revokedAt}
+	}
+	data, err := json.Marshal(storeFile{Next: 10, Records: []*Record{
+		record(0, now.Add(time.Hour), &revoked),
+		record(1, now.Add(time.Hour), nil),
+		record(2, now.Add(-time.Hour), &revoked),
+		record(9, now.Add(time.Hour), &revoked),
+		record(MinSize-1, now.Add(time.Hour), &revoked),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := s.EncodedList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits := decodeList(t, encoded)
+	if len(bits) != MinSize/8 {
+		t.Fatalf("len(bits) = %d, want %d", len(bits), MinSize/8)
+	}
+
+	// index 0 is the most significant bit of the first byte; the expired credential
+	// at index 2 no longer counts
+	want := map[int]byte{0: 0x80, 1: 0x40, MinSize/8 - 1: 0x01}
+	for i, b := range bits {
+		if b != want[i] {
+			t.Fatalf("bits[%d] = %#02x, want %#02x", i, b, want[i])
+		}
+	}
+}
+
+func TestStoreIssueRevoke(t *testing.T) {
+	s, err := Open("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := time.Now().Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		r, err := s.Issue(Record{ID: fmt.Sprintf("urn:uuid:%d", i), ExpiresAt: expiresAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Index != i {
+			t.Fatalf("Issue() index = %d, want %d", r.Index, i)
+		}
+	}
+
+	if _, err := s.Revoke("urn:uuid:1", "compromised"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Revoke("urn:uuid:1", "compromised"); !errors.Is(err, ErrRevoked) {
+		t.Fatalf("Revoke() twice error = %v, want %v", err, ErrRevoked)
+	}
+	if _, err := s.Revoke("urn:uuid:7", ""); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Revoke() of an unknown credential error = %v, want %v", err, ErrNotFound)
+	}
+
+	encoded, err := s.EncodedList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bits := decodeList(t, encoded); bits[0] != 0x40 {
+		t.Fatalf("bits[0] = %#02x, want 0x40", bits[0])
+	}
+}
@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+// AuditEventDTO represents an entry of the audit trail.
+// swagger:model
+type AuditEventDTO struct {
+	// Time of the event
+	// required: true
+	// example: 2025-09-07T00:00:00Z
+	Time time.Time `json:"time" example:"2025-09-07T00:00:00Z"`
+
+	// Admin or component that caused the event
+	// required: true
+	// example: alice
+	Actor string `json:"actor" example:"alice"`
+
+	// IP address of the actor
+	// example: 203.0.113.7
+	IP string `json:"ip,omitempty" example:"203.0.113.7"`
+
+	// Action that was taken
+	// required: true
+	// example: list.add
+	Action string `json:"action" example:"list.add"`
+
+	// Wallet or object the action was taken on
+	// example: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Target string `json:"target,omitempty" example:"0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// Client the action concerns
+	// example: my-dapp
+	ClientID string `json:"client_id,omitempty" example:"my-dapp"`
+
+	// Details of the action
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// AuditResponseDTO represents the latest events of the audit trail.
+// swagger:model
+type AuditResponseDTO struct {
+	// Events, newest first
+	// required: true
+	Events []AuditEventDTO `json:"events"`
+}
@@ -10,9 +10,20 @@ type AuthorizeRequestDTO struct {
 	// example: https://example.com/callback
 	RedirectURI string `json:"redirect_uri" validate:"required,url"`
 
+	// Optional ID of a registered client
+	// example: my-dapp
+	ClientID string `json:"client_id,omitempty"`
+
 	// Optional scope of the access request
 	// example: read write
 	Scope string `json:"scope,omitempty"`
+
+	// Login mode: signature (default) or transaction, for wallets that cannot sign messages
+	// example: transaction
+	Mode string `json:"mode,omitempty" validate:"omitempty,oneof=signature transaction"`
+
+	// IP address of the caller, set by the handler
+	IP string `json:"-" swaggerignore:"true"`
 }
 
 // AuthorizeResponseDTO represents the response containing the authorization challenge.
@@ -33,4 +44,35 @@ type AuthorizeResponseDTO struct {
 	// Expiration time of the challenge
 	// example: 2025-09-07T00:00:00Z
 	ExpiresAt time.Time `json:"expiresAt"`
+
+	// Proof-of-work puzzle to solve before verifying, present when proof of work is enabled
+	PoW *PoWChallengeDTO `json:"pow,omitempty"`
+
+	// Transfer to send in transaction mode; the challenge is then kept secret and used to
+	// poll /oauth/transaction/{challenge}
+	Transaction *TransactionChallengeDTO `json:"transaction,omitempty"`
+}
+
+// PoWChallengeDTO represents a hashcash style puzzle: find a nonce so that the hash of
+// "challenge:nonce" starts with difficulty zero bits.
+// swagger:model
+type PoWChallengeDTO struct {
+	// Signed challenge, valid once and only from the IP address it was issued to
+	// required: true
+	// example: q8Yk3n0cR1m2Z6bq0h6V7wAAAABo7c2AEHk0m1d3xq3uJb5kS9QqP2g
+	Challenge string `json:"challenge" example:"q8Yk3n0cR1m2Z6bq0h6V7wAAAABo7c2AEHk0m1d3xq3uJb5kS9QqP2g"`
+
+	// Number of leading zero bits the hash must have
+	// required: true
+	// example: 16
+	Difficulty int `json:"difficulty" example:"16"`
+
+	// Hash algorithm of the puzzle
+	// required: true
+	// example: sha256
+	Algorithm string `json:"algorithm" example:"sha256"`
+
+	// Expiration time of the puzzle
+	// example: 2025-09-07T00:00:00Z
+	ExpiresAt time.Time `json:"expiresAt" example:"2025-09-07T00:00:00Z"`
 }
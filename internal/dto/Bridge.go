@@ -0,0 +1,51 @@
+package dto
+
+// BridgeMessageRequestDTO represents a TonConnect bridge message sent by a wallet or dApp.
+// swagger:model
+type BridgeMessageRequestDTO struct {
+	// Hex encoded public key of the sender session
+	// required: true
+	// example: 6b5f4a3cc0f1d53d3d0e1d8a9f7a3f1b2c4d5e6f708192a3b4c5d6e7f8091a2b
+	ClientID string `json:"client_id" validate:"required,hexadecimal,len=64"`
+
+	// Hex encoded public key of the recipient session
+	// required: true
+	// example: 0f1d53d3d0e1d8a9f7a3f1b2c4d5e6f708192a3b4c5d6e7f8091a2b6b5f4a3cc
+	To string `json:"to" validate:"required,hexadecimal,len=64"`
+
+	// Message time to live in seconds
+	// required: true
+	// example: 300
+	TTL int `json:"ttl" validate:"required,gt=0"`
+
+	// Optional topic of the message
+	// example: sendTransaction
+	Topic string `json:"topic,omitempty"`
+
+	// Base64 encoded encrypted message body
+	// required: true
+	Message string `json:"message" validate:"required,base64"`
+}
+
+// BridgeResponseDTO represents the bridge protocol response.
+// swagger:model
+type BridgeResponseDTO struct {
+	// Result message
+	// example: OK
+	Message string `json:"message" example:"OK"`
+
+	// HTTP status code
+	// example: 200
+	StatusCode int `json:"statusCode" example:"200"`
+}
+
+// BridgeEventDTO represents a message delivered over the event stream.
+// swagger:model
+type BridgeEventDTO struct {
+	// Hex encoded public key of the sender session
+	// example: 6b5f4a3cc0f1d53d3d0e1d8a9f7a3f1b2c4d5e6f708192a3b4c5d6e7f8091a2b
+	From string `json:"from"`
+
+	// Base64 encoded encrypted message body
+	Message string `json:"message"`
+}
@@ -0,0 +1,91 @@
+package dto
+
+import "time"
+
+// CredentialRequestDTO represents a request to issue a verifiable credential after
+// verifying a TON wallet like the token request.
+// swagger:model
+type CredentialRequestDTO struct {
+	VerifyRequestDTO
+
+	// DID the credential is issued to: ton for did:ton of the wallet (default) or key for
+	// did:key of its public key
+	// example: ton
+	Subject string `json:"subject,omitempty" validate:"omitempty,oneof=ton key" example:"ton"`
+
+	// Facts about the wallet to include: dns for its TON DNS name, nft for the collections
+	// matched by the gating rules of the client. Facts the wallet lacks are left out.
+	// example: ["dns","nft"]
+	Facts []string `json:"facts,omitempty" validate:"omitempty,dive,oneof=dns nft" example:"dns,nft"`
+}
+
+// CredentialResponseDTO represents an issued verifiable credential.
+// swagger:model
+type CredentialResponseDTO struct {
+	// Verifiable credential in JWT format
+	// required: true
+	// example: eyJhbGciOiJSUzI1NiIsImtpZCI6Im1haW4ta2V5IiwidHlwIjoiSldUIn0...
+	Credential string `json:"credential" example:"eyJhbGciOiJSUzI1NiIsImtpZCI6Im1haW4ta2V5IiwidHlwIjoiSldUIn0..."`
+
+	// ID of the credential
+	// example: urn:uuid:3978344f-8596-4c3a-a978-8fcaba3903c5
+	ID string `json:"id" example:"urn:uuid:3978344f-8596-4c3a-a978-8fcaba3903c5"`
+
+	// DID of the subject
+	// example: did:ton:0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Subject string `json:"subject" example:"did:ton:0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// Index of the credential in the revocation status list
+	// example: 42
+	StatusListIndex int `json:"statusListIndex" example:"42"`
+
+	// Expiration time of the credential
+	// example: 2025-10-07T00:00:00Z
+	ExpiresAt time.Time `json:"expiresAt" example:"2025-10-07T00:00:00Z"`
+}
+
+// CredentialRecordDTO represents an issued credential that has not expired.
+// swagger:model
+type CredentialRecordDTO struct {
+	// ID of the credential
+	// example: urn:uuid:3978344f-8596-4c3a-a978-8fcaba3903c5
+	ID string `json:"id" example:"urn:uuid:3978344f-8596-4c3a-a978-8fcaba3903c5"`
+
+	// Index of the credential in the revocation status list
+	// example: 42
+	StatusListIndex int `json:"statusListIndex" example:"42"`
+
+	// DID of the subject
+	// example: did:ton:0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Subject string `json:"subject" example:"did:ton:0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// Raw address of the wallet
+	// example: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Address string `json:"address" example:"0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// Client the wallet logged in to
+	// example: my-dapp
+	ClientID string `json:"clientId,omitempty" example:"my-dapp"`
+
+	// Issuance time
+	// example: 2025-09-07T00:00:00Z
+	IssuedAt time.Time `json:"issuedAt" example:"2025-09-07T00:00:00Z"`
+
+	// Expiration time
+	// example: 2025-10-07T00:00:00Z
+	ExpiresAt time.Time `json:"expiresAt" example:"2025-10-07T00:00:00Z"`
+
+	// Revocation time, empty while the credential is valid
+	// example: 2025-09-08T00:00:00Z
+	RevokedAt *time.Time `json:"revokedAt,omitempty" example:"2025-09-08T00:00:00Z"`
+
+	// Reason of the revocation
+	// example: wallet compromised
+	Reason string `json:"reason,omitempty" example:"wallet compromised"`
+}
+
+// CredentialsResponseDTO represents the issued credentials that have not expired.
+// swagger:model
+type CredentialsResponseDTO struct {
+	Credentials []CredentialRecordDTO `json:"credentials"`
+}
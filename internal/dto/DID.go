@@ -0,0 +1,35 @@
+package dto
+
+import "TON/pkg/did"
+
+// DIDResolutionDTO represents a DID resolution result of the Universal Resolver driver API.
+// swagger:model
+type DIDResolutionDTO struct {
+	// JSON-LD context of the resolution result
+	// example: https://w3id.org/did-resolution/v1
+	Context string `json:"@context" example:"https://w3id.org/did-resolution/v1"`
+
+	// Resolved DID document, null when the resolution failed
+	DIDDocument *did.Document `json:"didDocument"`
+
+	DIDResolutionMetadata DIDResolutionMetadataDTO `json:"didResolutionMetadata"`
+
+	// Metadata of the wallet behind the document
+	DIDDocumentMetadata did.Metadata `json:"didDocumentMetadata"`
+}
+
+// DIDResolutionMetadataDTO represents the metadata of a DID resolution.
+// swagger:model
+type DIDResolutionMetadataDTO struct {
+	// Media type of the document
+	// example: application/did+ld+json
+	ContentType string `json:"contentType,omitempty" example:"application/did+ld+json"`
+
+	// Error code of a failed resolution: invalidDid, notFound, methodNotSupported or internalError
+	// example: notFound
+	Error string `json:"error,omitempty" example:"notFound"`
+
+	// Description of the error
+	// example: DID not found: wallet does not exist
+	ErrorMessage string `json:"errorMessage,omitempty" example:"DID not found: wallet does not exist"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// FixtureTransactionRequestDTO represents an incoming transfer added to the fixture chain
+// provider, to try out transaction mode logins locally.
+// swagger:model
+type FixtureTransactionRequestDTO struct {
+	// TonConnect chain id of the network, the default network when empty
+	// example: -239
+	Network string `json:"network,omitempty" example:"-239"`
+
+	// Account receiving the transfer
+	// required: true
+	// example: 0:77a6f2e1b9b4c0cd5a0a1b65e4c5f73a2f2f3c5fb0d1c0f0a3c7d5e8f9a0b1c2
+	Address string `json:"address" validate:"required" example:"0:77a6f2e1b9b4c0cd5a0a1b65e4c5f73a2f2f3c5fb0d1c0f0a3c7d5e8f9a0b1c2"`
+
+	// Wallet sending the transfer
+	// required: true
+	// example: 0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869
+	From string `json:"from" validate:"required" example:"0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869"`
+
+	// Value in nanotons
+	// required: true
+	// example: 10000000
+	Value int64 `json:"value" validate:"gte=0" example:"10000000"`
+
+	// Text comment of the transfer
+	// example: ton-oauth-mfrggzdfmztwq2lk
+	Comment string `json:"comment,omitempty" example:"ton-oauth-mfrggzdfmztwq2lk"`
+}
+
+// FixtureTransactionResponseDTO represents the transaction added to the fixture.
+// swagger:model
+type FixtureTransactionResponseDTO struct {
+	// Hash of the transaction in hex
+	// required: true
+	Hash string `json:"hash"`
+
+	// Logical time of the transaction, after every other transaction of the account
+	// required: true
+	LT uint64 `json:"lt"`
+
+	// Time of the transaction
+	// required: true
+	Time time.Time `json:"time"`
+}
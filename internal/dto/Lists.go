@@ -0,0 +1,76 @@
+package dto
+
+import "time"
+
+// ListEntryRequestDTO represents a request to put a wallet on the allow or deny list.
+// swagger:model
+type ListEntryRequestDTO struct {
+	// Wallet address in any form
+	// required: true
+	// example: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Address string `json:"address" validate:"required,ton_address" example:"0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// Client the entry applies to, empty for all clients
+	// example: my-dapp
+	ClientID string `json:"client_id,omitempty" example:"my-dapp"`
+
+	// Why the wallet is listed
+	// example: spam registrations
+	Reason string `json:"reason,omitempty" validate:"max=500" example:"spam registrations"`
+
+	// Time the entry expires, it never expires when neither expires_at nor ttl is set
+	// example: 2026-01-01T00:00:00Z
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2026-01-01T00:00:00Z"`
+
+	// Lifetime of the entry as a Go duration, an alternative to expires_at
+	// example: 720h
+	TTL string `json:"ttl,omitempty" validate:"excluded_with=ExpiresAt" example:"720h"`
+
+	// Name of the admin making the change, set by the handler from X-Admin-Actor
+	Actor string `json:"-" swaggerignore:"true"`
+	// IP address of the admin, set by the handler
+	IP string `json:"-" swaggerignore:"true"`
+}
+
+// ListEntryDTO represents a wallet on the allow or deny list.
+// swagger:model
+type ListEntryDTO struct {
+	// List of the entry: allow or deny
+	// required: true
+	// example: deny
+	Kind string `json:"kind" example:"deny"`
+
+	// Raw wallet address
+	// required: true
+	// example: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Address string `json:"address" example:"0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// Client the entry applies to, empty for all clients
+	// example: my-dapp
+	ClientID string `json:"client_id,omitempty" example:"my-dapp"`
+
+	// Why the wallet is listed
+	// example: spam registrations
+	Reason string `json:"reason,omitempty" example:"spam registrations"`
+
+	// Time the entry expires
+	// example: 2026-01-01T00:00:00Z
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2026-01-01T00:00:00Z"`
+
+	// Time the entry was created
+	// required: true
+	// example: 2025-09-07T00:00:00Z
+	CreatedAt time.Time `json:"created_at" example:"2025-09-07T00:00:00Z"`
+
+	// Admin who created the entry
+	// example: alice
+	CreatedBy string `json:"created_by,omitempty" example:"alice"`
+}
+
+// ListEntriesResponseDTO represents the entries of a list.
+// swagger:model
+type ListEntriesResponseDTO struct {
+	// Entries that have not expired, oldest first
+	// required: true
+	Entries []ListEntryDTO `json:"entries"`
+}
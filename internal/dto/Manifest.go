@@ -0,0 +1,28 @@
+package dto
+
+// TonConnectManifestDTO represents a tonconnect-manifest.json of a registered client.
+// swagger:model
+type TonConnectManifestDTO struct {
+	// URL of the dApp, its host is expected as the ton_proof domain
+	// required: true
+	// example: https://example.com
+	URL string `json:"url" example:"https://example.com"`
+
+	// Name of the dApp shown in the wallet
+	// required: true
+	// example: Example dApp
+	Name string `json:"name" example:"Example dApp"`
+
+	// URL of the dApp icon
+	// required: true
+	// example: https://auth.example.com/clients/my-dapp/icon
+	IconURL string `json:"iconUrl" example:"https://auth.example.com/clients/my-dapp/icon"`
+
+	// Optional URL of the terms of use
+	// example: https://example.com/terms
+	TermsOfUseURL string `json:"termsOfUseUrl,omitempty" example:"https://example.com/terms"`
+
+	// Optional URL of the privacy policy
+	// example: https://example.com/privacy
+	PrivacyPolicyURL string `json:"privacyPolicyUrl,omitempty" example:"https://example.com/privacy"`
+}
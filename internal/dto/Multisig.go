@@ -0,0 +1,120 @@
+package dto
+
+import "time"
+
+// MultisigStartRequestDTO represents a request to start a login as a multisig contract.
+// swagger:model
+type MultisigStartRequestDTO struct {
+	// Address of the multisig contract
+	// required: true
+	// example: 0:3b4c8f7e0a5d2c1b9e8f7a6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29
+	Address string `json:"address" validate:"required,ton_address" example:"0:3b4c8f7e0a5d2c1b9e8f7a6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29"`
+
+	// ID of the registered client, required for ton_proof signatures
+	// example: my-dapp
+	ClientID string `json:"client_id,omitempty" example:"my-dapp"`
+
+	// TonConnect chain id of the network, defaults to the client or service network
+	// example: -239
+	Network string `json:"network,omitempty" validate:"omitempty,oneof=-239 -3" example:"-239"`
+
+	// Space separated scopes requested by the client, available to access policies
+	// example: openid treasury
+	Scope string `json:"scope,omitempty" example:"openid treasury"`
+}
+
+// MultisigSignRequestDTO represents the signature of an owner over a multisig session,
+// either of its message or a TonConnect ton_proof with the challenge as payload.
+// swagger:model
+type MultisigSignRequestDTO struct {
+	// Challenge of the session, set from the path
+	Challenge string `json:"-" swaggerignore:"true" validate:"required"`
+
+	// Public key of the owner in base64 format
+	// required: true
+	// example: dGVzdF9wdWJsaWNfa2V5X2RhdGE=
+	PublicKey []byte `json:"publicKey" validate:"required,len=32" swaggertype:"string" format:"base64" example:"dGVzdF9wdWJsaWNfa2V5X2RhdGE="`
+
+	// Signature of the session message in base64 format, required unless proof is provided
+	// example: c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA==
+	Signature []byte `json:"signature,omitempty" validate:"required_without=Proof,omitempty,len=64" swaggertype:"string" format:"base64" example:"c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA=="`
+
+	// Wallet of the owner the ton_proof was made with, required with proof
+	// example: 0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869
+	Address string `json:"address,omitempty" validate:"required_with=Proof,omitempty,ton_address" example:"0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869"`
+
+	// TonConnect ton_proof of the owner wallet with the challenge as payload
+	Proof *TonProofDTO `json:"proof,omitempty"`
+}
+
+// MultisigSessionDTO represents the signatures collected for a multisig login.
+// swagger:model
+type MultisigSessionDTO struct {
+	// Challenge identifying the session, shared with the owners
+	// required: true
+	// example: JDcyHLLx39O7or8Qo-bV7_wrv95r5IeI50QlZhJq45g
+	Challenge string `json:"challenge" example:"JDcyHLLx39O7or8Qo-bV7_wrv95r5IeI50QlZhJq45g"`
+
+	// Message the owners sign with their keys
+	// required: true
+	// example: TON-OAUTH:multisig:0:3b4c8f7e0a5d2c1b9e8f7a6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29:JDcyHLLx39O7or8Qo-bV7_wrv95r5IeI50QlZhJq45g
+	Message string `json:"message" example:"TON-OAUTH:multisig:0:3b4c8f7e0a5d2c1b9e8f7a6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29:JDcyHLLx39O7or8Qo-bV7_wrv95r5IeI50QlZhJq45g"`
+
+	// Address of the multisig contract
+	// required: true
+	Address string `json:"address" example:"0:3b4c8f7e0a5d2c1b9e8f7a6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c4b3a29"`
+
+	// Network of the multisig contract
+	// example: -239
+	Network string `json:"network" example:"-239"`
+
+	// Multisig contract interface: v1 (owner keys) or v2 (signer wallets)
+	// example: v2
+	Kind string `json:"kind" example:"v2"`
+
+	// Number of owner signatures required
+	// example: 2
+	Threshold int `json:"threshold" example:"2"`
+
+	// Owners of the contract and whether they signed
+	Signers []MultisigSignerDTO `json:"signers"`
+
+	// collecting, complete, expired or used
+	// example: collecting
+	Status string `json:"status" example:"collecting"`
+
+	// Expiration time of the session
+	// example: 2025-09-07T00:15:00Z
+	ExpiresAt time.Time `json:"expiresAt" example:"2025-09-07T00:15:00Z"`
+}
+
+// MultisigSignerDTO represents an owner of a multisig contract.
+// swagger:model
+type MultisigSignerDTO struct {
+	// Index of the owner in the contract
+	// example: 0
+	Index int `json:"index" example:"0"`
+
+	// Hex encoded public key of the owner, empty for v2 signers that are no standard wallets
+	// example: 8a88e3dd7409f195fd52db2d3cba5d72ca6709bf1d94121bf3748801b40f6f5c
+	PublicKey string `json:"publicKey,omitempty" example:"8a88e3dd7409f195fd52db2d3cba5d72ca6709bf1d94121bf3748801b40f6f5c"`
+
+	// Signer wallet of v2 contracts
+	// example: 0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869
+	Address string `json:"address,omitempty" example:"0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869"`
+
+	// Whether the owner signed the session
+	// example: true
+	Signed bool `json:"signed" example:"true"`
+}
+
+// MultisigTokenRequestDTO represents a request to exchange a complete multisig session for a JWT.
+// swagger:model
+type MultisigTokenRequestDTO struct {
+	// Challenge of the session
+	// required: true
+	Challenge string `json:"challenge" validate:"required"`
+
+	// IP address of the caller, set by the handler
+	IP string `json:"-" swaggerignore:"true"`
+}
@@ -0,0 +1,64 @@
+package dto
+
+import "encoding/json"
+
+// PolicyDryRunRequestDTO represents a request to evaluate an access policy for a wallet
+// without logging in.
+// swagger:model
+type PolicyDryRunRequestDTO struct {
+	// ID of the client whose policy is evaluated
+	// required: true
+	// example: my-dapp
+	ClientID string `json:"client_id" validate:"required" example:"my-dapp"`
+
+	// Wallet address to evaluate the policy for
+	// required: true
+	// example: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Address string `json:"address" validate:"required,ton_address" example:"0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// TonConnect chain id of the wallet network, defaults to the client or service network
+	// example: -239
+	Network string `json:"network,omitempty" validate:"omitempty,oneof=-239 -3" example:"-239"`
+
+	// IP address of the simulated login
+	// example: 203.0.113.7
+	IP string `json:"ip,omitempty" validate:"omitempty,ip" example:"203.0.113.7"`
+
+	// Space separated scopes of the simulated login
+	// example: openid profile
+	Scope string `json:"scope,omitempty" example:"openid profile"`
+
+	// Policy to evaluate instead of the loaded policy of the client
+	Policy json.RawMessage `json:"policy,omitempty" swaggertype:"object"`
+}
+
+// PolicyDryRunResponseDTO represents the decision of an access policy.
+// swagger:model
+type PolicyDryRunResponseDTO struct {
+	// Whether the wallet would be allowed to log in
+	// required: true
+	// example: true
+	Allowed bool `json:"allowed" example:"true"`
+
+	// Deny rule that rejected the wallet, "allow" when the allow expression was false
+	// example: frozen-wallets
+	Reason string `json:"reason,omitempty" example:"frozen-wallets"`
+
+	// Names of the rules that applied
+	// example: ["holders"]
+	Matched []string `json:"matched,omitempty" example:"holders"`
+
+	// Roles that would be granted
+	// example: ["holder"]
+	Roles []string `json:"roles,omitempty" example:"holder"`
+
+	// Scopes that would be granted
+	// example: ["premium"]
+	Scopes []string `json:"scopes,omitempty" example:"premium"`
+
+	// Claims that would be added to the token
+	Claims map[string]interface{} `json:"claims,omitempty"`
+
+	// Facts the policy was evaluated on
+	Facts interface{} `json:"facts" swaggertype:"object"`
+}
@@ -0,0 +1,99 @@
+package dto
+
+import "time"
+
+// SignDataRequestDTO represents the result of a TonConnect signData request to verify.
+// swagger:model
+type SignDataRequestDTO struct {
+	// ID of the registered client whose manifest domain the data was signed for
+	// required: true
+	// example: my-dapp
+	ClientID string `json:"client_id" validate:"required" example:"my-dapp"`
+
+	// TonConnect chain id of the wallet network, defaults to the client or service network
+	// example: -239
+	Network string `json:"network,omitempty" validate:"omitempty,oneof=-239 -3" example:"-239"`
+
+	// Address of the wallet that signed the data
+	// required: true
+	// example: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Address string `json:"address" validate:"required,ton_address" example:"0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// Public key of the wallet in base64 format, required with state_init
+	// example: dGVzdF9wdWJsaWNfa2V5X2RhdGE=
+	PublicKey []byte `json:"publicKey,omitempty" validate:"required_with=StateInit,omitempty,len=32" swaggertype:"string" format:"base64" example:"dGVzdF9wdWJsaWNfa2V5X2RhdGE="`
+
+	// StateInit BoC of the wallet in base64 format, as returned by TonConnect
+	// Required for wallets that are not deployed yet
+	// example: te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF...
+	StateInit []byte `json:"state_init,omitempty" swaggertype:"string" format:"base64" example:"te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF..."`
+
+	// Signature returned by the wallet in base64 format
+	// required: true
+	// example: c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA==
+	Signature []byte `json:"signature" validate:"required,len=64" swaggertype:"string" format:"base64" example:"c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA=="`
+
+	// Unix time when the data was signed
+	// required: true
+	// example: 1757203200
+	Timestamp int64 `json:"timestamp" validate:"required" example:"1757203200"`
+
+	// Domain of the dApp the wallet signed the data for
+	// required: true
+	// example: my-dapp.example.com
+	Domain string `json:"domain" validate:"required,max=253" example:"my-dapp.example.com"`
+
+	// Payload the wallet was asked to sign
+	// required: true
+	Payload SignDataPayloadDTO `json:"payload" validate:"required"`
+}
+
+// SignDataPayloadDTO represents the payload of a TonConnect signData request.
+// swagger:model
+type SignDataPayloadDTO struct {
+	// Payload type: text, binary or cell
+	// required: true
+	// example: text
+	Type string `json:"type" validate:"required,oneof=text binary cell" example:"text"`
+
+	// Text shown to the user, for text payloads
+	// example: Confirm the transfer of 10 TON to Alice
+	Text string `json:"text,omitempty" validate:"required_if=Type text" example:"Confirm the transfer of 10 TON to Alice"`
+
+	// Bytes in base64 format, for binary payloads
+	// example: aGVsbG8gd29ybGQ=
+	Bytes []byte `json:"bytes,omitempty" validate:"required_if=Type binary" swaggertype:"string" format:"base64" example:"aGVsbG8gd29ybGQ="`
+
+	// TL-B schema of the cell, for cell payloads
+	// example: message#_ text:^Cell = Message;
+	Schema string `json:"schema,omitempty" validate:"required_if=Type cell" example:"message#_ text:^Cell = Message;"`
+
+	// Cell BoC in base64 format, for cell payloads
+	// example: te6cckEBAQEAEQAAHgAAAABIZWxsbywgVE9OIb7WCx4=
+	Cell []byte `json:"cell,omitempty" validate:"required_if=Type cell" swaggertype:"string" format:"base64" example:"te6cckEBAQEAEQAAHgAAAABIZWxsbywgVE9OIb7WCx4="`
+}
+
+// SignDataResponseDTO represents a verified signData result.
+// swagger:model
+type SignDataResponseDTO struct {
+	// Indicates that the signature verified against the key of the wallet
+	// required: true
+	// example: true
+	Valid bool `json:"valid" example:"true"`
+
+	// Raw address of the wallet that signed the data
+	// example: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Address string `json:"address" example:"0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// Hex encoded hash of the payload: sha256 of text and bytes, the cell hash of cells
+	// example: b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9
+	PayloadHash string `json:"payload_hash" example:"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"`
+
+	// JWT signed by the service attesting that the wallet approved the payload
+	// example: eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9...
+	Attestation string `json:"attestation" example:"eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9..."`
+
+	// Expiration time of the attestation
+	// example: 2025-09-08T00:00:00Z
+	ExpiresAt time.Time `json:"expiresAt" example:"2025-09-08T00:00:00Z"`
+}
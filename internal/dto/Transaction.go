@@ -0,0 +1,75 @@
+package dto
+
+import "time"
+
+// TransactionChallengeDTO represents the transfer that approves a login in transaction mode.
+// swagger:model
+type TransactionChallengeDTO struct {
+	// Address the transfer is sent to
+	// required: true
+	// example: EQBvW8Z5huBkMJYdnfAEM5JqTNkuWX3diqYENkWsIL0XggGG
+	Address string `json:"address" example:"EQBvW8Z5huBkMJYdnfAEM5JqTNkuWX3diqYENkWsIL0XggGG"`
+
+	// Text comment the transfer must carry
+	// required: true
+	// example: ton-oauth-mfrggzdfmztwq2lk
+	Comment string `json:"comment" example:"ton-oauth-mfrggzdfmztwq2lk"`
+
+	// Least amount of the transfer in nanotons
+	// required: true
+	// example: 10000000
+	Amount string `json:"amount" example:"10000000"`
+
+	// ton:// transfer link with address, amount and comment filled in
+	// example: ton://transfer/EQBvW8Z5huBkMJYdnfAEM5JqTNkuWX3diqYENkWsIL0XggGG?amount=10000000&text=ton-oauth-mfrggzdfmztwq2lk
+	Link string `json:"link" example:"ton://transfer/EQBvW8Z5huBkMJYdnfAEM5JqTNkuWX3diqYENkWsIL0XggGG?amount=10000000&text=ton-oauth-mfrggzdfmztwq2lk"`
+
+	// Network the transfer is expected on
+	// example: -239
+	Network string `json:"network" example:"-239"`
+}
+
+// TransactionStatusRequestDTO represents a request for the state of a transaction challenge.
+// swagger:model
+type TransactionStatusRequestDTO struct {
+	// Challenge returned by /oauth/authorize in transaction mode
+	// required: true
+	Challenge string `json:"challenge" validate:"required"`
+
+	// How long to wait for the challenge to leave the pending state, e.g. 30s
+	// example: 30s
+	Wait string `json:"wait,omitempty"`
+}
+
+// TransactionStatusResponseDTO represents the state of a transaction challenge.
+// swagger:model
+type TransactionStatusResponseDTO struct {
+	// pending, approved, expired or used
+	// required: true
+	// example: approved
+	Status string `json:"status" example:"approved"`
+
+	// Wallet that sent the transfer, present once approved
+	// example: 0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869
+	Wallet string `json:"wallet,omitempty" example:"0:6463684ae800d1901ed6e511475ff8ad7832498dce950b165cf73461a6c43869"`
+
+	// Hash of the transfer transaction, present once approved
+	// example: 3f1b0c3c1f7f6c6a5a2f0f0b6f7b5f0e2d4b6a8c0e2f4a6b8c0d2e4f6a8b0c2d
+	TxHash string `json:"txHash,omitempty" example:"3f1b0c3c1f7f6c6a5a2f0f0b6f7b5f0e2d4b6a8c0e2f4a6b8c0d2e4f6a8b0c2d"`
+
+	// Until when the transfer is awaited, or the approved challenge can be exchanged for a token
+	// example: 2025-09-07T00:10:00Z
+	ExpiresAt time.Time `json:"expiresAt" example:"2025-09-07T00:10:00Z"`
+}
+
+// TransactionTokenRequestDTO represents a request to exchange an approved transaction
+// challenge for a JWT.
+// swagger:model
+type TransactionTokenRequestDTO struct {
+	// Challenge returned by /oauth/authorize in transaction mode
+	// required: true
+	Challenge string `json:"challenge" validate:"required"`
+
+	// IP address of the caller, set by the handler
+	IP string `json:"-" swaggerignore:"true"`
+}
@@ -7,6 +7,10 @@ type VerifyTokenRequestDTO struct {
 	// required: true
 	// example: eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9...
 	JWT string `json:"jwt" validate:"required" example:"eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9..."`
+
+	// Client the token must be issued to, checked against its aud claim when set
+	// example: my-dapp
+	ClientID string `json:"client_id,omitempty" example:"my-dapp"`
 }
 
 // VerifyTokenResponseDTO represents the response after verifying a JWT token.
@@ -22,6 +26,22 @@ type VerifyTokenResponseDTO struct {
 	// example: TON OAuth Service
 	Issuer string `json:"issuer" example:"TON OAuth Service"`
 
+	// Raw address of the wallet the token was issued to
+	// example: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Subject string `json:"sub,omitempty" example:"0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// TonConnect chain id of the wallet network
+	// example: -239
+	Network string `json:"network,omitempty" example:"-239"`
+
+	// Activation state of the wallet at login: active, uninit or nonexist
+	// example: active
+	WalletState string `json:"wallet_state,omitempty" example:"active"`
+
+	// TON DNS name of the wallet
+	// example: alice.ton
+	PreferredUsername string `json:"preferred_username,omitempty" example:"alice.ton"`
+
 	// Expiration timestamp of the token (Unix time)
 	// required: true
 	// example: 1751913600
@@ -5,20 +5,100 @@ import "time"
 // VerifyRequestDTO represents a request to verify a signed message from a TON wallet.
 // swagger:model
 type VerifyRequestDTO struct {
-	// Original message that was signed
-	// required: true
+	// Original message that was signed, required unless proof is provided
 	// example: TON OAuth challenge message
-	Message string `json:"message" validate:"required" example:"TON OAuth challenge message"`
+	Message string `json:"message" validate:"required_without=Proof" example:"TON OAuth challenge message"`
 
-	// Signature of the message in base64 format
-	// required: true
+	// Signature of the message in base64 format, required unless proof is provided
 	// example: c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA==
-	Signature []byte `json:"signature" validate:"required,len=64" example:"c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA=="`
+	Signature []byte `json:"signature" validate:"required_without=Proof,omitempty,len=64" swaggertype:"string" format:"base64" example:"c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA=="`
 
 	// Public key of the TON wallet in base64 format
 	// required: true
 	// example: dGVzdF9wdWJsaWNfa2V5X2RhdGE=
-	PublicKey []byte `json:"publicKey" validate:"required,len=32" example:"dGVzdF9wdWJsaWNfa2V5X2RhdGE="`
+	PublicKey []byte `json:"publicKey" validate:"required,len=32" swaggertype:"string" format:"base64" example:"dGVzdF9wdWJsaWNfa2V5X2RhdGE="`
+
+	// ID of the registered client the wallet connected to, required with proof
+	// example: my-dapp
+	ClientID string `json:"client_id,omitempty" validate:"required_with=Proof" example:"my-dapp"`
+
+	// Wallet address claimed by the user, required with proof
+	// It is checked against the standard wallet contracts of the public key
+	// example: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Address string `json:"address,omitempty" validate:"required_with=Proof,omitempty,ton_address" example:"0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// StateInit BoC of the wallet in base64 format, as returned by TonConnect
+	// Required to log in with a wallet that is not deployed yet
+	// example: te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF...
+	StateInit []byte `json:"state_init,omitempty" swaggertype:"string" format:"base64" example:"te6cckECFgEAAwQAAgE0AQIBFP8A9KQT9LzyyAsDAFEAAAAAKamjF..."`
+
+	// TonConnect chain id of the wallet network, defaults to the client or service network
+	// example: -239
+	Network string `json:"network,omitempty" validate:"omitempty,oneof=-239 -3" example:"-239"`
+
+	// Space separated scopes requested by the client, available to access policies
+	// example: openid profile
+	Scope string `json:"scope,omitempty" example:"openid profile"`
+
+	// TonConnect ton_proof returned by the wallet
+	Proof *TonProofDTO `json:"proof,omitempty"`
+
+	// Solution of the proof-of-work puzzle of /oauth/authorize, required when proof of work is enabled
+	PoW *PoWSolutionDTO `json:"pow,omitempty"`
+
+	// IP address of the caller, set by the handler
+	IP string `json:"-" swaggerignore:"true"`
+}
+
+// TonProofDTO represents the ton_proof item returned by a TonConnect wallet.
+// swagger:model
+type TonProofDTO struct {
+	// Unix time when the proof was signed
+	// required: true
+	// example: 1757203200
+	Timestamp int64 `json:"timestamp" validate:"required" example:"1757203200"`
+
+	// Domain of the dApp the wallet connected to
+	// required: true
+	Domain TonProofDomainDTO `json:"domain" validate:"required"`
+
+	// Payload (challenge) that was signed
+	// required: true
+	// example: nonce1234567890
+	Payload string `json:"payload" validate:"required" example:"nonce1234567890"`
+
+	// Signature of the proof in base64 format
+	// required: true
+	// example: c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA==
+	Signature []byte `json:"signature" validate:"required,len=64" swaggertype:"string" format:"base64" example:"c2lnbmF0dXJlX2RhdGFfYmFzZTY0X2Zvcm1hdA=="`
+}
+
+// PoWSolutionDTO represents the solution of a proof-of-work puzzle.
+// swagger:model
+type PoWSolutionDTO struct {
+	// Challenge returned by /oauth/authorize
+	// required: true
+	// example: q8Yk3n0cR1m2Z6bq0h6V7wAAAABo7c2AEHk0m1d3xq3uJb5kS9QqP2g
+	Challenge string `json:"challenge" validate:"required" example:"q8Yk3n0cR1m2Z6bq0h6V7wAAAABo7c2AEHk0m1d3xq3uJb5kS9QqP2g"`
+
+	// Nonce whose hash with the challenge has the required zero bits
+	// required: true
+	// example: 48213
+	Nonce string `json:"nonce" validate:"required,max=64" example:"48213"`
+}
+
+// TonProofDomainDTO represents the dApp domain inside ton_proof.
+// swagger:model
+type TonProofDomainDTO struct {
+	// Length of the domain in bytes
+	// required: true
+	// example: 11
+	LengthBytes uint32 `json:"lengthBytes" validate:"required" example:"11"`
+
+	// Domain value
+	// required: true
+	// example: example.com
+	Value string `json:"value" validate:"required" example:"example.com"`
 }
 
 // VerifyResponseDTO represents the response after verifying a TON wallet signature.
@@ -29,11 +109,29 @@ type VerifyResponseDTO struct {
 	// example: true
 	Valid bool `json:"valid" example:"true"`
 
-	// Wallet address of the signer
+	// Wallet address of the signer, rendered in the address format of the client
 	// required: true
 	// example: EQC1234567890abcdef...
 	Wallet string `json:"wallet" example:"EQC1234567890abcdef..."`
 
+	// Version of the wallet contract
+	// example: v4r2
+	Version string `json:"version,omitempty" example:"v4r2"`
+
+	// TonConnect chain id of the wallet network
+	// required: true
+	// example: -239
+	Network string `json:"network" example:"-239"`
+
+	// Activation state of the wallet: active, uninit or nonexist
+	// required: true
+	// example: active
+	WalletState string `json:"walletState" example:"active"`
+
+	// TON DNS name that resolves back to the wallet
+	// example: alice.ton
+	Name string `json:"name,omitempty" example:"alice.ton"`
+
 	// Issuer of the verification
 	// required: true
 	// example: TON OAuth Service
@@ -49,3 +147,61 @@ type VerifyResponseDTO struct {
 	// example: 2025-09-07T00:00:00Z
 	ExpiresAt time.Time `json:"expiresAt" example:"2025-09-07T00:00:00Z"`
 }
+
+// WalletsRequestDTO represents a request to list the wallets of a public key.
+// swagger:model
+type WalletsRequestDTO struct {
+	// Hex encoded public key of the TON wallet
+	// required: true
+	// example: 83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	PublicKey string `json:"public_key" validate:"required,hexadecimal,len=64" example:"83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// Optional TonConnect chain id, defaults to the client or service network
+	// example: -239
+	Network string `json:"network,omitempty" validate:"omitempty,oneof=-239 -3" example:"-239"`
+
+	// Optional ID of a registered client whose wallet version preference applies
+	// example: my-dapp
+	ClientID string `json:"client_id,omitempty" example:"my-dapp"`
+}
+
+// WalletsResponseDTO represents the candidate wallets of a public key.
+// swagger:model
+type WalletsResponseDTO struct {
+	// TonConnect chain id of the network the wallets were looked up on
+	// required: true
+	// example: -239
+	Network string `json:"network" example:"-239"`
+
+	// Candidate wallets, the one used by default first
+	// required: true
+	Wallets []WalletDTO `json:"wallets"`
+}
+
+// WalletDTO represents a wallet contract controlled by a public key.
+// swagger:model
+type WalletDTO struct {
+	// Address of the wallet in the address format of the client
+	// required: true
+	// example: 0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8
+	Address string `json:"address" example:"0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"`
+
+	// Version of the wallet contract, empty when unknown
+	// example: v4r2
+	Version string `json:"version,omitempty" example:"v4r2"`
+
+	// Balance in nanotons
+	// required: true
+	// example: 1500000000
+	Balance int64 `json:"balance" example:"1500000000"`
+
+	// Account status: active, uninit, frozen or nonexist
+	// required: true
+	// example: active
+	Status string `json:"status" example:"active"`
+
+	// Indicates the wallet used when no address is passed to verify or token
+	// required: true
+	// example: true
+	Preferred bool `json:"preferred" example:"true"`
+}
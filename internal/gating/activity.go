@@ -0,0 +1,122 @@
+package gating
+
+import (
+	"TON/internal/chain"
+	"context"
+	"fmt"
+	"time"
+)
+
+// activityPage is the number of transactions read per provider call.
+const activityPage = 100
+
+// Activity describes the transaction history of a wallet.
+type Activity struct {
+	// FirstTx is the time of the oldest transaction read, zero without transactions. It
+	// is a lower bound of the wallet age when Truncated is set.
+	FirstTx time.Time
+	// LastTx is the time of the latest transaction, zero without transactions.
+	LastTx  time.Time
+	TxCount int
+	// Truncated is set when the history is longer than the scan limit: the wallet is
+	// older than FirstTx and has more than TxCount transactions.
+	Truncated bool
+}
+
+// AgeDays returns the whole days since the first transaction.
+func (a *Activity) AgeDays(now time.Time) int {
+	if a.FirstTx.IsZero() {
+		return 0
+	}
+	return int(now.Sub(a.FirstTx) / (24 * time.Hour))
+}
+
+// InactiveDays returns the whole days since the last transaction, -1 without transactions.
+func (a *Activity) InactiveDays(now time.Time) int {
+	if a.LastTx.IsZero() {
+		return -1
+	}
+	return int(now.Sub(a.LastTx) / (24 * time.Hour))
+}
+
+func (e *Evaluator) checkActivity(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr string, r *Rules, res *Result) error {
+	if r.MinAgeDays == 0 && r.MinTxCount == 0 && r.MaxInactiveDays == 0 && !r.ActivityClaims {
+		return nil
+	}
+
+	a, err := e.Activity(ctx, provider, network, addr)
+	if err != nil {
+		return err
+	}
+
+	// a truncated history only tells that the wallet is at least that old, so it is
+	// admitted when that lower bound already meets the age. It holds as many
+	// transactions as the scan limit, which minTxCount never exceeds.
+	now := time.Now()
+	if age := a.AgeDays(now); age < r.MinAgeDays {
+		if a.Truncated {
+			return fmt.Errorf("%w: the latest %d transactions of the wallet span %d days, %d required", ErrAccessDenied, a.TxCount, age, r.MinAgeDays)
+		}
+		return fmt.Errorf("%w: wallet is %d days old, %d required", ErrAccessDenied, age, r.MinAgeDays)
+	}
+	if a.TxCount < r.MinTxCount {
+		return fmt.Errorf("%w: wallet has %d transactions, %d required", ErrAccessDenied, a.TxCount, r.MinTxCount)
+	}
+	if r.MaxInactiveDays > 0 {
+		if inactive := a.InactiveDays(now); inactive < 0 || inactive > r.MaxInactiveDays {
+			return fmt.Errorf("%w: wallet has no transaction in the last %d days", ErrAccessDenied, r.MaxInactiveDays)
+		}
+	}
+
+	if r.ActivityClaims {
+		res.Claims["wallet_tx_count"] = a.TxCount
+		if !a.FirstTx.IsZero() {
+			res.Claims["wallet_first_tx_at"] = a.FirstTx.Unix()
+			res.Claims["wallet_last_tx_at"] = a.LastTx.Unix()
+		}
+		if a.Truncated {
+			res.Claims["wallet_history_truncated"] = true
+		}
+	}
+	return nil
+}
+
+// Activity returns the transaction history facts of the wallet, reading at most the
+// scan limit of transactions and reusing cached facts up to the activity max age.
+func (e *Evaluator) Activity(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr string) (*Activity, error) {
+	a, _, err := e.activity.Fetch(string(network)+"/"+addr, e.activityMaxAge, func() (*Activity, error) {
+		return scanActivity(ctx, provider, addr, e.activityScanLimit)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet activity: %w", err)
+	}
+	return a, nil
+}
+
+func scanActivity(ctx context.Context, provider chain.ChainProvider, addr string, limit int) (*Activity, error) {
+	a := &Activity{}
+	var before *chain.Transaction
+	for a.TxCount < limit {
+		page, err := provider.GetTransactions(ctx, addr, before, min(activityPage, limit-a.TxCount))
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			return a, nil
+		}
+		if a.TxCount == 0 {
+			a.LastTx = page[0].Time
+		}
+		a.TxCount += len(page)
+		before = &page[len(page)-1]
+		a.FirstTx = before.Time
+	}
+
+	// the history may end exactly at the limit
+	next, err := provider.GetTransactions(ctx, addr, before, 1)
+	if err != nil {
+		return nil, err
+	}
+	a.Truncated = len(next) > 0
+	return a, nil
+}
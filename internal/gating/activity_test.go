@@ -0,0 +1,97 @@
+package gating
+
+import (
+	"TON/internal/chain"
+	"TON/pkg/tonwallet"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const wallet = "0:960ab627408d5472d9d125b667cbe00ce17eeaa44e9dc6a86e93cdfef2c480d5"
+
+// history records count transactions of the wallet, one a day, the newest today.
+func history(count int) *chain.FixtureProvider {
+	p := chain.NewFixtureProvider(tonwallet.Options{})
+	now := time.Now()
+	for i := 0; i < count; i++ {
+		p.AddTransaction(wallet, chain.Transaction{
+			LT:   uint64(1000 + count - i),
+			Time: now.Add(-time.Duration(i) * 24 * time.Hour),
+		})
+	}
+	return p
+}
+
+func TestScanActivity(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     int
+		limit     int
+		txCount   int
+		ageDays   int
+		truncated bool
+	}{
+		{"no transactions", 0, 10, 0, 0, false},
+		{"several pages", 250, 1000, 250, 249, false},
+		{"ends at the limit", 200, 200, 200, 199, false},
+		{"longer than the limit", 250, 120, 120, 119, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := scanActivity(context.Background(), history(tt.count), wallet, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.TxCount != tt.txCount || a.AgeDays(time.Now()) != tt.ageDays || a.Truncated != tt.truncated {
+				t.Fatalf("scanActivity() = %d transactions, %d days, truncated=%v, want %d, %d, %v",
+					a.TxCount, a.AgeDays(time.Now()), a.Truncated, tt.txCount, tt.ageDays, tt.truncated)
+			}
+		})
+	}
+}
+
+func TestCheckActivity(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		limit   int
+		rules   Rules
+		allowed bool
+	}{
+		{"old enough", 40, 100, Rules{MinAgeDays: 30}, true},
+		{"too young", 20, 100, Rules{MinAgeDays: 30}, false},
+		{"too few transactions", 20, 100, Rules{MinTxCount: 30}, false},
+		{"truncated history old enough", 40, 35, Rules{MinAgeDays: 30, MinTxCount: 35}, true},
+		{"truncated history too young", 40, 10, Rules{MinAgeDays: 30}, false},
+		{"inactive", 0, 100, Rules{MaxInactiveDays: 7}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEvaluator(time.Minute, time.Minute, tt.limit, time.Minute, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := &Result{Claims: make(map[string]interface{})}
+			err = e.checkActivity(context.Background(), history(tt.count), chain.Mainnet, wallet, &tt.rules, res)
+			if tt.allowed && err != nil {
+				t.Fatalf("checkActivity() error = %v", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrAccessDenied) {
+				t.Fatalf("checkActivity() error = %v, want %v", err, ErrAccessDenied)
+			}
+		})
+	}
+}
+
+func TestPrepareMinTxCount(t *testing.T) {
+	r := Rules{MinTxCount: 100}
+	if err := r.Prepare(100); err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	r = Rules{MinTxCount: 101}
+	if err := r.Prepare(100); err == nil {
+		t.Fatal("Prepare() accepted a minTxCount above the scan limit")
+	}
+}
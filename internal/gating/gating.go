@@ -0,0 +1,245 @@
+package gating
+
+import (
+	"TON/internal/chain"
+	"TON/pkg/cache"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xssnick/tonutils-go/tlb"
+)
+
+// ErrAccessDenied is returned when a wallet does not satisfy the rules of a client.
+var ErrAccessDenied = errors.New("access denied")
+
+// Rules are the login requirements of a client and the claims derived from the wallet.
+type Rules struct {
+	// MinBalance in TON a wallet must hold to log in, e.g. "10" or "0.5".
+	MinBalance string `json:"minBalance,omitempty"`
+	// BalanceClaim adds the ton_balance and ton_balance_at claims.
+	BalanceClaim bool `json:"balanceClaim,omitempty"`
+	// BalanceMaxAge is how stale a cached TON or jetton balance may be, e.g. "30s".
+	BalanceMaxAge string `json:"balanceMaxAge,omitempty"`
+	// Jettons are checked in order; each one held grants its roles and scopes.
+	Jettons []JettonRule `json:"jettons,omitempty"`
+	// NFTs are checked in order; each collection held grants its roles and scopes.
+	NFTs []NFTRule `json:"nfts,omitempty"`
+	// MinAgeDays is how many days ago the first transaction of a wallet must be.
+	MinAgeDays int `json:"minAgeDays,omitempty"`
+	// MinTxCount is the number of transactions a wallet must have.
+	MinTxCount int `json:"minTxCount,omitempty"`
+	// MaxInactiveDays rejects wallets without a transaction in as many days.
+	MaxInactiveDays int `json:"maxInactiveDays,omitempty"`
+	// ActivityClaims adds the wallet_tx_count, wallet_first_tx_at and wallet_last_tx_at claims.
+	ActivityClaims bool `json:"activityClaims,omitempty"`
+	// Subscriptions are paid plans; each active one grants its roles and scopes.
+	Subscriptions []SubscriptionRule `json:"subscriptions,omitempty"`
+
+	minBalance    *big.Int
+	balanceMaxAge time.Duration
+}
+
+// Prepare parses and validates the rules. A wallet history is read up to
+// activityScanLimit transactions, so more cannot be required.
+func (r *Rules) Prepare(activityScanLimit int) error {
+	if r.MinBalance != "" {
+		coins, err := tlb.FromTON(r.MinBalance)
+		if err != nil {
+			return fmt.Errorf("minBalance: %w", err)
+		}
+		r.minBalance = coins.Nano()
+	}
+	if r.BalanceMaxAge != "" {
+		d, err := time.ParseDuration(r.BalanceMaxAge)
+		if err != nil {
+			return fmt.Errorf("balanceMaxAge: %w", err)
+		}
+		r.balanceMaxAge = d
+	}
+	if r.MinAgeDays < 0 || r.MinTxCount < 0 || r.MaxInactiveDays < 0 {
+		return errors.New("minAgeDays, minTxCount and maxInactiveDays must not be negative")
+	}
+	if r.MinTxCount > activityScanLimit {
+		return fmt.Errorf("minTxCount %d is above the activity scan limit %d", r.MinTxCount, activityScanLimit)
+	}
+	for i := range r.Jettons {
+		if err := r.Jettons[i].prepare(); err != nil {
+			return fmt.Errorf("jettons[%d]: %w", i, err)
+		}
+	}
+	for i := range r.NFTs {
+		if err := r.NFTs[i].prepare(); err != nil {
+			return fmt.Errorf("nfts[%d]: %w", i, err)
+		}
+	}
+	for i := range r.Subscriptions {
+		if err := r.Subscriptions[i].prepare(); err != nil {
+			return fmt.Errorf("subscriptions[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Result holds what the rules grant to a wallet.
+type Result struct {
+	Claims map[string]interface{}
+	Roles  []string
+	Scopes []string
+}
+
+// Evaluator checks wallets against client rules, caching the chain data it reads.
+type Evaluator struct {
+	balances      *cache.Cache[int64]
+	jettons       *cache.Cache[*chain.JettonBalance]
+	nfts          *cache.Cache[[]chain.NFTItem]
+	balanceMaxAge time.Duration
+
+	activity          *cache.Cache[*Activity]
+	activityMaxAge    time.Duration
+	activityScanLimit int
+
+	paymentsMu        sync.Mutex
+	payments          map[string]*incoming
+	paymentsMaxAge    time.Duration
+	paymentsScanLimit int
+}
+
+// NewEvaluator creates an evaluator that reuses balances up to balanceMaxAge old
+// unless the rules of a client allow another age, and wallet activity up to
+// activityMaxAge old. At most activityScanLimit transactions of a wallet are read.
+// Payments to subscription addresses are topped up when older than paymentsMaxAge,
+// reading at most paymentsScanLimit transactions at a time.
+func NewEvaluator(balanceMaxAge, activityMaxAge time.Duration, activityScanLimit int, paymentsMaxAge time.Duration, paymentsScanLimit int) (*Evaluator, error) {
+	if activityScanLimit <= 0 {
+		return nil, errors.New("activity scan limit must be positive")
+	}
+	if paymentsScanLimit <= 0 {
+		return nil, errors.New("payments scan limit must be positive")
+	}
+	return &Evaluator{
+		balances:          cache.New[int64](balanceMaxAge),
+		jettons:           cache.New[*chain.JettonBalance](balanceMaxAge),
+		nfts:              cache.New[[]chain.NFTItem](balanceMaxAge),
+		balanceMaxAge:     balanceMaxAge,
+		activity:          cache.New[*Activity](activityMaxAge),
+		activityMaxAge:    activityMaxAge,
+		activityScanLimit: activityScanLimit,
+		payments:          make(map[string]*incoming),
+		paymentsMaxAge:    paymentsMaxAge,
+		paymentsScanLimit: paymentsScanLimit,
+	}, nil
+}
+
+// Run periodically drops old cache entries until stop is closed.
+func (e *Evaluator) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.balances.Cleanup()
+			e.jettons.Cleanup()
+			e.nfts.Cleanup()
+			e.activity.Cleanup()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Evaluate checks the wallet against the rules. A wallet that does not satisfy them
+// yields an error wrapping ErrAccessDenied.
+func (e *Evaluator) Evaluate(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr string, r *Rules) (*Result, error) {
+	res := &Result{Claims: make(map[string]interface{})}
+	if r == nil {
+		return res, nil
+	}
+
+	if err := e.checkBalance(ctx, provider, network, addr, r, res); err != nil {
+		return nil, err
+	}
+	if err := e.checkJettons(ctx, provider, network, addr, r, res); err != nil {
+		return nil, err
+	}
+	if err := e.checkNFTs(ctx, provider, network, addr, r, res); err != nil {
+		return nil, err
+	}
+	if err := e.checkActivity(ctx, provider, network, addr, r, res); err != nil {
+		return nil, err
+	}
+	if err := e.checkSubscriptions(ctx, provider, network, addr, r, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// maxAge returns how stale cached balances may be for the rules.
+func (e *Evaluator) maxAge(r *Rules) time.Duration {
+	if r.balanceMaxAge > 0 {
+		return r.balanceMaxAge
+	}
+	return e.balanceMaxAge
+}
+
+// grant adds roles and scopes to the result, skipping ones already granted.
+func (res *Result) grant(roles, scopes []string) {
+	res.Roles = appendUnique(res.Roles, roles...)
+	res.Scopes = appendUnique(res.Scopes, scopes...)
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		if !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func (e *Evaluator) checkBalance(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr string, r *Rules, res *Result) error {
+	if r.minBalance == nil && !r.BalanceClaim {
+		return nil
+	}
+
+	balance, fetchedAt, err := e.Balance(ctx, provider, network, addr, e.maxAge(r))
+	if err != nil {
+		return err
+	}
+
+	if r.minBalance != nil && big.NewInt(balance).Cmp(r.minBalance) < 0 {
+		return fmt.Errorf("%w: wallet balance %s TON is below the required %s TON",
+			ErrAccessDenied, tlb.FromNanoTON(big.NewInt(balance)).String(), tlb.FromNanoTON(r.minBalance).String())
+	}
+
+	if r.BalanceClaim {
+		res.Claims["ton_balance"] = strconv.FormatInt(balance, 10)
+		res.Claims["ton_balance_at"] = fetchedAt.Unix()
+	}
+	return nil
+}
+
+// Balance returns the TON balance of the wallet in nanotons and when it was read,
+// reusing a cached balance up to maxAge old, or the default age when maxAge is zero.
+func (e *Evaluator) Balance(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr string, maxAge time.Duration) (int64, time.Time, error) {
+	if maxAge == 0 {
+		maxAge = e.balanceMaxAge
+	}
+	balance, fetchedAt, err := e.balances.Fetch(string(network)+"/"+addr, maxAge, func() (int64, error) {
+		balance, err := provider.GetBalance(ctx, addr)
+		if errors.Is(err, chain.ErrNotFound) {
+			return 0, nil
+		}
+		return balance, err
+	})
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to get balance: %w", err)
+	}
+	return balance, fetchedAt, nil
+}
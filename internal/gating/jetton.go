@@ -0,0 +1,103 @@
+package gating
+
+import (
+	"TON/internal/chain"
+	"TON/pkg/address"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// JettonRule grants roles and scopes to wallets holding a jetton.
+type JettonRule struct {
+	// Master is the address of the jetton master contract.
+	Master string `json:"master"`
+	// MinAmount in base units of the jetton, any positive balance when empty.
+	MinAmount string `json:"minAmount,omitempty"`
+	// Required rejects wallets that do not hold the jetton instead of only
+	// withholding the roles and scopes of the rule.
+	Required bool     `json:"required,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+	// Claim adds the holding to the jettons claim.
+	Claim bool `json:"claim,omitempty"`
+
+	minAmount *big.Int
+}
+
+func (j *JettonRule) prepare() error {
+	master, err := address.Normalize(j.Master)
+	if err != nil {
+		return fmt.Errorf("master: %w", err)
+	}
+	j.Master = master
+
+	j.minAmount = big.NewInt(1)
+	if j.MinAmount != "" {
+		amount, ok := new(big.Int).SetString(j.MinAmount, 10)
+		if !ok || amount.Sign() < 0 {
+			return fmt.Errorf("minAmount must be a non-negative integer, got %q", j.MinAmount)
+		}
+		j.minAmount = amount
+	}
+	return nil
+}
+
+func (e *Evaluator) checkJettons(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr string, r *Rules, res *Result) error {
+	var holdings []map[string]interface{}
+	for _, rule := range r.Jettons {
+		balance, err := e.JettonBalance(ctx, provider, network, addr, rule.Master, e.maxAge(r))
+		if err != nil {
+			return err
+		}
+
+		amount := balance.Amount
+		if amount.Cmp(rule.minAmount) >= 0 {
+			res.grant(rule.Roles, rule.Scopes)
+		} else if rule.Required {
+			return fmt.Errorf("%w: wallet holds %s of jetton %s, %s required", ErrAccessDenied, amount, rule.Master, rule.minAmount)
+		}
+
+		if rule.Claim {
+			holding := map[string]interface{}{
+				"master": rule.Master,
+				"amount": amount.String(),
+			}
+			if balance.Symbol != "" {
+				holding["symbol"] = balance.Symbol
+			}
+			if balance.Decimals != 0 {
+				holding["decimals"] = balance.Decimals
+			}
+			holdings = append(holdings, holding)
+		}
+	}
+
+	if len(holdings) > 0 {
+		res.Claims["jettons"] = holdings
+	}
+	return nil
+}
+
+// JettonBalance returns the balance of the wallet in the jetton master, reusing a cached
+// balance up to maxAge old, or the default age when maxAge is zero. Amount is never nil.
+func (e *Evaluator) JettonBalance(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr, master string, maxAge time.Duration) (*chain.JettonBalance, error) {
+	if maxAge == 0 {
+		maxAge = e.balanceMaxAge
+	}
+	balance, _, err := e.jettons.Fetch(string(network)+"/"+addr+"/"+master, maxAge, func() (*chain.JettonBalance, error) {
+		balance, err := provider.GetJettonBalance(ctx, addr, master)
+		if err != nil {
+			return nil, err
+		}
+		if balance.Amount == nil {
+			balance.Amount = new(big.Int)
+		}
+		return balance, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jetton balance of %s: %w", master, err)
+	}
+	return balance, nil
+}
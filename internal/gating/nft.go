@@ -0,0 +1,88 @@
+package gating
+
+import (
+	"TON/internal/chain"
+	"TON/pkg/address"
+	"context"
+	"fmt"
+	"time"
+)
+
+// NFTRule grants roles and scopes to wallets owning an item of a collection,
+// including soulbound tokens.
+type NFTRule struct {
+	// Collection is the address of the NFT or SBT collection.
+	Collection string `json:"collection"`
+	// Attributes an item must have, all of them with the given values.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Required rejects wallets without a matching item instead of only
+	// withholding the roles and scopes of the rule.
+	Required bool     `json:"required,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+func (n *NFTRule) prepare() error {
+	collection, err := address.Normalize(n.Collection)
+	if err != nil {
+		return fmt.Errorf("collection: %w", err)
+	}
+	n.Collection = collection
+	return nil
+}
+
+// matches reports whether the item carries all attributes of the rule.
+func (n *NFTRule) matches(item chain.NFTItem) bool {
+	for k, v := range n.Attributes {
+		if item.Attributes[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Evaluator) checkNFTs(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr string, r *Rules, res *Result) error {
+	var collections []string
+	for _, rule := range r.NFTs {
+		items, err := e.NFTs(ctx, provider, network, addr, rule.Collection, e.maxAge(r))
+		if err != nil {
+			return err
+		}
+
+		held := false
+		for _, item := range items {
+			if rule.matches(item) {
+				held = true
+				break
+			}
+		}
+		if !held {
+			if rule.Required {
+				return fmt.Errorf("%w: wallet holds no matching item of collection %s", ErrAccessDenied, rule.Collection)
+			}
+			continue
+		}
+		res.grant(rule.Roles, rule.Scopes)
+		collections = appendUnique(collections, rule.Collection)
+	}
+
+	if len(collections) > 0 {
+		res.Claims["nft_collections"] = collections
+	}
+	return nil
+}
+
+// NFTs returns the items of the collection owned by the wallet, reusing cached items
+// up to maxAge old, or the default age when maxAge is zero.
+func (e *Evaluator) NFTs(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr, collection string, maxAge time.Duration) ([]chain.NFTItem, error) {
+	if maxAge == 0 {
+		maxAge = e.balanceMaxAge
+	}
+	items, _, err := e.nfts.Fetch(string(network)+"/"+addr+"/"+collection, maxAge, func() ([]chain.NFTItem, error) {
+		return provider.GetNFTs(ctx, addr, collection)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items of collection %s: %w", collection, err)
+	}
+	return items, nil
+}
@@ -0,0 +1,252 @@
+package gating
+
+import (
+	"TON/internal/chain"
+	"TON/pkg/address"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xssnick/tonutils-go/tlb"
+)
+
+// SubscriptionRule grants roles and scopes to wallets that paid for a plan: a transfer of
+// at least MinAmount with the Comment to Address within the last Days.
+type SubscriptionRule struct {
+	Plan string `json:"plan"`
+	// Address the payments are sent to.
+	Address string `json:"address"`
+	// Comment a payment must carry, compared case-insensitively; any comment when empty.
+	Comment string `json:"comment,omitempty"`
+	// Jetton is the master of the jetton paid in, TON when empty.
+	Jetton string `json:"jetton,omitempty"`
+	// MinAmount of a payment in TON, e.g. "5", or in base units of the jetton.
+	MinAmount string `json:"minAmount"`
+	// Days a payment keeps the plan active.
+	Days int `json:"days"`
+	// Required rejects wallets without an active plan instead of only withholding
+	// the roles and scopes of the rule.
+	Required bool     `json:"required,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+
+	minAmount *big.Int
+}
+
+func (s *SubscriptionRule) prepare() error {
+	if s.Plan == "" {
+		return errors.New("plan is required")
+	}
+	addr, err := address.Normalize(s.Address)
+	if err != nil {
+		return fmt.Errorf("address: %w", err)
+	}
+	s.Address = addr
+	if s.Days <= 0 {
+		return errors.New("days must be positive")
+	}
+
+	if s.Jetton == "" {
+		coins, err := tlb.FromTON(s.MinAmount)
+		if err != nil {
+			return fmt.Errorf("minAmount: %w", err)
+		}
+		s.minAmount = coins.Nano()
+		return nil
+	}
+
+	if s.Jetton, err = address.Normalize(s.Jetton); err != nil {
+		return fmt.Errorf("jetton: %w", err)
+	}
+	amount, ok := new(big.Int).SetString(s.MinAmount, 10)
+	if !ok || amount.Sign() < 0 {
+		return fmt.Errorf("minAmount must be a non-negative integer, got %q", s.MinAmount)
+	}
+	s.minAmount = amount
+	return nil
+}
+
+func (e *Evaluator) checkSubscriptions(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr string, r *Rules, res *Result) error {
+	var plans []string
+	var expiresAt time.Time
+	for _, rule := range r.Subscriptions {
+		paidUntil, complete, err := e.paidUntil(ctx, provider, network, addr, &rule)
+		if err != nil {
+			return err
+		}
+
+		if paidUntil.After(time.Now()) {
+			res.grant(rule.Roles, rule.Scopes)
+			plans = appendUnique(plans, rule.Plan)
+			if paidUntil.After(expiresAt) {
+				expiresAt = paidUntil
+			}
+		} else if rule.Required && !complete {
+			return fmt.Errorf("%w: no payment for plan %s among the %d latest transactions of the payment address", ErrAccessDenied, rule.Plan, e.paymentsScanLimit)
+		} else if rule.Required {
+			return fmt.Errorf("%w: wallet has no active subscription to plan %s", ErrAccessDenied, rule.Plan)
+		}
+	}
+
+	if len(plans) > 0 {
+		res.Claims["subscription_plans"] = plans
+		res.Claims["subscription_expires_at"] = expiresAt.Unix()
+	}
+	return nil
+}
+
+// paidUntil returns when the latest payment of the wallet for the plan runs out, zero
+// without a payment in the last days of the plan. complete is false when the payments
+// of the whole period could not be read.
+func (e *Evaluator) paidUntil(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr string, rule *SubscriptionRule) (paidUntil time.Time, complete bool, err error) {
+	period := time.Duration(rule.Days) * 24 * time.Hour
+	txs, complete, err := e.Payments(ctx, provider, network, rule.Address, time.Now().Add(-period), 0)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	// jettons arrive as notifications from the jetton wallet of the payment address,
+	// anyone else can send a notification with any amount
+	var jettonWallet string
+	if rule.Jetton != "" {
+		j, err := e.JettonBalance(ctx, provider, network, rule.Address, rule.Jetton, 0)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if jettonWallet = j.Wallet; jettonWallet == "" {
+			return time.Time{}, true, nil
+		}
+	}
+
+	for _, tx := range txs {
+		var paid bool
+		if rule.Jetton == "" {
+			paid = tx.Jetton == nil && tx.From == addr && big.NewInt(tx.Value).Cmp(rule.minAmount) >= 0 &&
+				commentMatches(tx.Comment, rule.Comment)
+		} else if t := tx.Jetton; t != nil {
+			paid = tx.From == jettonWallet && t.Sender == addr && t.Amount.Cmp(rule.minAmount) >= 0 &&
+				commentMatches(t.Comment, rule.Comment)
+		}
+		if paid {
+			return tx.Time.Add(period), true, nil
+		}
+	}
+	return time.Time{}, complete, nil
+}
+
+func commentMatches(comment, want string) bool {
+	return want == "" || strings.EqualFold(strings.TrimSpace(comment), want)
+}
+
+// incoming keeps the incoming transactions of a payment address, newest first.
+type incoming struct {
+	mu  sync.Mutex
+	txs []chain.Transaction
+	// latest is the lt of the newest transaction read, incoming or not.
+	latest    uint64
+	fetchedAt time.Time
+	// horizon is the time from which on every transaction was read.
+	horizon time.Time
+	// truncated is set when the scan limit was reached: older transactions are not
+	// read, however far back they are asked for.
+	truncated bool
+}
+
+// Payments returns the incoming transactions of the address since the time, newest first.
+// Transactions are read once and topped up with new ones when older than maxAge, or the
+// payments max age when maxAge is zero, reading at most the payments scan limit at a time.
+// complete is false when the address has more transactions since the time than the scan
+// limit and only the latest ones are returned.
+func (e *Evaluator) Payments(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr string, since time.Time, maxAge time.Duration) (txs []chain.Transaction, complete bool, err error) {
+	if maxAge == 0 {
+		maxAge = e.paymentsMaxAge
+	}
+
+	key := string(network) + "/" + addr
+	e.paymentsMu.Lock()
+	in, ok := e.payments[key]
+	if !ok {
+		in = &incoming{}
+		e.payments[key] = in
+	}
+	e.paymentsMu.Unlock()
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	// a truncated scan would stop at the same limit again
+	if time.Since(in.fetchedAt) > maxAge || (in.horizon.After(since) && !in.truncated) {
+		if err := in.refresh(ctx, provider, addr, since, e.paymentsScanLimit); err != nil {
+			return nil, false, fmt.Errorf("failed to get payments: %w", err)
+		}
+	}
+
+	for _, tx := range in.txs {
+		if tx.Time.Before(since) {
+			break
+		}
+		txs = append(txs, tx)
+	}
+	return txs, !in.horizon.After(since), nil
+}
+
+// refresh reads the transactions newer than the known ones, or all back to since when
+// nothing is known yet or older transactions are needed and within the limit.
+func (in *incoming) refresh(ctx context.Context, provider chain.ChainProvider, addr string, since time.Time, limit int) error {
+	full := in.fetchedAt.IsZero() || (in.horizon.After(since) && !in.truncated)
+	var known uint64
+	if !full {
+		known = in.latest
+	}
+
+	var read []chain.Transaction
+	var before *chain.Transaction
+	latest, complete, count := uint64(0), false, 0
+	for !complete && count < limit {
+		page, err := provider.GetTransactions(ctx, addr, before, min(activityPage, limit-count))
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			complete = true
+			break
+		}
+		if latest == 0 {
+			latest = page[0].LT
+		}
+		for _, tx := range page {
+			if tx.LT <= known || tx.Time.Before(since) {
+				complete = true
+				break
+			}
+			count++
+			if tx.From != "" {
+				read = append(read, tx)
+			}
+		}
+		before = &page[len(page)-1]
+	}
+
+	if latest != 0 {
+		in.latest = latest
+	}
+	in.fetchedAt = time.Now()
+	switch {
+	case full && complete:
+		in.txs, in.horizon, in.truncated = read, since, false
+	case !complete:
+		// transactions between the ones read and the known ones were skipped
+		in.txs, in.horizon, in.truncated = read, before.Time, true
+	default:
+		in.txs = append(read, in.txs...)
+	}
+	if len(in.txs) > limit {
+		in.txs = in.txs[:limit]
+		in.horizon, in.truncated = in.txs[limit-1].Time, true
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package gating
+
+import (
+	"TON/internal/chain"
+	"TON/pkg/tonwallet"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const payer = "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+
+// countingProvider counts the transaction pages read.
+type countingProvider struct {
+	*chain.FixtureProvider
+	calls int
+}
+
+func (p *countingProvider) GetTransactions(ctx context.Context, addr string, before *chain.Transaction, limit int) ([]chain.Transaction, error) {
+	p.calls++
+	return p.FixtureProvider.GetTransactions(ctx, addr, before, limit)
+}
+
+// payments records count payments of 1 TON from the payer to the wallet, one an hour,
+// the newest now, and returns the provider with the time of the oldest one.
+func payments(count int) (*countingProvider, time.Time) {
+	p := &countingProvider{FixtureProvider: chain.NewFixtureProvider(tonwallet.Options{})}
+	now := time.Now()
+	for i := 0; i < count; i++ {
+		p.AddTransaction(wallet, chain.Transaction{
+			LT:    uint64(1000 + count - i),
+			Time:  now.Add(-time.Duration(i) * time.Hour),
+			From:  payer,
+			Value: 1_000_000_000,
+		})
+	}
+	return p, now.Add(-time.Duration(count-1) * time.Hour)
+}
+
+func TestNewEvaluatorLimits(t *testing.T) {
+	if _, err := NewEvaluator(time.Minute, time.Minute, 0, time.Minute, 100); err == nil {
+		t.Fatal("NewEvaluator() accepted an activity scan limit of 0")
+	}
+	if _, err := NewEvaluator(time.Minute, time.Minute, 100, time.Minute, 0); err == nil {
+		t.Fatal("NewEvaluator() accepted a payments scan limit of 0")
+	}
+}
+
+func TestPayments(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("several pages", func(t *testing.T) {
+		e, _ := NewEvaluator(time.Minute, time.Minute, 100, time.Hour, 1000)
+		p, oldest := payments(250)
+		txs, complete, err := e.Payments(ctx, p, chain.Mainnet, wallet, oldest.Add(-time.Minute), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txs) != 250 || !complete {
+			t.Fatalf("Payments() = %d transactions, complete=%v, want 250, true", len(txs), complete)
+		}
+		// three full pages and the empty one after them
+		if p.calls != 4 {
+			t.Fatalf("read %d pages, want 4", p.calls)
+		}
+
+		// new transactions are topped up without reading the known ones again
+		p.AddTransaction(wallet, chain.Transaction{LT: 2000, Time: time.Now(), From: payer, Value: 1})
+		txs, _, err = e.Payments(ctx, p, chain.Mainnet, wallet, oldest.Add(-time.Minute), time.Nanosecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txs) != 251 || txs[0].LT != 2000 || p.calls != 5 {
+			t.Fatalf("top-up = %d transactions after %d pages, want 251 after 5", len(txs), p.calls)
+		}
+	})
+
+	t.Run("beyond the scan limit", func(t *testing.T) {
+		e, _ := NewEvaluator(time.Minute, time.Minute, 100, time.Hour, 120)
+		p, oldest := payments(250)
+		for i := 0; i < 2; i++ {
+			txs, complete, err := e.Payments(ctx, p, chain.Mainnet, wallet, oldest.Add(-time.Minute), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(txs) != 120 || complete {
+				t.Fatalf("Payments() = %d transactions, complete=%v, want 120, false", len(txs), complete)
+			}
+		}
+		// the truncated scan is not repeated on every call
+		if p.calls != 2 {
+			t.Fatalf("read %d pages, want 2", p.calls)
+		}
+	})
+}
+
+func TestCheckSubscriptions(t *testing.T) {
+	rule := func(days int) Rules {
+		r := Rules{Subscriptions: []SubscriptionRule{{Plan: "pro", Address: wallet, MinAmount: "1", Days: days, Required: true}}}
+		if err := r.Prepare(100); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		count   int
+		limit   int
+		days    int
+		allowed bool
+		reason  string
+	}{
+		{"paid", 10, 100, 1, true, ""},
+		{"not paid", 0, 100, 1, false, "no active subscription"},
+		{"payment beyond the scan limit", 0, 5, 30, false, "5 latest transactions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEvaluator(time.Minute, time.Minute, 100, time.Hour, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, _ := payments(tt.count)
+			if tt.count == 0 {
+				// transfers from another wallet fill the history, the payment is the oldest
+				now := time.Now()
+				p.AddTransaction(wallet, chain.Transaction{LT: 10, Time: now.Add(-48 * time.Hour), From: payer, Value: 1_000_000_000})
+				for i := 0; i < 10; i++ {
+					p.AddTransaction(wallet, chain.Transaction{LT: uint64(100 + i), Time: now.Add(-time.Duration(i) * time.Hour), From: wallet, Value: 1})
+				}
+			}
+
+			r := rule(tt.days)
+			res := &Result{Claims: make(map[string]interface{})}
+			err = e.checkSubscriptions(context.Background(), p, chain.Mainnet, payer, &r, res)
+			if tt.allowed {
+				if err != nil {
+					t.Fatalf("checkSubscriptions() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrAccessDenied) || !strings.Contains(err.Error(), tt.reason) {
+				t.Fatalf("checkSubscriptions() error = %v, want %v mentioning %q", err, ErrAccessDenied, tt.reason)
+			}
+		})
+	}
+}
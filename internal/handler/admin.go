@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"TON/internal/chain"
+	"TON/internal/client"
+	"TON/internal/credential"
+	"TON/internal/dto"
+	"TON/internal/lists"
+	"TON/internal/usecase"
+	"TON/pkg/Json"
+	"TON/pkg/address"
+	"TON/pkg/logger"
+	"TON/pkg/validator"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// headerAdminActor names the admin making a change in the audit trail.
+const headerAdminActor = "X-Admin-Actor"
+
+type AdminHandler struct {
+	PolicyUseCase usecase.PolicyUseCase
+	ListsUseCase  usecase.ListsUseCase
+	AuditUseCase  usecase.AuditUseCase
+	// FixtureUseCase is nil unless the fixture chain provider is used.
+	FixtureUseCase    usecase.FixtureUseCase
+	CredentialUseCase usecase.CredentialUseCase
+	logger            logger.Logger
+	validator         *validator.CustomValidator
+}
+
+func NewAdminHandler(log logger.Logger, val *validator.CustomValidator, policy usecase.PolicyUseCase, lists usecase.ListsUseCase, audit usecase.AuditUseCase, fixture usecase.FixtureUseCase, credential usecase.CredentialUseCase) *AdminHandler {
+	return &AdminHandler{
+		logger:            log,
+		validator:         val,
+		PolicyUseCase:     policy,
+		ListsUseCase:      lists,
+		AuditUseCase:      audit,
+		FixtureUseCase:    fixture,
+		CredentialUseCase: credential,
+	}
+}
+
+// AdminAuth rejects requests without the admin token as bearer token.
+func AdminAuth(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			got, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				return Json.JSONError(c, http.StatusUnauthorized, "Unauthorized", "admin token required")
+			}
+			return next(c)
+		}
+	}
+}
+
+// PolicyDryRunHandler godoc
+// @Summary Evaluate an access policy
+// @Description Evaluate the access policy of a client, or the policy in the request, for a wallet without logging in.
+// @Description Returns the decision together with the facts it was made on.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param body body dto.PolicyDryRunRequestDTO true "Dry-run request"
+// @Success 200 {object} dto.PolicyDryRunResponseDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Bad request, invalid body, client or policy"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, admin token required"
+// @Failure 404 {object} dto.ErrorResponseDTO "Client has no policy"
+// @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
+// @Router /admin/policies/dry-run [post]
+func (h *AdminHandler) PolicyDryRunHandler(c echo.Context) error {
+	var req dto.PolicyDryRunRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	resp, err := h.PolicyUseCase.DryRun(req)
+	switch {
+	case errors.Is(err, client.ErrClientNotFound), errors.Is(err, chain.ErrNetworkNotSupported), errors.Is(err, usecase.ErrInvalidPolicy):
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	case errors.Is(err, usecase.ErrNoPolicy):
+		return Json.JSONError(c, http.StatusNotFound, "Policy not found", err.Error())
+	case err != nil:
+		h.logger.Error(c.Request().Context(), "policy dry-run failed: "+err.Error())
+		return Json.JSONError(c, http.StatusInternalServerError, "Policy dry-run failed", err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListEntriesHandler godoc
+// @Summary List allow or deny list entries
+// @Description Get the wallets on the allow or deny list that have not expired.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param kind path string true "List: allow or deny"
+// @Param client_id query string false "Only entries of the client"
+// @Success 200 {object} dto.ListEntriesResponseDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Unknown list"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, admin token required"
+// @Router /admin/lists/{kind} [get]
+func (h *AdminHandler) ListEntriesHandler(c echo.Context) error {
+	resp, err := h.ListsUseCase.ListEntries(c.Param("kind"), c.QueryParam("client_id"))
+	if err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid list", err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// AddListEntryHandler godoc
+// @Summary Add a wallet to the allow or deny list
+// @Description Put a wallet on the allow or deny list of all clients or of one client, optionally until it expires.
+// @Description An existing entry for the same wallet, list and client is replaced. The change is recorded in the audit trail.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param kind path string true "List: allow or deny"
+// @Param X-Admin-Actor header string false "Name of the admin for the audit trail"
+// @Param body body dto.ListEntryRequestDTO true "List entry"
+// @Success 201 {object} dto.ListEntryDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Bad request, invalid body, list, client or expiry"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, admin token required"
+// @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
+// @Router /admin/lists/{kind} [post]
+func (h *AdminHandler) AddListEntryHandler(c echo.Context) error {
+	var req dto.ListEntryRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+	}
+	req.Actor = actor(c)
+	req.IP = c.RealIP()
+
+	if err := h.validator.Validate(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	resp, err := h.ListsUseCase.AddEntry(c.Param("kind"), req)
+	switch {
+	case errors.Is(err, lists.ErrUnknownKind), errors.Is(err, client.ErrClientNotFound), errors.Is(err, usecase.ErrInvalidExpiry):
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	case err != nil:
+		h.logger.Error(c.Request().Context(), "failed to add list entry: "+err.Error())
+		return Json.JSONError(c, http.StatusInternalServerError, "Failed to add list entry", err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// RemoveListEntryHandler godoc
+// @Summary Remove a wallet from the allow or deny list
+// @Description Take a wallet off the allow or deny list of all clients or of one client. The change is recorded in the audit trail.
+// @Tags admin
+// @Security AdminToken
+// @Param kind path string true "List: allow or deny"
+// @Param address path string true "Wallet address"
+// @Param client_id query string false "Client of the entry, empty for the global entry"
+// @Param X-Admin-Actor header string false "Name of the admin for the audit trail"
+// @Success 204 "Removed"
+// @Failure 400 {object} dto.ErrorResponseDTO "Unknown list or invalid address"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, admin token required"
+// @Failure 404 {object} dto.ErrorResponseDTO "Entry not found"
+// @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
+// @Router /admin/lists/{kind}/{address} [delete]
+func (h *AdminHandler) RemoveListEntryHandler(c echo.Context) error {
+	err := h.ListsUseCase.RemoveEntry(c.Param("kind"), c.Param("address"), c.QueryParam("client_id"), actor(c), c.RealIP())
+	switch {
+	case errors.Is(err, lists.ErrUnknownKind), errors.Is(err, address.ErrInvalidAddress), errors.Is(err, address.ErrChecksum):
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	case errors.Is(err, lists.ErrNotFound):
+		return Json.JSONError(c, http.StatusNotFound, "Entry not found", err.Error())
+	case err != nil:
+		h.logger.Error(c.Request().Context(), "failed to remove list entry: "+err.Error())
+		return Json.JSONError(c, http.StatusInternalServerError, "Failed to remove list entry", err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AuditHandler godoc
+// @Summary Get the audit trail
+// @Description Get the latest audit events, newest first.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param limit query int false "Maximum number of events, 100 by default"
+// @Param action query string false "Only events whose action starts with the prefix, e.g. list."
+// @Success 200 {object} dto.AuditResponseDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Invalid limit"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, admin token required"
+// @Router /admin/audit [get]
+func (h *AdminHandler) AuditHandler(c echo.Context) error {
+	limit := 100
+	if s := c.QueryParam("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return Json.JSONError(c, http.StatusBadRequest, "Invalid limit", "limit must be a positive integer")
+		}
+		limit = n
+	}
+
+	return c.JSON(http.StatusOK, h.AuditUseCase.Recent(limit, c.QueryParam("action")))
+}
+
+// CredentialsHandler godoc
+// @Summary List issued credentials
+// @Description Get the verifiable credentials that have not expired, with their status list index and revocation.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param address query string false "Only credentials of the wallet"
+// @Success 200 {object} dto.CredentialsResponseDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Invalid address"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, admin token required"
+// @Router /admin/credentials [get]
+func (h *AdminHandler) CredentialsHandler(c echo.Context) error {
+	resp, err := h.CredentialUseCase.ListCredentials(c.QueryParam("address"))
+	if err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid address", err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RevokeCredentialHandler godoc
+// @Summary Revoke a credential
+// @Description Set the bit of a verifiable credential in the status list. The change is recorded in the audit trail.
+// @Tags admin
+// @Security AdminToken
+// @Param id path string true "Credential ID, a urn:uuid"
+// @Param reason query string false "Reason of the revocation"
+// @Param X-Admin-Actor header string false "Name of the admin for the audit trail"
+// @Success 204 "Revoked"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, admin token required"
+// @Failure 404 {object} dto.ErrorResponseDTO "Credential not found or expired"
+// @Failure 409 {object} dto.ErrorResponseDTO "Credential already revoked"
+// @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
+// @Router /admin/credentials/{id} [delete]
+func (h *AdminHandler) RevokeCredentialHandler(c echo.Context) error {
+	err := h.CredentialUseCase.Revoke(c.Param("id"), c.QueryParam("reason"), actor(c), c.RealIP())
+	switch {
+	case errors.Is(err, credential.ErrNotFound):
+		return Json.JSONError(c, http.StatusNotFound, "Credential not found", err.Error())
+	case errors.Is(err, credential.ErrRevoked):
+		return Json.JSONError(c, http.StatusConflict, "Credential already revoked", err.Error())
+	case err != nil:
+		h.logger.Error(c.Request().Context(), "failed to revoke credential: "+err.Error())
+		return Json.JSONError(c, http.StatusInternalServerError, "Failed to revoke credential", err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// FixtureTransactionHandler godoc
+// @Summary Add a transfer to the fixture chain
+// @Description Record an incoming transfer in the fixture chain provider, e.g. the transfer of a transaction mode login.
+// @Description Only available with the fixture chain provider.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param body body dto.FixtureTransactionRequestDTO true "Transfer"
+// @Success 201 {object} dto.FixtureTransactionResponseDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Bad request, invalid body, network or address"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, admin token required"
+// @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
+// @Router /admin/fixture/transactions [post]
+func (h *AdminHandler) FixtureTransactionHandler(c echo.Context) error {
+	var req dto.FixtureTransactionRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	resp, err := h.FixtureUseCase.AddTransaction(req)
+	switch {
+	case errors.Is(err, chain.ErrNetworkNotSupported), errors.Is(err, usecase.ErrNotFixture),
+		errors.Is(err, address.ErrInvalidAddress), errors.Is(err, address.ErrChecksum):
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	case err != nil:
+		h.logger.Error(c.Request().Context(), "failed to add fixture transaction: "+err.Error())
+		return Json.JSONError(c, http.StatusInternalServerError, "Failed to add transaction", err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// actor returns the admin named in the request, "admin" when none is named.
+func actor(c echo.Context) string {
+	if name := strings.TrimSpace(c.Request().Header.Get(headerAdminActor)); name != "" {
+		return name
+	}
+	return "admin"
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"TON/internal/bridge"
+	"TON/internal/dto"
+	"TON/pkg/logger"
+	"TON/pkg/validator"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxBridgeMessage is the largest message body the bridge accepts.
+const maxBridgeMessage = 64 * 1024
+
+type BridgeHandler struct {
+	hub       *bridge.Hub
+	heartbeat time.Duration
+	logger    logger.Logger
+	validator *validator.CustomValidator
+}
+
+func NewBridgeHandler(log logger.Logger, val *validator.CustomValidator, hub *bridge.Hub, heartbeat time.Duration) *BridgeHandler {
+	return &BridgeHandler{
+		hub:       hub,
+		heartbeat: heartbeat,
+		logger:    log,
+		validator: val,
+	}
+}
+
+// MessageHandler godoc
+// @Summary Send a bridge message
+// @Description Buffer an encrypted TonConnect message for the recipient session.
+// @Tags bridge
+// @Accept plain
+// @Produce json
+// @Param client_id query string true "Sender client id"
+// @Param to query string true "Recipient client id"
+// @Param ttl query int true "Message TTL in seconds"
+// @Param topic query string false "Message topic"
+// @Param body body string true "Base64 encoded message"
+// @Success 200 {object} dto.BridgeResponseDTO
+// @Failure 400 {object} dto.BridgeResponseDTO "Bad request"
+// @Failure 413 {object} dto.BridgeResponseDTO "Message larger than 64 KB"
+// @Failure 429 {object} dto.BridgeResponseDTO "Recipient buffer is full"
+// @Router /bridge/message [post]
+func (h *BridgeHandler) MessageHandler(c echo.Context) error {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, maxBridgeMessage))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return bridgeError(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("message is larger than %d bytes", maxBridgeMessage))
+	}
+	if err != nil {
+		return bridgeError(c, http.StatusBadRequest, "failed to read body")
+	}
+
+	ttl, err := strconv.Atoi(c.QueryParam("ttl"))
+	if err != nil {
+		return bridgeError(c, http.StatusBadRequest, "invalid ttl")
+	}
+
+	req := dto.BridgeMessageRequestDTO{
+		ClientID: c.QueryParam("client_id"),
+		To:       c.QueryParam("to"),
+		TTL:      ttl,
+		Topic:    c.QueryParam("topic"),
+		Message:  string(body),
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		return bridgeError(c, http.StatusBadRequest, err.Error())
+	}
+
+	if _, err := h.hub.Publish(req.ClientID, req.To, time.Duration(req.TTL)*time.Second, req.Topic, req.Message); err != nil {
+		h.logger.Error(c.Request().Context(), "failed to publish bridge message: "+err.Error())
+		if errors.Is(err, bridge.ErrMailboxFull) {
+			return bridgeError(c, http.StatusTooManyRequests, err.Error())
+		}
+		return bridgeError(c, http.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, dto.BridgeResponseDTO{Message: "OK", StatusCode: http.StatusOK})
+}
+
+// EventsHandler godoc
+// @Summary Subscribe to bridge events
+// @Description Open a server-sent events stream with messages for the given client ids.
+// @Description Reconnecting with last_event_id acknowledges every message up to that id.
+// @Tags bridge
+// @Produce text/event-stream
+// @Param client_id query string true "Comma separated client ids"
+// @Param last_event_id query string false "Id of the last received event"
+// @Success 200 {object} dto.BridgeEventDTO
+// @Failure 400 {object} dto.BridgeResponseDTO "Bad request"
+// @Failure 429 {object} dto.BridgeResponseDTO "Too many connections"
+// @Router /bridge/events [get]
+func (h *BridgeHandler) EventsHandler(c echo.Context) error {
+	clientIDs, err := bridge.ParseClientIDs(c.QueryParam("client_id"))
+	if err != nil {
+		return bridgeError(c, http.StatusBadRequest, err.Error())
+	}
+
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		return bridgeError(c, http.StatusBadRequest, "invalid last_event_id")
+	}
+
+	sub, backlog, err := h.hub.Subscribe(clientIDs, lastEventID)
+	if err != nil {
+		if errors.Is(err, bridge.ErrTooManyConnections) {
+			return bridgeError(c, http.StatusTooManyRequests, err.Error())
+		}
+		return bridgeError(c, http.StatusBadRequest, err.Error())
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	for _, msg := range backlog {
+		if err := writeBridgeEvent(res, msg); err != nil {
+			return nil
+		}
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case msg := <-sub.Events():
+			if err := writeBridgeEvent(res, msg); err != nil {
+				return nil
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(res, "event: heartbeat\ndata: heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-sub.Done():
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func writeBridgeEvent(res *echo.Response, msg bridge.Message) error {
+	data, err := json.Marshal(dto.BridgeEventDTO{From: msg.From, Message: msg.Body})
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(res, "id: %d\ndata: %s\n\n", msg.ID, data); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
+
+func parseLastEventID(c echo.Context) (uint64, error) {
+	raw := c.QueryParam("last_event_id")
+	if raw == "" {
+		raw = c.Request().Header.Get("Last-Event-ID")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(raw, 10, 64)
+}
+
+func bridgeError(c echo.Context, status int, msg string) error {
+	return c.JSON(status, dto.BridgeResponseDTO{Message: msg, StatusCode: status})
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"TON/internal/bridge"
+	"TON/pkg/logger"
+	"TON/pkg/validator"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	bridgeWallet = strings.Repeat("a", 64)
+	bridgeDapp   = strings.Repeat("b", 64)
+)
+
+func newBridgeHandler(heartbeat time.Duration) (*BridgeHandler, *bridge.Hub) {
+	hub := bridge.NewHub(bridge.Config{MaxTTL: time.Minute, MaxConnections: 1, BufferSize: 10, MaxClientIDs: 1})
+	return NewBridgeHandler(logger.New("test"), validator.NewCustomValidator(), hub, heartbeat), hub
+}
+
+func TestBridgeMessageSize(t *testing.T) {
+	h, _ := newBridgeHandler(time.Minute)
+	e := echo.New()
+
+	tests := []struct {
+		name   string
+		size   int
+		status int
+	}{
+		{"small", 48, http.StatusOK},
+		{"at the limit", maxBridgeMessage / 4 * 3, http.StatusOK},
+		{"too large", maxBridgeMessage, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := base64.StdEncoding.EncodeToString(make([]byte, tt.size))
+			req := httptest.NewRequest(http.MethodPost, "/bridge/message?client_id="+bridgeWallet+"&to="+bridgeDapp+"&ttl=60", strings.NewReader(body))
+			rec := httptest.NewRecorder()
+			if err := h.MessageHandler(e.NewContext(req, rec)); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+		})
+	}
+}
+
+func TestBridgeEventsHeartbeat(t *testing.T) {
+	h, hub := newBridgeHandler(10 * time.Millisecond)
+	if _, err := hub.Publish(bridgeWallet, bridgeDapp, time.Minute, "", "bWVzc2FnZQ=="); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/bridge/events?client_id="+bridgeDapp, nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	if err := h.EventsHandler(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the buffered message comes first, heartbeats follow while the stream is idle
+	out := rec.Body.String()
+	message := strings.Index(out, `data: {"from":"`+bridgeWallet+`","message":"bWVzc2FnZQ=="}`)
+	heartbeat := strings.Index(out, "event: heartbeat\ndata: heartbeat\n\n")
+	if message < 0 || heartbeat < message {
+		t.Fatalf("stream = %q, want the message and then a heartbeat", out)
+	}
+}
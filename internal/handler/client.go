@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"TON/internal/usecase"
+	"TON/pkg/Json"
+	"TON/pkg/logger"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ClientHandler struct {
+	ManifestUseCase usecase.ManifestUseCase
+	logger          logger.Logger
+}
+
+func NewClientHandler(log logger.Logger, manifest usecase.ManifestUseCase) *ClientHandler {
+	return &ClientHandler{
+		logger:          log,
+		ManifestUseCase: manifest,
+	}
+}
+
+// ManifestHandler godoc
+// @Summary Get TonConnect manifest
+// @Description Get the tonconnect-manifest.json of a registered client.
+// @Tags clients
+// @Produce json
+// @Param client_id path string true "Client ID"
+// @Success 200 {object} dto.TonConnectManifestDTO
+// @Failure 404 {object} dto.ErrorResponseDTO "Client not found"
+// @Router /clients/{client_id}/tonconnect-manifest.json [get]
+func (h *ClientHandler) ManifestHandler(c echo.Context) error {
+	resp, err := h.ManifestUseCase.GetManifest(c.Param("client_id"))
+	if err != nil {
+		return Json.JSONError(c, http.StatusNotFound, "Client not found", err.Error())
+	}
+
+	c.Response().Header().Set("Access-Control-Allow-Origin", "*")
+	return c.JSON(http.StatusOK, resp)
+}
+
+// IconHandler godoc
+// @Summary Get client icon
+// @Description Get the icon referenced by the TonConnect manifest of a registered client.
+// @Tags clients
+// @Produce image/png
+// @Param client_id path string true "Client ID"
+// @Success 200 {file} file
+// @Failure 404 {object} dto.ErrorResponseDTO "Icon not found"
+// @Router /clients/{client_id}/icon [get]
+func (h *ClientHandler) IconHandler(c echo.Context) error {
+	path, err := h.ManifestUseCase.GetIconPath(c.Param("client_id"))
+	if err != nil {
+		return Json.JSONError(c, http.StatusNotFound, "Icon not found", err.Error())
+	}
+
+	c.Response().Header().Set("Access-Control-Allow-Origin", "*")
+	return c.File(path)
+}
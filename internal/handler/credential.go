@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"TON/internal/credential"
+	"TON/internal/dto"
+	"TON/internal/gating"
+	"TON/internal/pow"
+	"TON/internal/usecase"
+	"TON/pkg/Json"
+	"TON/pkg/logger"
+	"TON/pkg/validator"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CredentialHandler struct {
+	CredentialUseCase usecase.CredentialUseCase
+	logger            logger.Logger
+	validator         *validator.CustomValidator
+}
+
+func NewCredentialHandler(log logger.Logger, val *validator.CustomValidator, credential usecase.CredentialUseCase) *CredentialHandler {
+	return &CredentialHandler{
+		logger:            log,
+		validator:         val,
+		CredentialUseCase: credential,
+	}
+}
+
+// IssueHandler godoc
+// @Summary Issue a verifiable credential
+// @Description Verify a signed message or ton_proof like /oauth/token and issue a W3C verifiable credential in JWT format attesting control of the wallet.
+// @Description The subject is the did:ton of the wallet or the did:key of its public key. The credential can be revoked and carries a StatusList2021 entry.
+// @Tags credentials
+// @Accept json
+// @Produce json
+// @Param body body dto.CredentialRequestDTO true "Credential request"
+// @Success 200 {object} dto.CredentialResponseDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Bad request, invalid body or validation failed"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, verification failed"
+// @Failure 403 {object} dto.ErrorResponseDTO "Forbidden, access_denied by the rules of the client"
+// @Failure 503 {object} dto.ErrorResponseDTO "Status list is full"
+// @Router /oauth/credentials [post]
+func (h *CredentialHandler) IssueHandler(c echo.Context) error {
+	var req dto.CredentialRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+	}
+	req.IP = c.RealIP()
+
+	if err := h.validator.Validate(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	resp, err := h.CredentialUseCase.Issue(req)
+	switch {
+	case errors.Is(err, pow.ErrRequired), errors.Is(err, pow.ErrInvalid):
+		return Json.JSONError(c, http.StatusUnauthorized, "Proof of work failed", err.Error())
+	case errors.Is(err, gating.ErrAccessDenied):
+		h.logger.Error(c.Request().Context(), "access denied: "+err.Error())
+		return Json.JSONError(c, http.StatusForbidden, "access_denied", err.Error())
+	case errors.Is(err, credential.ErrFull):
+		h.logger.Error(c.Request().Context(), "credential issuance failed: "+err.Error())
+		return Json.JSONError(c, http.StatusServiceUnavailable, "Credential issuance failed", err.Error())
+	case err != nil:
+		h.logger.Error(c.Request().Context(), "credential issuance failed: "+err.Error())
+		return Json.JSONError(c, http.StatusUnauthorized, "Credential issuance failed", err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// StatusListHandler godoc
+// @Summary Get the credential status list
+// @Description Get the StatusList2021 credential, a statuslist+jwt signed with the credential key, whose bits mark the revoked credentials.
+// @Description The list is signed for ten minutes; fetch it again after it expired.
+// @Tags credentials
+// @Produce application/jwt
+// @Success 200 {string} string "Status list credential"
+// @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
+// @Router /oauth/credentials/status [get]
+func (h *CredentialHandler) StatusListHandler(c echo.Context) error {
+	token, err := h.CredentialUseCase.StatusList()
+	if err != nil {
+		h.logger.Error(c.Request().Context(), "failed to sign status list: "+err.Error())
+		return Json.JSONError(c, http.StatusInternalServerError, "Failed to sign status list", err.Error())
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "max-age=60")
+	return c.Blob(http.StatusOK, "application/jwt", []byte(token))
+}
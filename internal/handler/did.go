@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"TON/internal/dto"
+	"TON/internal/usecase"
+	"TON/pkg/did"
+	"TON/pkg/logger"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// resolutionResultType is the media type of DID resolution results.
+const resolutionResultType = `application/ld+json;profile="https://w3id.org/did-resolution"`
+
+type DIDHandler struct {
+	DIDUseCase usecase.DIDUseCase
+	logger     logger.Logger
+}
+
+func NewDIDHandler(log logger.Logger, uc usecase.DIDUseCase) *DIDHandler {
+	return &DIDHandler{
+		logger:     log,
+		DIDUseCase: uc,
+	}
+}
+
+// ResolveHandler godoc
+// @Summary Resolve a did:ton identifier
+// @Description Build the DID document of a TON wallet, following the Universal Resolver driver API. The document holds the ed25519 key of the wallet read from the chain
+// @Description and services from the site and storage records of its TON DNS name. Testnet wallets are resolved from did:ton:testnet identifiers.
+// @Description Requests accepting application/did+ld+json get the document alone, all others the resolution result. Failures are resolution results with an error.
+// @Tags did
+// @Produce json
+// @Param did path string true "DID, e.g. did:ton:0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+// @Success 200 {object} dto.DIDResolutionDTO
+// @Failure 400 {object} dto.DIDResolutionDTO "invalidDid, the identifier is no valid DID"
+// @Failure 404 {object} dto.DIDResolutionDTO "notFound, the wallet does not exist"
+// @Failure 500 {object} dto.DIDResolutionDTO "internalError, the chain could not be read"
+// @Failure 501 {object} dto.DIDResolutionDTO "methodNotSupported, the DID is no did:ton"
+// @Router /1.0/identifiers/{did} [get]
+func (h *DIDHandler) ResolveHandler(c echo.Context) error {
+	c.Response().Header().Set("Access-Control-Allow-Origin", "*")
+
+	id, err := url.PathUnescape(c.Param("did"))
+	if err != nil {
+		return resolutionError(c, http.StatusBadRequest, "invalidDid", err)
+	}
+
+	resp, err := h.DIDUseCase.Resolve(id)
+	switch {
+	case errors.Is(err, did.ErrInvalidDID):
+		return resolutionError(c, http.StatusBadRequest, "invalidDid", err)
+	case errors.Is(err, did.ErrNotFound):
+		return resolutionError(c, http.StatusNotFound, "notFound", err)
+	case errors.Is(err, did.ErrMethodNotSupported):
+		return resolutionError(c, http.StatusNotImplemented, "methodNotSupported", err)
+	case err != nil:
+		return resolutionError(c, http.StatusInternalServerError, "internalError", err)
+	}
+
+	if strings.Contains(c.Request().Header.Get(echo.HeaderAccept), usecase.DIDContentType) {
+		return writeJSON(c, http.StatusOK, usecase.DIDContentType, resp.DIDDocument)
+	}
+	return writeJSON(c, http.StatusOK, resolutionResultType, resp)
+}
+
+// resolutionError responds with a resolution result holding the error code.
+func resolutionError(c echo.Context, status int, code string, err error) error {
+	return writeJSON(c, status, resolutionResultType, &dto.DIDResolutionDTO{
+		Context: usecase.DIDResolutionContext,
+		DIDResolutionMetadata: dto.DIDResolutionMetadataDTO{
+			Error:        code,
+			ErrorMessage: err.Error(),
+		},
+	})
+}
+
+// writeJSON responds with the body in JSON under a JSON-LD media type.
+func writeJSON(c echo.Context, status int, contentType string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.Blob(status, contentType, data)
+}
@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"TON/internal/chain"
+	"TON/internal/client"
 	"TON/internal/dto"
+	"TON/internal/gating"
+	"TON/internal/pow"
+	"TON/internal/txauth"
 	"TON/internal/usecase"
 	"TON/pkg/Json"
 	"TON/pkg/logger"
 	"TON/pkg/validator"
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -17,6 +23,8 @@ type OauthHandler struct {
 	TokenUseCase       usecase.TokenUseCase
 	JWKSUseCase        usecase.JWKSUseCase
 	TokenVerifyUseCase usecase.TokenVerifyUseCase
+	WalletsUseCase     usecase.WalletsUseCase
+	TransactionUseCase usecase.TransactionUseCase
 	logger             logger.Logger
 	validator          *validator.CustomValidator
 }
@@ -29,6 +37,8 @@ func NewOauthHandler(
 	token usecase.TokenUseCase,
 	jwks usecase.JWKSUseCase,
 	tokenVerify usecase.TokenVerifyUseCase,
+	wallets usecase.WalletsUseCase,
+	transaction usecase.TransactionUseCase,
 ) *OauthHandler {
 	return &OauthHandler{
 		logger:             log,
@@ -38,27 +48,37 @@ func NewOauthHandler(
 		TokenUseCase:       token,
 		JWKSUseCase:        jwks,
 		TokenVerifyUseCase: tokenVerify,
+		WalletsUseCase:     wallets,
+		TransactionUseCase: transaction,
 	}
 }
 
 // AuthorizeHandler godoc
 // @Summary Generate authorization challenge
 // @Description Generate a one-time nonce for TON OAuth.
+// @Description In transaction mode the response carries a transfer to send from the wallet instead, and the challenge is polled at /oauth/transaction/{challenge}.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param redirect_uri query string true "Redirect URI"
+// @Param client_id query string false "Registered client ID"
 // @Param scope query string false "Scope"
+// @Param mode query string false "Login mode: signature (default) or transaction"
 // @Success 200 {object} dto.AuthorizeResponseDTO
 // @Failure 400 {object} dto.ErrorResponseDTO "Validation failed"
 // @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized"
 // @Failure 403 {object} dto.ErrorResponseDTO "Forbidden"
 // @Failure 404 {object} dto.ErrorResponseDTO "Not found"
+// @Failure 429 {object} dto.ErrorResponseDTO "Too many pending transaction challenges"
 // @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
 // @Router /oauth/authorize [get]
 func (h *OauthHandler) AuthorizeHandler(c echo.Context) error {
 	req := dto.AuthorizeRequestDTO{
 		RedirectURI: c.QueryParam("redirect_uri"),
+		ClientID:    c.QueryParam("client_id"),
+		Scope:       c.QueryParam("scope"),
+		Mode:        c.QueryParam("mode"),
+		IP:          c.RealIP(),
 	}
 
 	if err := h.validator.Validate(&req); err != nil {
@@ -66,6 +86,15 @@ func (h *OauthHandler) AuthorizeHandler(c echo.Context) error {
 	}
 
 	resp, err := h.AuthorizeUseCase.Authorize(req)
+	if errors.Is(err, client.ErrClientNotFound) || errors.Is(err, client.ErrRedirectNotAllowed) {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid client", err.Error())
+	}
+	if errors.Is(err, txauth.ErrDisabled) || errors.Is(err, chain.ErrNetworkNotSupported) {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid mode", err.Error())
+	}
+	if errors.Is(err, txauth.ErrTooMany) {
+		return Json.JSONError(c, http.StatusTooManyRequests, "Too many pending challenges", err.Error())
+	}
 	if err != nil {
 		h.logger.Error(c.Request().Context(), "failed to generate challenge: "+err.Error())
 		return Json.JSONError(c, http.StatusInternalServerError, "Failed to generate challenge", err.Error())
@@ -74,9 +103,49 @@ func (h *OauthHandler) AuthorizeHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// WalletsHandler godoc
+// @Summary List wallets of a public key
+// @Description List the wallet contracts controlled by a public key with version, balance and status.
+// @Description Wallets are ordered by the wallet version preference; the first one is used when verify or token get no address.
+// @Tags auth
+// @Produce json
+// @Param public_key query string true "Hex encoded public key"
+// @Param network query string false "TonConnect chain id (-239 or -3)"
+// @Param client_id query string false "Registered client ID"
+// @Success 200 {object} dto.WalletsResponseDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Validation failed"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponseDTO "Forbidden"
+// @Failure 404 {object} dto.ErrorResponseDTO "Not found"
+// @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
+// @Router /oauth/wallets [get]
+func (h *OauthHandler) WalletsHandler(c echo.Context) error {
+	req := dto.WalletsRequestDTO{
+		PublicKey: c.QueryParam("public_key"),
+		Network:   c.QueryParam("network"),
+		ClientID:  c.QueryParam("client_id"),
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	resp, err := h.WalletsUseCase.ListWallets(req)
+	if errors.Is(err, chain.ErrNetworkNotSupported) {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid network", err.Error())
+	}
+	if err != nil {
+		h.logger.Error(c.Request().Context(), "failed to list wallets: "+err.Error())
+		return Json.JSONError(c, http.StatusInternalServerError, "Failed to list wallets", err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
 // VerifyHandler godoc
 // @Summary Verify TON wallet signature
-// @Description Verify signed message from TON wallet using ed25519.
+// @Description Verify signed message or TonConnect ton_proof from TON wallet using ed25519.
+// @Description A ton_proof must carry the domain of the manifest URL of the given client.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -84,7 +153,7 @@ func (h *OauthHandler) AuthorizeHandler(c echo.Context) error {
 // @Success 200 {object} dto.VerifyResponseDTO
 // @Failure 400 {object} dto.ErrorResponseDTO "Bad request, invalid body or validation failed"
 // @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, signature invalid"
-// @Failure 403 {object} dto.ErrorResponseDTO "Forbidden"
+// @Failure 403 {object} dto.ErrorResponseDTO "Forbidden, access_denied by the rules of the client"
 // @Failure 404 {object} dto.ErrorResponseDTO "Not found"
 // @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
 // @Router /oauth/verify [post]
@@ -93,12 +162,20 @@ func (h *OauthHandler) VerifyHandler(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return Json.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
 	}
+	req.IP = c.RealIP()
 
 	if err := h.validator.Validate(&req); err != nil {
 		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
 	}
 
 	resp, err := h.VerifyUseCase.Verify(req)
+	if errors.Is(err, pow.ErrRequired) || errors.Is(err, pow.ErrInvalid) {
+		return Json.JSONError(c, http.StatusUnauthorized, "Proof of work failed", err.Error())
+	}
+	if errors.Is(err, gating.ErrAccessDenied) {
+		h.logger.Error(c.Request().Context(), "access denied: "+err.Error())
+		return Json.JSONError(c, http.StatusForbidden, "access_denied", err.Error())
+	}
 	if err != nil {
 		h.logger.Error(c.Request().Context(), "verification failed: "+err.Error())
 		return Json.JSONError(c, http.StatusUnauthorized, "Signature verification failed", err.Error())
@@ -109,7 +186,7 @@ func (h *OauthHandler) VerifyHandler(c echo.Context) error {
 
 // TokenHandler godoc
 // @Summary Create JWT token
-// @Description Create JWT after successful verification of TON wallet.
+// @Description Verify a signed message or ton_proof like /oauth/verify and create a JWT with the wallet address as sub and its network.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -117,7 +194,7 @@ func (h *OauthHandler) VerifyHandler(c echo.Context) error {
 // @Success 200 {object} dto.TokenResponseDTO
 // @Failure 400 {object} dto.ErrorResponseDTO "Bad request, invalid body or validation failed"
 // @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, verification failed"
-// @Failure 403 {object} dto.ErrorResponseDTO "Forbidden"
+// @Failure 403 {object} dto.ErrorResponseDTO "Forbidden, access_denied by the rules of the client"
 // @Failure 404 {object} dto.ErrorResponseDTO "Not found"
 // @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
 // @Router /oauth/token [post]
@@ -126,12 +203,20 @@ func (h *OauthHandler) TokenHandler(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return Json.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
 	}
+	req.IP = c.RealIP()
 
 	if err := h.validator.Validate(&req); err != nil {
 		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
 	}
 
 	resp, err := h.TokenUseCase.CreateToken(req)
+	if errors.Is(err, pow.ErrRequired) || errors.Is(err, pow.ErrInvalid) {
+		return Json.JSONError(c, http.StatusUnauthorized, "Proof of work failed", err.Error())
+	}
+	if errors.Is(err, gating.ErrAccessDenied) {
+		h.logger.Error(c.Request().Context(), "access denied: "+err.Error())
+		return Json.JSONError(c, http.StatusForbidden, "access_denied", err.Error())
+	}
 	if err != nil {
 		h.logger.Error(c.Request().Context(), "token creation failed: "+err.Error())
 		return Json.JSONError(c, http.StatusUnauthorized, "Token creation failed", err.Error())
@@ -140,6 +225,84 @@ func (h *OauthHandler) TokenHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// TransactionStatusHandler godoc
+// @Summary Get transaction challenge state
+// @Description Get the state of a transaction mode login: pending until the transfer from the wallet is seen on chain, then approved.
+// @Description With wait the request is held until the challenge leaves the pending state, for at most a minute.
+// @Tags auth
+// @Produce json
+// @Param challenge path string true "Challenge returned by /oauth/authorize in transaction mode"
+// @Param wait query string false "How long to wait while pending, e.g. 30s"
+// @Success 200 {object} dto.TransactionStatusResponseDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Validation failed"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponseDTO "Forbidden"
+// @Failure 404 {object} dto.ErrorResponseDTO "Challenge not found"
+// @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
+// @Router /oauth/transaction/{challenge} [get]
+func (h *OauthHandler) TransactionStatusHandler(c echo.Context) error {
+	req := dto.TransactionStatusRequestDTO{
+		Challenge: c.Param("challenge"),
+		Wait:      c.QueryParam("wait"),
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	resp, err := h.TransactionUseCase.Status(c.Request().Context(), req)
+	if errors.Is(err, txauth.ErrNotFound) {
+		return Json.JSONError(c, http.StatusNotFound, "Challenge not found", err.Error())
+	}
+	if err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// TransactionTokenHandler godoc
+// @Summary Create JWT token for a transaction challenge
+// @Description Exchange an approved transaction mode login for a JWT with the address of the sending wallet as sub. A challenge is exchanged once.
+// @Tags auth
+// @Produce json
+// @Param challenge path string true "Challenge returned by /oauth/authorize in transaction mode"
+// @Success 200 {object} dto.TokenResponseDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Challenge pending, expired or already used"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponseDTO "Forbidden, access_denied by the rules of the client"
+// @Failure 404 {object} dto.ErrorResponseDTO "Challenge not found"
+// @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
+// @Router /oauth/transaction/{challenge}/token [post]
+func (h *OauthHandler) TransactionTokenHandler(c echo.Context) error {
+	req := dto.TransactionTokenRequestDTO{
+		Challenge: c.Param("challenge"),
+		IP:        c.RealIP(),
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	resp, err := h.TokenUseCase.CreateTransactionToken(req)
+	if errors.Is(err, txauth.ErrNotFound) {
+		return Json.JSONError(c, http.StatusNotFound, "Challenge not found", err.Error())
+	}
+	if errors.Is(err, txauth.ErrPending) || errors.Is(err, txauth.ErrExpired) || errors.Is(err, txauth.ErrUsed) {
+		return Json.JSONError(c, http.StatusBadRequest, "Challenge not approved", err.Error())
+	}
+	if errors.Is(err, gating.ErrAccessDenied) {
+		h.logger.Error(c.Request().Context(), "access denied: "+err.Error())
+		return Json.JSONError(c, http.StatusForbidden, "access_denied", err.Error())
+	}
+	if err != nil {
+		h.logger.Error(c.Request().Context(), "token creation failed: "+err.Error())
+		return Json.JSONError(c, http.StatusInternalServerError, "Token creation failed", err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
 // JWKSHandler godoc
 // @Summary Get JSON Web Key Set (JWKS)
 // @Description Get public keys to verify JWT tokens issued by TON OAuth.
@@ -163,7 +326,7 @@ func (h *OauthHandler) JWKSHandler(c echo.Context) error {
 
 // VerifyTokenHandler godoc
 // @Summary Verify JWT token
-// @Description Verify JWT issued by TON OAuth service.
+// @Description Verify an access token issued by TON OAuth service. Tokens of another typ than at+jwt, such as attestations and credentials, are refused.
 // @Tags auth
 // @Accept json
 // @Produce json
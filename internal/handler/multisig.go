@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"TON/internal/chain"
+	"TON/internal/client"
+	"TON/internal/dto"
+	"TON/internal/gating"
+	"TON/internal/multisig"
+	"TON/internal/usecase"
+	"TON/pkg/Json"
+	"TON/pkg/address"
+	"TON/pkg/logger"
+	"TON/pkg/validator"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MultisigHandler struct {
+	MultisigUseCase usecase.MultisigUseCase
+	TokenUseCase    usecase.TokenUseCase
+	logger          logger.Logger
+	validator       *validator.CustomValidator
+}
+
+func NewMultisigHandler(log logger.Logger, val *validator.CustomValidator, multisig usecase.MultisigUseCase, token usecase.TokenUseCase) *MultisigHandler {
+	return &MultisigHandler{
+		logger:          log,
+		validator:       val,
+		MultisigUseCase: multisig,
+		TokenUseCase:    token,
+	}
+}
+
+// StartHandler godoc
+// @Summary Start a multisig login
+// @Description Read the owners and threshold of a multisig contract and open a session collecting their signatures.
+// @Description Share the challenge with the owners; each signs the message, or a ton_proof with the challenge as payload.
+// @Tags multisig
+// @Accept json
+// @Produce json
+// @Param body body dto.MultisigStartRequestDTO true "Multisig contract"
+// @Success 201 {object} dto.MultisigSessionDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Bad request, invalid body, client, network or no multisig contract"
+// @Failure 429 {object} dto.ErrorResponseDTO "Too many open sessions"
+// @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
+// @Router /oauth/multisig [post]
+func (h *MultisigHandler) StartHandler(c echo.Context) error {
+	var req dto.MultisigStartRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	resp, err := h.MultisigUseCase.Start(req)
+	switch {
+	case errors.Is(err, client.ErrClientNotFound), errors.Is(err, chain.ErrNetworkNotSupported),
+		errors.Is(err, address.ErrInvalidAddress), errors.Is(err, address.ErrChecksum), errors.Is(err, multisig.ErrNotMultisig):
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	case errors.Is(err, multisig.ErrTooMany):
+		return Json.JSONError(c, http.StatusTooManyRequests, "Too many open sessions", err.Error())
+	case err != nil:
+		h.logger.Error(c.Request().Context(), "failed to start multisig session: "+err.Error())
+		return Json.JSONError(c, http.StatusInternalServerError, "Failed to start multisig session", err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// SessionHandler godoc
+// @Summary Get a multisig login
+// @Description Get the owners of a multisig session, who signed and whether the threshold is reached.
+// @Tags multisig
+// @Produce json
+// @Param challenge path string true "Session challenge"
+// @Success 200 {object} dto.MultisigSessionDTO
+// @Failure 404 {object} dto.ErrorResponseDTO "Session not found"
+// @Router /oauth/multisig/{challenge} [get]
+func (h *MultisigHandler) SessionHandler(c echo.Context) error {
+	resp, err := h.MultisigUseCase.Session(c.Param("challenge"))
+	if err != nil {
+		return Json.JSONError(c, http.StatusNotFound, "Session not found", err.Error())
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SignHandler godoc
+// @Summary Add an owner signature to a multisig login
+// @Description Verify the signature of an owner over the session message, or its ton_proof with the challenge as payload, and record it.
+// @Tags multisig
+// @Accept json
+// @Produce json
+// @Param challenge path string true "Session challenge"
+// @Param body body dto.MultisigSignRequestDTO true "Owner signature"
+// @Success 200 {object} dto.MultisigSessionDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Bad request, invalid body, session expired or used"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, signature invalid"
+// @Failure 403 {object} dto.ErrorResponseDTO "Forbidden, key is not an owner"
+// @Failure 404 {object} dto.ErrorResponseDTO "Session not found"
+// @Router /oauth/multisig/{challenge}/signatures [post]
+func (h *MultisigHandler) SignHandler(c echo.Context) error {
+	var req dto.MultisigSignRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+	}
+	req.Challenge = c.Param("challenge")
+
+	if err := h.validator.Validate(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	resp, err := h.MultisigUseCase.Sign(req)
+	switch {
+	case errors.Is(err, multisig.ErrNotFound):
+		return Json.JSONError(c, http.StatusNotFound, "Session not found", err.Error())
+	case errors.Is(err, multisig.ErrNotOwner):
+		return Json.JSONError(c, http.StatusForbidden, "access_denied", err.Error())
+	case errors.Is(err, multisig.ErrExpired), errors.Is(err, multisig.ErrUsed), errors.Is(err, client.ErrClientNotFound):
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	case err != nil:
+		return Json.JSONError(c, http.StatusUnauthorized, "Signature verification failed", err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// TokenHandler godoc
+// @Summary Create JWT token for a multisig login
+// @Description Exchange a multisig session signed by enough owners for a JWT with the contract address as sub and the signers in the act and signers claims.
+// @Description The owners are read again, so signers removed since the session started do not count. A session is exchanged once.
+// @Tags multisig
+// @Produce json
+// @Param challenge path string true "Session challenge"
+// @Success 200 {object} dto.TokenResponseDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Threshold not reached, session expired or used"
+// @Failure 403 {object} dto.ErrorResponseDTO "Forbidden, access_denied by the rules of the client"
+// @Failure 404 {object} dto.ErrorResponseDTO "Session not found"
+// @Failure 500 {object} dto.ErrorResponseDTO "Internal server error"
+// @Router /oauth/multisig/{challenge}/token [post]
+func (h *MultisigHandler) TokenHandler(c echo.Context) error {
+	req := dto.MultisigTokenRequestDTO{
+		Challenge: c.Param("challenge"),
+		IP:        c.RealIP(),
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	resp, err := h.TokenUseCase.CreateMultisigToken(req)
+	switch {
+	case errors.Is(err, multisig.ErrNotFound):
+		return Json.JSONError(c, http.StatusNotFound, "Session not found", err.Error())
+	case errors.Is(err, multisig.ErrThreshold), errors.Is(err, multisig.ErrExpired), errors.Is(err, multisig.ErrUsed):
+		return Json.JSONError(c, http.StatusBadRequest, "Session not complete", err.Error())
+	case errors.Is(err, gating.ErrAccessDenied):
+		h.logger.Error(c.Request().Context(), "access denied: "+err.Error())
+		return Json.JSONError(c, http.StatusForbidden, "access_denied", err.Error())
+	case err != nil:
+		h.logger.Error(c.Request().Context(), "token creation failed: "+err.Error())
+		return Json.JSONError(c, http.StatusInternalServerError, "Token creation failed", err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
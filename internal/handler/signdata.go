@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"TON/internal/chain"
+	"TON/internal/client"
+	"TON/internal/dto"
+	"TON/internal/usecase"
+	"TON/pkg/Json"
+	"TON/pkg/address"
+	"TON/pkg/logger"
+	"TON/pkg/validator"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SignDataHandler struct {
+	SignDataUseCase usecase.SignDataUseCase
+	logger          logger.Logger
+	validator       *validator.CustomValidator
+}
+
+func NewSignDataHandler(log logger.Logger, val *validator.CustomValidator, signData usecase.SignDataUseCase) *SignDataHandler {
+	return &SignDataHandler{
+		logger:          log,
+		validator:       val,
+		SignDataUseCase: signData,
+	}
+}
+
+// VerifyHandler godoc
+// @Summary Verify a TonConnect signData result
+// @Description Verify the signature of a text, binary or cell payload signed with TonConnect signData against the key of the wallet.
+// @Description The domain must be the manifest domain of the client and the timestamp recent. The response carries a JWT attesting that the wallet approved the payload.
+// @Tags sign-data
+// @Accept json
+// @Produce json
+// @Param body body dto.SignDataRequestDTO true "signData result"
+// @Success 200 {object} dto.SignDataResponseDTO
+// @Failure 400 {object} dto.ErrorResponseDTO "Bad request, invalid body, client, network or address"
+// @Failure 401 {object} dto.ErrorResponseDTO "Unauthorized, signature invalid"
+// @Router /oauth/sign-data [post]
+func (h *SignDataHandler) VerifyHandler(c echo.Context) error {
+	var req dto.SignDataRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		return Json.JSONError(c, http.StatusBadRequest, "Validation failed", err.Error())
+	}
+
+	resp, err := h.SignDataUseCase.Verify(req)
+	switch {
+	case errors.Is(err, client.ErrClientNotFound), errors.Is(err, chain.ErrNetworkNotSupported),
+		errors.Is(err, address.ErrInvalidAddress), errors.Is(err, address.ErrChecksum):
+		return Json.JSONError(c, http.StatusBadRequest, "Invalid request", err.Error())
+	case err != nil:
+		h.logger.Error(c.Request().Context(), "sign data verification failed: "+err.Error())
+		return Json.JSONError(c, http.StatusUnauthorized, "Signature verification failed", err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
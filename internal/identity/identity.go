@@ -0,0 +1,73 @@
+package identity
+
+import (
+	"TON/internal/chain"
+	"TON/pkg/tonwallet"
+	"crypto/ed25519"
+	"slices"
+	"strings"
+)
+
+// Identity is a wallet whose control was proven during login.
+type Identity struct {
+	// Address of the wallet in the raw "wc:hex" form.
+	Address   string
+	Network   chain.Network
+	PublicKey ed25519.PublicKey
+	Version   tonwallet.Version
+	// State of the wallet account at login; uninit and nonexist wallets were proven
+	// by their StateInit.
+	State    chain.AccountStatus
+	ClientID string
+	// Name is the TON DNS name that resolves back to the wallet, empty when it has none.
+	Name string
+	// Roles and Scopes granted by the rules of the client.
+	Roles  []string
+	Scopes []string
+	// Extra claims added by the rules of the client.
+	Extra map[string]interface{}
+}
+
+// Claims returns the token claims describing the wallet. The claims of the wallet are
+// applied after Extra, so extra claims cannot replace them.
+func (i *Identity) Claims() map[string]interface{} {
+	claims := make(map[string]interface{}, len(i.Extra)+5)
+	for k, v := range i.Extra {
+		claims[k] = v
+	}
+	claims["sub"] = i.Address
+	claims["network"] = string(i.Network)
+	claims["wallet_state"] = string(i.State)
+	if i.Name != "" {
+		claims["preferred_username"] = i.Name
+		claims["name"] = i.Name
+	}
+	if len(i.Roles) > 0 {
+		claims["roles"] = i.Roles
+	}
+	if len(i.Scopes) > 0 {
+		claims["scope"] = strings.Join(i.Scopes, " ")
+	}
+	return claims
+}
+
+// Grant adds roles, scopes and claims, skipping roles and scopes already granted.
+// Later claims replace earlier ones with the same name.
+func (i *Identity) Grant(roles, scopes []string, claims map[string]interface{}) {
+	for _, role := range roles {
+		if !slices.Contains(i.Roles, role) {
+			i.Roles = append(i.Roles, role)
+		}
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(i.Scopes, scope) {
+			i.Scopes = append(i.Scopes, scope)
+		}
+	}
+	if len(claims) > 0 && i.Extra == nil {
+		i.Extra = make(map[string]interface{}, len(claims))
+	}
+	for k, v := range claims {
+		i.Extra[k] = v
+	}
+}
@@ -0,0 +1,38 @@
+package identity
+
+import (
+	"TON/internal/chain"
+	"testing"
+)
+
+func TestClaimsKeepWalletClaims(t *testing.T) {
+	id := &Identity{
+		Address: "0:960ab627408d5472d9d125b667cbe00ce17eeaa44e9dc6a86e93cdfef2c480d5",
+		Network: chain.Mainnet,
+		State:   chain.StatusActive,
+		Roles:   []string{"member"},
+	}
+	id.Grant([]string{"admin"}, []string{"read"}, map[string]interface{}{
+		"sub":     "0:0000000000000000000000000000000000000000000000000000000000000000",
+		"network": "-3",
+		"roles":   []string{"owner"},
+		"tier":    "gold",
+	})
+
+	claims := id.Claims()
+	want := map[string]interface{}{
+		"sub":          id.Address,
+		"network":      string(chain.Mainnet),
+		"wallet_state": string(chain.StatusActive),
+		"scope":        "read",
+		"tier":         "gold",
+	}
+	for k, v := range want {
+		if claims[k] != v {
+			t.Errorf("claims[%s] = %v, want %v", k, claims[k], v)
+		}
+	}
+	if roles, _ := claims["roles"].([]string); len(roles) != 2 || roles[0] != "member" || roles[1] != "admin" {
+		t.Errorf("claims[roles] = %v, want [member admin]", claims["roles"])
+	}
+}
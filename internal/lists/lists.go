@@ -0,0 +1,256 @@
+package lists
+
+import (
+	"TON/pkg/address"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrDenied is returned for wallets on a deny list.
+	ErrDenied = errors.New("wallet is blocked")
+	// ErrNotAllowed is returned for wallets missing from an enforced allow list.
+	ErrNotAllowed  = errors.New("wallet is not on the allow list")
+	ErrNotFound    = errors.New("list entry not found")
+	ErrUnknownKind = errors.New("unknown list, expected allow or deny")
+)
+
+type Kind string
+
+const (
+	Allow Kind = "allow"
+	Deny  Kind = "deny"
+)
+
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(s); k {
+	case Allow, Deny:
+		return k, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownKind, s)
+	}
+}
+
+// Entry puts a wallet on a list, globally or for one client.
+type Entry struct {
+	Kind Kind `json:"kind"`
+	// Address of the wallet in the raw "wc:hex" form.
+	Address string `json:"address"`
+	// ClientID limits the entry to a client, empty for a global entry.
+	ClientID  string     `json:"clientId,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	CreatedBy string     `json:"createdBy,omitempty"`
+}
+
+// Expired reports whether the entry no longer applies at now.
+func (e *Entry) Expired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+func key(kind Kind, clientID, addr string) string {
+	return string(kind) + "/" + clientID + "/" + addr
+}
+
+// Store keeps the allow and deny lists in a JSON file.
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	entries map[string]*Entry
+}
+
+// Open loads the lists from path. A missing file yields empty lists; without a path
+// the lists are not persisted.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, entries: make(map[string]*Entry)}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Entries []*Entry `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, e := range file.Entries {
+		s.entries[key(e.Kind, e.ClientID, e.Address)] = e
+	}
+	return s, nil
+}
+
+// Add puts the wallet on the list, replacing an entry for the same list and client.
+func (s *Store) Add(e Entry) (*Entry, error) {
+	if _, err := ParseKind(string(e.Kind)); err != nil {
+		return nil, err
+	}
+	addr, err := address.Normalize(e.Address)
+	if err != nil {
+		return nil, err
+	}
+	e.Address = addr
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = s.update(func(entries map[string]*Entry) {
+		entries[key(e.Kind, e.ClientID, e.Address)] = &e
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Remove takes the wallet off the list and returns the removed entry.
+func (s *Store) Remove(kind Kind, clientID, addr string) (*Entry, error) {
+	addr, err := address.Normalize(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key(kind, clientID, addr)
+	e, ok := s.entries[k]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	err = s.update(func(entries map[string]*Entry) {
+		delete(entries, k)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// List returns the entries of the list that have not expired, the global ones and
+// those of every client when clientID is empty.
+func (s *Store) List(kind Kind, clientID string) []Entry {
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]Entry, 0)
+	for _, e := range s.entries {
+		if e.Kind != kind || e.Expired(now) || (clientID != "" && e.ClientID != clientID) {
+			continue
+		}
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries
+}
+
+// Lookup returns the entry that puts the wallet on the list for the client, preferring
+// the entry of the client over a global one, nil when there is none.
+func (s *Store) Lookup(kind Kind, clientID, addr string) *Entry {
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if clientID != "" {
+		if e, ok := s.entries[key(kind, clientID, addr)]; ok && !e.Expired(now) {
+			return e
+		}
+	}
+	if e, ok := s.entries[key(kind, "", addr)]; ok && !e.Expired(now) {
+		return e
+	}
+	return nil
+}
+
+// Check rejects wallets on a deny list and, when allowlistOnly is set, wallets on
+// no allow list. Deny entries win over allow entries.
+func (s *Store) Check(clientID, addr string, allowlistOnly bool) error {
+	if e := s.Lookup(Deny, clientID, addr); e != nil {
+		return fmt.Errorf("%w: %s", ErrDenied, scope(e))
+	}
+	if allowlistOnly && s.Lookup(Allow, clientID, addr) == nil {
+		return ErrNotAllowed
+	}
+	return nil
+}
+
+func scope(e *Entry) string {
+	if e.ClientID == "" {
+		return "global deny list"
+	}
+	return "deny list of client " + e.ClientID
+}
+
+// update applies change to a copy of the entries without the expired ones and keeps
+// the copy once it is saved, so a failed write leaves the lists unchanged. It must be
+// called with the lock held.
+func (s *Store) update(change func(entries map[string]*Entry)) error {
+	now := time.Now()
+	entries := make(map[string]*Entry, len(s.entries)+1)
+	for k, e := range s.entries {
+		if !e.Expired(now) {
+			entries[k] = e
+		}
+	}
+	change(entries)
+
+	if err := s.save(entries); err != nil {
+		return err
+	}
+	s.entries = entries
+	return nil
+}
+
+// save writes the entries to the file of the store.
+func (s *Store) save(entries map[string]*Entry) error {
+	if s.path == "" {
+		return nil
+	}
+
+	var file struct {
+		Entries []*Entry `json:"entries"`
+	}
+	file.Entries = make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		file.Entries = append(file.Entries, e)
+	}
+	sort.Slice(file.Entries, func(i, j int) bool {
+		return file.Entries[i].CreatedAt.Before(file.Entries[j].CreatedAt)
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	// write a temporary file first, so a crash never leaves truncated lists behind
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,69 @@
+package lists
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const wallet = "0:960ab627408d5472d9d125b667cbe00ce17eeaa44e9dc6a86e93cdfef2c480d5"
+
+func TestStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lists.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Add(Entry{Kind: Deny, Address: wallet, Reason: "fraud"}); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if _, err := s.Add(Entry{Kind: Allow, Address: wallet, ClientID: "app", ExpiresAt: &past}); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.List(Deny, ""); len(got) != 1 || got[0].Reason != "fraud" {
+		t.Fatalf("List(deny) = %+v, want the fraud entry", got)
+	}
+	if got := reopened.List(Allow, ""); len(got) != 0 {
+		t.Fatalf("List(allow) = %+v, want no expired entries", got)
+	}
+	if err := reopened.Check("app", wallet, false); !errors.Is(err, ErrDenied) {
+		t.Fatalf("Check() error = %v, want %v", err, ErrDenied)
+	}
+}
+
+func TestStoreKeepsEntriesOnSaveError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lists.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Add(Entry{Kind: Deny, Address: wallet}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a directory in place of the temporary file makes every write fail
+	if err := os.Mkdir(path+".tmp", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Remove(Deny, "", wallet); err == nil {
+		t.Fatal("Remove() succeeded without saving the lists")
+	}
+	if s.Lookup(Deny, "", wallet) == nil {
+		t.Fatal("Remove() dropped the entry although saving failed")
+	}
+	if _, err := s.Add(Entry{Kind: Allow, Address: wallet}); err == nil {
+		t.Fatal("Add() succeeded without saving the lists")
+	}
+	if s.Lookup(Allow, "", wallet) != nil {
+		t.Fatal("Add() kept the entry although saving failed")
+	}
+}
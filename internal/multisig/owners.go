@@ -0,0 +1,195 @@
+package multisig
+
+import (
+	"TON/internal/chain"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// ErrNotMultisig is returned for contracts that expose neither multisig interface.
+var ErrNotMultisig = errors.New("address is not a multisig contract")
+
+// Kind names the multisig contract interface the owners were read through.
+type Kind string
+
+const (
+	// KindV1 is the multisig wallet with a set of owner keys, read with get_public_keys
+	// and get_n_k.
+	KindV1 Kind = "v1"
+	// KindV2 is multisig-contract-v2 whose signers are wallets, read with get_multisig_data.
+	KindV2 Kind = "v2"
+)
+
+// Owners is the signer set and threshold of a multisig contract.
+type Owners struct {
+	Kind      Kind
+	Threshold int
+	Signers   []Signer
+}
+
+// Signer is an owner of a multisig contract. Signer wallets of v2 contracts that are no
+// standard wallets have no public key and cannot sign a login.
+type Signer struct {
+	Index     int
+	PublicKey ed25519.PublicKey
+	// Address of the signer wallet, set for v2 contracts.
+	Address string
+}
+
+// Signer returns the signer controlled by the key, nil when the key is no owner.
+func (o *Owners) Signer(pub ed25519.PublicKey) *Signer {
+	for i := range o.Signers {
+		if s := &o.Signers[i]; len(s.PublicKey) > 0 && bytes.Equal(s.PublicKey, pub) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Approved returns the approvals of keys that still control a signer of the owners,
+// for owners read again before a token is issued.
+func (o *Owners) Approved(approvals []Approval) []Approval {
+	var approved []Approval
+	for _, a := range approvals {
+		s := o.Signer(a.PublicKey)
+		if s != nil && s.Index == a.Index && (s.Address == "" || s.Address == a.Address) {
+			approved = append(approved, a)
+		}
+	}
+	return approved
+}
+
+// ReadOwners runs the get-methods of the multisig contract at addr to read its signers
+// and threshold, trying multisig-contract-v2 first.
+func ReadOwners(ctx context.Context, p chain.ChainProvider, addr string) (*Owners, error) {
+	owners, err := readV2(ctx, p, addr)
+	if !errors.Is(err, chain.ErrGetMethodFailed) && !errors.Is(err, chain.ErrNotSupported) {
+		return owners, err
+	}
+	owners, err = readV1(ctx, p, addr)
+	if errors.Is(err, chain.ErrGetMethodFailed) || errors.Is(err, chain.ErrNotSupported) {
+		return nil, fmt.Errorf("%w: %w", ErrNotMultisig, err)
+	}
+	return owners, err
+}
+
+// readV2 reads get_multisig_data: next order seqno, threshold, signers and proposers,
+// the signers as a dictionary of 8-bit indexes to wallet addresses.
+func readV2(ctx context.Context, p chain.ChainProvider, addr string) (*Owners, error) {
+	res, err := p.RunGetMethod(ctx, addr, "get_multisig_data")
+	if err != nil {
+		return nil, err
+	}
+	if len(res) < 3 {
+		return nil, fmt.Errorf("%w: get_multisig_data returned %d entries", chain.ErrGetMethodFailed, len(res))
+	}
+	threshold, err := threshold(res[1])
+	if err != nil {
+		return nil, err
+	}
+	entries, err := dictEntries(res[2])
+	if err != nil {
+		return nil, fmt.Errorf("signers: %w", err)
+	}
+
+	owners := &Owners{Kind: KindV2, Threshold: threshold}
+	for _, kv := range entries {
+		index, err := kv.Key.LoadUInt(8)
+		if err != nil {
+			return nil, fmt.Errorf("signers: %w", err)
+		}
+		a, err := kv.Value.LoadAddr()
+		if err != nil {
+			return nil, fmt.Errorf("signer %d: %w", index, err)
+		}
+		signer := Signer{Index: int(index), Address: a.StringRaw()}
+
+		acc, err := p.GetAccount(ctx, signer.Address)
+		if err != nil && !errors.Is(err, chain.ErrNotFound) {
+			return nil, fmt.Errorf("signer %d: %w", index, err)
+		}
+		if acc != nil && acc.Status == chain.StatusActive {
+			if key, _, err := chain.WalletPublicKey(ctx, p, acc); err == nil {
+				signer.PublicKey = key
+			}
+		}
+		owners.Signers = append(owners.Signers, signer)
+	}
+	return owners.sorted(), nil
+}
+
+// readV1 reads get_n_k, the number of owners and the threshold, and get_public_keys,
+// a dictionary of 8-bit indexes to 256-bit keys followed by a flood counter.
+func readV1(ctx context.Context, p chain.ChainProvider, addr string) (*Owners, error) {
+	nk, err := p.RunGetMethod(ctx, addr, "get_n_k")
+	if err != nil {
+		return nil, err
+	}
+	if len(nk) < 2 {
+		return nil, fmt.Errorf("%w: get_n_k returned %d entries", chain.ErrGetMethodFailed, len(nk))
+	}
+	threshold, err := threshold(nk[1])
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.RunGetMethod(ctx, addr, "get_public_keys")
+	if err != nil {
+		return nil, err
+	}
+	if len(res) < 1 {
+		return nil, fmt.Errorf("%w: get_public_keys returned an empty stack", chain.ErrGetMethodFailed)
+	}
+	entries, err := dictEntries(res[0])
+	if err != nil {
+		return nil, fmt.Errorf("public keys: %w", err)
+	}
+
+	owners := &Owners{Kind: KindV1, Threshold: threshold}
+	for _, kv := range entries {
+		index, err := kv.Key.LoadUInt(8)
+		if err != nil {
+			return nil, fmt.Errorf("public keys: %w", err)
+		}
+		key, err := kv.Value.LoadSlice(256)
+		if err != nil {
+			return nil, fmt.Errorf("public key %d: %w", index, err)
+		}
+		owners.Signers = append(owners.Signers, Signer{Index: int(index), PublicKey: key})
+	}
+	return owners.sorted(), nil
+}
+
+func threshold(e chain.StackEntry) (int, error) {
+	n, err := e.Int()
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsInt64() || n.Int64() < 1 || n.Int64() > 255 {
+		return 0, fmt.Errorf("%w: invalid threshold %s", chain.ErrGetMethodFailed, n)
+	}
+	return int(n.Int64()), nil
+}
+
+// dictEntries loads a dictionary with 8-bit keys from a cell entry, empty for null.
+func dictEntries(e chain.StackEntry) ([]cell.DictKV, error) {
+	if e.Type == chain.StackNull {
+		return nil, nil
+	}
+	c, err := e.ToCell()
+	if err != nil {
+		return nil, err
+	}
+	return c.AsDict(8).LoadAll()
+}
+
+func (o *Owners) sorted() *Owners {
+	sort.Slice(o.Signers, func(i, j int) bool { return o.Signers[i].Index < o.Signers[j].Index })
+	return o
+}
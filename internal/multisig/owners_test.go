@@ -0,0 +1,185 @@
+package multisig
+
+import (
+	"TON/internal/chain"
+	"TON/pkg/tonwallet"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+const (
+	contract = "0:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	walletA  = "0:6463000000000000000000000000000000000000000000000000000000000000"
+	walletB  = "0:1212000000000000000000000000000000000000000000000000000000000000"
+)
+
+// key returns the public key derived from a seed filled with b.
+func key(b byte) ed25519.PublicKey {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = b
+	}
+	return ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+}
+
+func num(n int64) chain.StackEntry {
+	return chain.StackEntry{Type: chain.StackNum, Num: big.NewInt(n)}
+}
+
+// dict builds a cell entry of a dictionary with 8-bit keys.
+func dict(t *testing.T, values map[int64]*cell.Cell) chain.StackEntry {
+	t.Helper()
+	d := cell.NewDict(8)
+	for k, v := range values {
+		if err := d.SetIntKey(big.NewInt(k), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return chain.StackEntry{Type: chain.StackCell, Cell: d.AsCell().ToBOC()}
+}
+
+// setV1 defines the get-methods of a multisig wallet v1 with the keys and threshold.
+func setV1(t *testing.T, p *chain.FixtureProvider, threshold int64, keys map[int64]ed25519.PublicKey) {
+	t.Helper()
+	values := make(map[int64]*cell.Cell)
+	for i, k := range keys {
+		values[i] = cell.BeginCell().MustStoreSlice(k, 256).EndCell()
+	}
+	p.SetGetMethod(contract, "get_n_k", []chain.StackEntry{num(int64(len(keys))), num(threshold)})
+	p.SetGetMethod(contract, "get_public_keys", []chain.StackEntry{dict(t, values), num(0)})
+}
+
+// setV2 defines get_multisig_data of a multisig-contract-v2 with the signer wallets.
+func setV2(t *testing.T, p *chain.FixtureProvider, threshold int64, wallets map[int64]string) {
+	t.Helper()
+	values := make(map[int64]*cell.Cell)
+	for i, w := range wallets {
+		values[i] = cell.BeginCell().MustStoreAddr(address.MustParseRawAddr(w)).EndCell()
+	}
+	p.SetGetMethod(contract, "get_multisig_data", []chain.StackEntry{
+		num(1), num(threshold), dict(t, values), {Type: chain.StackNull},
+	})
+}
+
+// setWallet deploys a wallet answering get_public_key with the key.
+func setWallet(p *chain.FixtureProvider, addr string, pub ed25519.PublicKey) {
+	p.SetAccount(chain.Account{Address: addr, Status: chain.StatusActive})
+	p.SetGetMethod(addr, "get_public_key", []chain.StackEntry{{Type: chain.StackNum, Num: new(big.Int).SetBytes(pub)}})
+}
+
+func TestReadOwnersV1(t *testing.T) {
+	p := chain.NewFixtureProvider(tonwallet.Options{})
+	setV1(t, p, 2, map[int64]ed25519.PublicKey{2: key(3), 0: key(1), 1: key(2)})
+
+	owners, err := ReadOwners(context.Background(), p, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owners.Kind != KindV1 || owners.Threshold != 2 || len(owners.Signers) != 3 {
+		t.Fatalf("ReadOwners() = %s %d of %d, want v1 2 of 3", owners.Kind, owners.Threshold, len(owners.Signers))
+	}
+	for i, s := range owners.Signers {
+		if s.Index != i || !s.PublicKey.Equal(key(byte(i+1))) || s.Address != "" {
+			t.Fatalf("signer %d = %d %x %s", i, s.Index, s.PublicKey, s.Address)
+		}
+	}
+}
+
+func TestReadOwnersV2(t *testing.T) {
+	p := chain.NewFixtureProvider(tonwallet.Options{})
+	// v1 get-methods are ignored once get_multisig_data answers
+	setV1(t, p, 1, map[int64]ed25519.PublicKey{0: key(9)})
+	setV2(t, p, 2, map[int64]string{0: walletA, 1: walletB})
+	setWallet(p, walletA, key(1))
+
+	owners, err := ReadOwners(context.Background(), p, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owners.Kind != KindV2 || owners.Threshold != 2 || len(owners.Signers) != 2 {
+		t.Fatalf("ReadOwners() = %s %d of %d, want v2 2 of 2", owners.Kind, owners.Threshold, len(owners.Signers))
+	}
+	if s := owners.Signers[0]; s.Address != walletA || !s.PublicKey.Equal(key(1)) {
+		t.Fatalf("signer 0 = %s %x, want %s with its key", s.Address, s.PublicKey, walletA)
+	}
+	// the undeployed signer wallet has no key to sign with
+	if s := owners.Signers[1]; s.Address != walletB || s.PublicKey != nil {
+		t.Fatalf("signer 1 = %s %x, want %s without key", s.Address, s.PublicKey, walletB)
+	}
+}
+
+func TestReadOwnersErrors(t *testing.T) {
+	p := chain.NewFixtureProvider(tonwallet.Options{})
+	if _, err := ReadOwners(context.Background(), p, contract); !errors.Is(err, ErrNotMultisig) {
+		t.Fatalf("ReadOwners() of a plain contract error = %v, want %v", err, ErrNotMultisig)
+	}
+
+	setV1(t, p, 0, map[int64]ed25519.PublicKey{0: key(1)})
+	if _, err := ReadOwners(context.Background(), p, contract); !errors.Is(err, chain.ErrGetMethodFailed) {
+		t.Fatalf("ReadOwners() with threshold 0 error = %v, want %v", err, chain.ErrGetMethodFailed)
+	}
+}
+
+func TestApproved(t *testing.T) {
+	owners := &Owners{Kind: KindV2, Threshold: 2, Signers: []Signer{
+		{Index: 0, PublicKey: key(1), Address: walletA},
+		{Index: 1, PublicKey: key(2), Address: walletB},
+	}}
+	approvals := []Approval{
+		{Index: 0, PublicKey: key(1), Address: walletA},
+		{Index: 1, PublicKey: key(2), Address: walletB},
+		{Index: 1, PublicKey: key(3), Address: walletB}, // no owner key
+		{Index: 0, PublicKey: key(2), Address: walletB}, // key of another index
+		{Index: 1, PublicKey: key(2), Address: walletA}, // key moved to another wallet
+	}
+	if got := owners.Approved(approvals); len(got) != 2 || got[0].Index != 0 || got[1].Index != 1 {
+		t.Fatalf("Approved() = %+v, want the first two approvals", got)
+	}
+}
+
+// TestApprovedOwnersReread reads the owners again as a login does before issuing the
+// token: a signer removed since the session started no longer counts.
+func TestApprovedOwnersReread(t *testing.T) {
+	p := chain.NewFixtureProvider(tonwallet.Options{})
+	setV1(t, p, 2, map[int64]ed25519.PublicKey{0: key(1), 1: key(2), 2: key(3)})
+	ctx := context.Background()
+
+	owners, err := ReadOwners(ctx, p, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCollector("TON-OAUTH", time.Minute, 0)
+	s, err := c.Start("", "", chain.Mainnet, contract, owners)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []ed25519.PublicKey{key(1), key(2)} {
+		if s, err = c.Approve(s.ID, k, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reread, err := ReadOwners(ctx, p, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(reread.Approved(s.Approvals)); n != 2 {
+		t.Fatalf("Approved() with unchanged owners = %d, want 2", n)
+	}
+
+	setV1(t, p, 2, map[int64]ed25519.PublicKey{0: key(1), 2: key(3)})
+	reread, err = ReadOwners(ctx, p, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(reread.Approved(s.Approvals)); n != 1 {
+		t.Fatalf("Approved() after removing a signer = %d, want 1", n)
+	}
+}
@@ -0,0 +1,219 @@
+package multisig
+
+import (
+	"TON/internal/chain"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("multisig session not found")
+	ErrExpired  = errors.New("multisig session expired")
+	ErrUsed     = errors.New("multisig session already used")
+	// ErrNotOwner is returned for signatures of keys that are no owner of the contract.
+	ErrNotOwner = errors.New("key is not an owner of the multisig contract")
+	// ErrThreshold is returned when fewer owners than the threshold signed.
+	ErrThreshold = errors.New("threshold of owner signatures not reached")
+	// ErrTooMany is returned when the limit of open sessions is reached.
+	ErrTooMany = errors.New("too many open multisig sessions")
+)
+
+type Status string
+
+const (
+	// Collecting sessions wait for more signatures.
+	Collecting Status = "collecting"
+	// Complete sessions reached the threshold and can be exchanged for a token.
+	Complete Status = "complete"
+	Expired  Status = "expired"
+	Used     Status = "used"
+)
+
+// Session collects the signatures of the owners of a multisig contract over its message.
+type Session struct {
+	// ID is the challenge the owners sign, shared with them by whoever started the login.
+	ID       string
+	ClientID string
+	Scope    string
+	Network  chain.Network
+	Address  string
+	// Message the owners sign with their keys, or whose ID they sign as ton_proof payload.
+	Message   string
+	Owners    Owners
+	Approvals []Approval
+	Status    Status
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Approval is the signature of an owner.
+type Approval struct {
+	Index     int
+	PublicKey ed25519.PublicKey
+	// Address of the signer wallet of v2 contracts.
+	Address  string
+	SignedAt time.Time
+}
+
+// Collector keeps the open multisig sessions in memory.
+type Collector struct {
+	issuer     string
+	ttl        time.Duration
+	maxPending int
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+func NewCollector(issuer string, ttl time.Duration, maxPending int) *Collector {
+	return &Collector{
+		issuer:     issuer,
+		ttl:        ttl,
+		maxPending: maxPending,
+		sessions:   make(map[string]*Session),
+	}
+}
+
+// Start opens a session for the multisig contract at addr with the owners read from it.
+func (c *Collector) Start(clientID, scope string, network chain.Network, addr string, owners *Owners) (*Session, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	id := base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+	s := &Session{
+		ID:        id,
+		ClientID:  clientID,
+		Scope:     scope,
+		Network:   network,
+		Address:   addr,
+		Message:   Message(c.issuer, addr, id),
+		Owners:    *owners,
+		Status:    Collecting,
+		CreatedAt: now,
+		ExpiresAt: now.Add(c.ttl),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxPending > 0 && len(c.sessions) >= c.maxPending {
+		return nil, ErrTooMany
+	}
+	c.sessions[id] = s
+	return s.copy(), nil
+}
+
+// Message is the text the owners sign to log in as the multisig contract.
+func Message(issuer, addr, id string) string {
+	return fmt.Sprintf("%s:multisig:%s:%s", issuer, addr, id)
+}
+
+func (c *Collector) Get(id string) (*Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s.copy(), nil
+}
+
+// Approve records the signature of the owner with the key, whose signature the caller
+// verified. A signature of an owner that already signed replaces the earlier one. With
+// an address the key must control that signer wallet of a v2 contract.
+func (c *Collector) Approve(id string, pub ed25519.PublicKey, addr string) (*Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	switch {
+	case s.Status == Used:
+		return nil, ErrUsed
+	case s.Status == Expired || time.Now().After(s.ExpiresAt):
+		return nil, ErrExpired
+	}
+
+	signer := s.Owners.Signer(pub)
+	if signer == nil || (addr != "" && signer.Address != "" && signer.Address != addr) {
+		return nil, ErrNotOwner
+	}
+
+	approval := Approval{Index: signer.Index, PublicKey: pub, Address: signer.Address, SignedAt: time.Now()}
+	replaced := false
+	for i := range s.Approvals {
+		if s.Approvals[i].Index == signer.Index {
+			s.Approvals[i], replaced = approval, true
+		}
+	}
+	if !replaced {
+		s.Approvals = append(s.Approvals, approval)
+	}
+	if len(s.Approvals) >= s.Owners.Threshold {
+		s.Status = Complete
+	}
+	return s.copy(), nil
+}
+
+// Consume marks a complete session as used and returns it. A session is exchanged for a
+// token once.
+func (c *Collector) Consume(id string) (*Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	switch {
+	case s.Status == Used:
+		return nil, ErrUsed
+	case s.Status == Expired || time.Now().After(s.ExpiresAt):
+		return nil, ErrExpired
+	case s.Status != Complete:
+		return nil, ErrThreshold
+	}
+	s.Status = Used
+	return s.copy(), nil
+}
+
+// Run expires sessions every interval and forgets them an expiry later, until stop is closed.
+func (c *Collector) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			c.mu.Lock()
+			for id, s := range c.sessions {
+				if now.Before(s.ExpiresAt) {
+					continue
+				}
+				if s.Status == Collecting || s.Status == Complete {
+					s.Status = Expired
+				}
+				if now.Sub(s.ExpiresAt) > c.ttl {
+					delete(c.sessions, id)
+				}
+			}
+			c.mu.Unlock()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (s *Session) copy() *Session {
+	c := *s
+	c.Owners.Signers = append([]Signer(nil), s.Owners.Signers...)
+	c.Approvals = append([]Approval(nil), s.Approvals...)
+	return &c
+}
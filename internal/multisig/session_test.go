@@ -0,0 +1,109 @@
+package multisig
+
+import (
+	"TON/internal/chain"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestApprove(t *testing.T) {
+	owners := &Owners{Kind: KindV1, Threshold: 2, Signers: []Signer{
+		{Index: 0, PublicKey: key(1)},
+		{Index: 1, PublicKey: key(2)},
+		{Index: 2, PublicKey: key(3)},
+	}}
+	c := NewCollector("TON-OAUTH", time.Minute, 0)
+	s, err := c.Start("my-dapp", "", chain.Mainnet, contract, owners)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "TON-OAUTH:multisig:" + contract + ":" + s.ID; s.Message != want {
+		t.Fatalf("Message = %s, want %s", s.Message, want)
+	}
+	if _, err := c.Consume(s.ID); !errors.Is(err, ErrThreshold) {
+		t.Fatalf("Consume() below the threshold error = %v, want %v", err, ErrThreshold)
+	}
+
+	tests := []struct {
+		name      string
+		key       ed25519.PublicKey
+		wantErr   error
+		approvals int
+		status    Status
+	}{
+		{"first owner", key(1), nil, 1, Collecting},
+		{"same owner again", key(1), nil, 1, Collecting},
+		{"no owner", key(4), ErrNotOwner, 1, Collecting},
+		{"second owner", key(2), nil, 2, Complete},
+		{"third owner", key(3), nil, 3, Complete},
+	}
+	for _, tt := range tests {
+		got, err := c.Approve(s.ID, tt.key, "")
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: Approve() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if got, _ = c.Get(s.ID); len(got.Approvals) != tt.approvals || got.Status != tt.status {
+			t.Fatalf("%s: session has %d approvals, %s, want %d, %s", tt.name, len(got.Approvals), got.Status, tt.approvals, tt.status)
+		}
+	}
+
+	if _, err := c.Consume(s.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Consume(s.ID); !errors.Is(err, ErrUsed) {
+		t.Fatalf("Consume() twice error = %v, want %v", err, ErrUsed)
+	}
+	if _, err := c.Approve(s.ID, key(1), ""); !errors.Is(err, ErrUsed) {
+		t.Fatalf("Approve() of a used session error = %v, want %v", err, ErrUsed)
+	}
+	if _, err := c.Approve("unknown", key(1), ""); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Approve() of an unknown session error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestApproveSignerWallet(t *testing.T) {
+	owners := &Owners{Kind: KindV2, Threshold: 1, Signers: []Signer{
+		{Index: 0, PublicKey: key(1), Address: walletA},
+		{Index: 1, Address: walletB},
+	}}
+	c := NewCollector("TON-OAUTH", time.Minute, 0)
+	s, err := c.Start("", "", chain.Mainnet, contract, owners)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the key must control the signer wallet it claims
+	if _, err := c.Approve(s.ID, key(1), walletB); !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("Approve() for another wallet error = %v, want %v", err, ErrNotOwner)
+	}
+	got, err := c.Approve(s.ID, key(1), walletA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Approvals) != 1 || got.Approvals[0].Address != walletA || got.Status != Complete {
+		t.Fatalf("Approve() = %+v", got)
+	}
+}
+
+func TestCollectorLimits(t *testing.T) {
+	owners := &Owners{Kind: KindV1, Threshold: 1, Signers: []Signer{{Index: 0, PublicKey: key(1)}}}
+
+	c := NewCollector("TON-OAUTH", time.Minute, 1)
+	if _, err := c.Start("", "", chain.Mainnet, contract, owners); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Start("", "", chain.Mainnet, contract, owners); !errors.Is(err, ErrTooMany) {
+		t.Fatalf("Start() over the limit error = %v, want %v", err, ErrTooMany)
+	}
+
+	c = NewCollector("TON-OAUTH", -time.Second, 0)
+	s, err := c.Start("", "", chain.Mainnet, contract, owners)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Approve(s.ID, key(1), ""); !errors.Is(err, ErrExpired) {
+		t.Fatalf("Approve() of an expired session error = %v, want %v", err, ErrExpired)
+	}
+}
@@ -0,0 +1,118 @@
+package policy
+
+import (
+	"TON/internal/chain"
+	"math"
+	"math/big"
+)
+
+// Facts describe a wallet and its login request to policy expressions.
+type Facts struct {
+	// Address of the wallet in the raw "wc:hex" form.
+	Address   string `json:"address"`
+	Version   string `json:"version,omitempty"`
+	Status    string `json:"status"`
+	PublicKey string `json:"publicKey,omitempty"`
+	// Balance in nanotons.
+	Balance int64 `json:"balance"`
+	// Name is the verified TON DNS name of the wallet.
+	Name     string `json:"name,omitempty"`
+	Network  string `json:"network"`
+	ClientID string `json:"clientId"`
+	IP       string `json:"ip,omitempty"`
+	// Scopes requested by the client.
+	Scopes []string `json:"scopes"`
+	// Jettons by master address, for the masters listed in the policy.
+	Jettons map[string]chain.JettonBalance `json:"jettons"`
+	// NFTs owned in the collections listed in the policy.
+	NFTs []chain.NFTItem `json:"nfts"`
+	// Activity of the wallet, read when the policy asks for it.
+	Activity *Activity `json:"activity,omitempty"`
+}
+
+// Activity describes the transaction history of a wallet.
+type Activity struct {
+	AgeDays int `json:"ageDays"`
+	TxCount int `json:"txCount"`
+	// FirstTxAt and LastTxAt are unix times, zero without transactions.
+	FirstTxAt int64 `json:"firstTxAt"`
+	LastTxAt  int64 `json:"lastTxAt"`
+	// Truncated is set when the history is longer than the scan limit: AgeDays,
+	// TxCount and FirstTxAt are lower bounds then.
+	Truncated bool `json:"truncated"`
+}
+
+// vars maps the facts to the variables of policy expressions.
+func (f *Facts) vars() map[string]interface{} {
+	jettons := make(map[string]interface{}, len(f.Jettons))
+	for master, j := range f.Jettons {
+		amount := j.Amount
+		if amount == nil {
+			amount = new(big.Int)
+		}
+		jettons[master] = map[string]interface{}{
+			"amount":        units(amount),
+			"amount_string": amount.String(),
+			"symbol":        j.Symbol,
+			"decimals":      int64(j.Decimals),
+		}
+	}
+
+	nfts := make([]interface{}, 0, len(f.NFTs))
+	for _, item := range f.NFTs {
+		attributes := make(map[string]interface{}, len(item.Attributes))
+		for k, v := range item.Attributes {
+			attributes[k] = v
+		}
+		nfts = append(nfts, map[string]interface{}{
+			"address":    item.Address,
+			"collection": item.Collection,
+			"name":       item.Name,
+			"attributes": attributes,
+		})
+	}
+
+	scopes := f.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	wallet := map[string]interface{}{
+		"address":    f.Address,
+		"version":    f.Version,
+		"status":     f.Status,
+		"public_key": f.PublicKey,
+		"balance":    f.Balance,
+		"name":       f.Name,
+	}
+	if a := f.Activity; a != nil {
+		wallet["age_days"] = int64(a.AgeDays)
+		wallet["tx_count"] = int64(a.TxCount)
+		wallet["first_tx_at"] = a.FirstTxAt
+		wallet["last_tx_at"] = a.LastTxAt
+		wallet["truncated"] = a.Truncated
+	}
+
+	return map[string]interface{}{
+		"wallet":    wallet,
+		"network":   f.Network,
+		"client_id": f.ClientID,
+		"ip":        f.IP,
+		"scopes":    scopes,
+		"jettons":   jettons,
+		"nfts":      nfts,
+	}
+}
+
+// units converts a jetton amount to a CEL uint without the rounding of a double. Amounts
+// above the range saturate, which keeps every comparison with a uint literal right but
+// equality with the largest one.
+func units(amount *big.Int) uint64 {
+	switch {
+	case amount.Sign() < 0:
+		return 0
+	case !amount.IsUint64():
+		return math.MaxUint64
+	}
+	return amount.Uint64()
+}
@@ -0,0 +1,283 @@
+package policy
+
+import (
+	"TON/pkg/address"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Policy decides with CEL expressions over wallet facts whether a wallet may log in to
+// a client and which roles, scopes and claims it gets.
+type Policy struct {
+	// Client is the id of the client the policy applies to.
+	Client string `json:"client"`
+	// Allow must evaluate to true for the login to be allowed; empty allows every wallet
+	// not denied by a rule.
+	Allow string `json:"allow,omitempty"`
+	// Jettons are the masters whose balances are read into the jettons fact.
+	Jettons []string `json:"jettons,omitempty"`
+	// Collections are the NFT collections whose items are read into the nfts fact.
+	Collections []string `json:"collections,omitempty"`
+	// Activity reads the transaction history of the wallet into the wallet facts.
+	Activity bool `json:"activity,omitempty"`
+	// Rules are evaluated in order after Allow.
+	Rules []Rule `json:"rules,omitempty"`
+
+	allow cel.Program
+}
+
+// Rule applies when its When expression is true: a deny rule rejects the login,
+// any other rule grants its roles, scopes and claims.
+type Rule struct {
+	Name string `json:"name"`
+	// When is a boolean expression; empty always applies.
+	When string `json:"when,omitempty"`
+	Deny bool   `json:"deny,omitempty"`
+	// Roles and Scopes granted when the rule applies.
+	Roles  []string `json:"roles,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+	// Claims maps claim names to expressions whose results become token claims.
+	Claims map[string]string `json:"claims,omitempty"`
+
+	when   cel.Program
+	claims map[string]cel.Program
+}
+
+// Decision is the outcome of a policy for a wallet.
+type Decision struct {
+	Allowed bool `json:"allowed"`
+	// Reason names the deny rule, or "allow" when the Allow expression was false.
+	Reason string `json:"reason,omitempty"`
+	// Matched lists the names of the rules that applied.
+	Matched []string               `json:"matched,omitempty"`
+	Roles   []string               `json:"roles,omitempty"`
+	Scopes  []string               `json:"scopes,omitempty"`
+	Claims  map[string]interface{} `json:"claims,omitempty"`
+}
+
+// reservedClaims are set by the service itself; rules may not emit them, which would
+// let a policy rewrite the subject or lifetime of tokens, forge the verified DNS name or
+// erase the evidence of how the wallet logged in and what it was checked against.
+var reservedClaims = []string{
+	"sub", "iss", "exp", "iat", "nbf", "jti", "aud", "network", "wallet_state", "scope", "roles",
+	"preferred_username", "name",
+	"amr", "act", "signers", "multisig_threshold", "tx_hash", "screening_flags",
+	"ton_balance", "ton_balance_at", "jettons", "nft_collections",
+	"wallet_tx_count", "wallet_first_tx_at", "wallet_last_tx_at", "wallet_history_truncated",
+	"subscription_plans", "subscription_expires_at",
+}
+
+var env *cel.Env
+
+func init() {
+	var err error
+	env, err = cel.NewEnv(
+		cel.Variable("wallet", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("network", cel.StringType),
+		cel.Variable("client_id", cel.StringType),
+		cel.Variable("ip", cel.StringType),
+		cel.Variable("scopes", cel.ListType(cel.StringType)),
+		cel.Variable("jettons", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("nfts", cel.ListType(cel.DynType)),
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Parse reads a policy from JSON and compiles its expressions.
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	if err := p.compile(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (p *Policy) compile() error {
+	var err error
+	if p.Allow != "" {
+		if p.allow, err = compile(p.Allow, cel.BoolType); err != nil {
+			return fmt.Errorf("allow: %w", err)
+		}
+	}
+	for i, master := range p.Jettons {
+		if p.Jettons[i], err = address.Normalize(master); err != nil {
+			return fmt.Errorf("jettons[%d]: %w", i, err)
+		}
+	}
+	for i, collection := range p.Collections {
+		if p.Collections[i], err = address.Normalize(collection); err != nil {
+			return fmt.Errorf("collections[%d]: %w", i, err)
+		}
+	}
+
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rules[%d]", i)
+		}
+		if r.When != "" {
+			if r.when, err = compile(r.When, cel.BoolType); err != nil {
+				return fmt.Errorf("%s: when: %w", r.Name, err)
+			}
+		}
+		r.claims = make(map[string]cel.Program, len(r.Claims))
+		for name, expr := range r.Claims {
+			if slices.Contains(reservedClaims, name) {
+				return fmt.Errorf("%s: claims.%s: claim name is reserved", r.Name, name)
+			}
+			if r.claims[name], err = compile(expr, nil); err != nil {
+				return fmt.Errorf("%s: claims.%s: %w", r.Name, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// compile checks the expression and, when want is set, its result type.
+func compile(expr string, want *cel.Type) (cel.Program, error) {
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if want != nil && !ast.OutputType().IsAssignableType(want) {
+		return nil, fmt.Errorf("expression must return %s, got %s", want, ast.OutputType())
+	}
+	return env.Program(ast)
+}
+
+// Evaluate runs the policy on the facts of a wallet.
+func (p *Policy) Evaluate(f *Facts) (*Decision, error) {
+	vars := f.vars()
+	d := &Decision{Allowed: true, Claims: make(map[string]interface{})}
+
+	if p.allow != nil {
+		ok, err := evalBool(p.allow, vars)
+		if err != nil {
+			return nil, fmt.Errorf("allow: %w", err)
+		}
+		if !ok {
+			return &Decision{Reason: "allow"}, nil
+		}
+	}
+
+	for _, r := range p.Rules {
+		if r.when != nil {
+			ok, err := evalBool(r.when, vars)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", r.Name, err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		d.Matched = append(d.Matched, r.Name)
+		if r.Deny {
+			return &Decision{Reason: r.Name, Matched: d.Matched}, nil
+		}
+
+		d.Roles = appendUnique(d.Roles, r.Roles...)
+		d.Scopes = appendUnique(d.Scopes, r.Scopes...)
+		for name, prg := range r.claims {
+			v, err := eval(prg, vars)
+			if err != nil {
+				return nil, fmt.Errorf("%s: claims.%s: %w", r.Name, name, err)
+			}
+			d.Claims[name] = v
+		}
+	}
+	return d, nil
+}
+
+func evalBool(prg cel.Program, vars map[string]interface{}) (bool, error) {
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	ok, isBool := out.(types.Bool)
+	if !isBool {
+		return false, fmt.Errorf("expression returned %s instead of bool", out.Type())
+	}
+	return bool(ok), nil
+}
+
+// eval runs the expression and converts its result to JSON compatible Go values.
+func eval(prg cel.Program, vars map[string]interface{}) (interface{}, error) {
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return native(out)
+}
+
+func native(v ref.Val) (interface{}, error) {
+	pb, err := v.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return nil, err
+	}
+	return pb.(*structpb.Value).AsInterface(), nil
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		if !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// Set holds the policies of the clients.
+type Set struct {
+	policies map[string]*Policy
+}
+
+// Load reads every *.json file in dir as a policy. A missing directory yields an empty set.
+func Load(dir string) (*Set, error) {
+	s := &Set{policies: make(map[string]*Policy)}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		p, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %w", filepath.Base(file), err)
+		}
+		if strings.TrimSpace(p.Client) == "" {
+			return nil, fmt.Errorf("policy %s: client is required", filepath.Base(file))
+		}
+		if _, ok := s.policies[p.Client]; ok {
+			return nil, fmt.Errorf("policy %s: duplicate policy for client %s", filepath.Base(file), p.Client)
+		}
+		s.policies[p.Client] = p
+	}
+	return s, nil
+}
+
+// Get returns the policy of the client, nil when it has none.
+func (s *Set) Get(clientID string) *Policy {
+	if s == nil || clientID == "" {
+		return nil
+	}
+	return s.policies[clientID]
+}
@@ -0,0 +1,109 @@
+package policy
+
+import (
+	"TON/internal/chain"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestParseReservedClaims(t *testing.T) {
+	for _, name := range reservedClaims {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(`{"client":"app","rules":[{"name":"r","claims":{"` + name + `":"wallet.address"}}]}`))
+			if err == nil || !strings.Contains(err.Error(), "reserved") {
+				t.Fatalf("Parse() error = %v, want a reserved claim error", err)
+			}
+		})
+	}
+}
+
+func TestParseServiceClaims(t *testing.T) {
+	// claims of the DNS name, the login evidence and the screening outcome
+	for _, name := range []string{"preferred_username", "name", "amr", "act", "multisig_threshold", "tx_hash", "screening_flags"} {
+		_, err := Parse([]byte(`{"client":"app","rules":[{"name":"r","claims":{"` + name + `":"'forged'"}}]}`))
+		if err == nil {
+			t.Errorf("Parse() accepted a policy setting %s", name)
+		}
+	}
+}
+
+func TestEvaluateJettonAmounts(t *testing.T) {
+	const master = "0:b113a994b5024a16719f69139328eb759596c38a25f59028b146fecdc3621dfe"
+	p, err := Parse([]byte(`{
+		"client": "app",
+		"rules": [
+			{"name": "above", "when": "jettons['` + master + `'].amount > 9007199254740992u", "roles": ["above"]},
+			{"name": "exact", "when": "jettons['` + master + `'].amount_string == '9007199254740993'", "roles": ["exact"]}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	huge, _ := new(big.Int).SetString("100000000000000000000000", 10)
+	tests := []struct {
+		name   string
+		amount *big.Int
+		roles  string
+	}{
+		// 2^53 + 1 rounds to 2^53 as a double
+		{"above 2^53", big.NewInt(9007199254740993), "above,exact"},
+		{"at 2^53", big.NewInt(9007199254740992), ""},
+		{"above uint64", huge, "above"},
+		{"none", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facts := Facts{Jettons: map[string]chain.JettonBalance{master: {Master: master, Amount: tt.amount}}}
+			d, err := p.Evaluate(&facts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(d.Roles, ","); got != tt.roles {
+				t.Fatalf("roles = %s, want %s", got, tt.roles)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := Parse([]byte(`{
+		"client": "app",
+		"allow": "wallet.balance >= 1000",
+		"rules": [
+			{"name": "blocked", "when": "ip == '10.0.0.1'", "deny": true},
+			{"name": "named", "when": "wallet.name != ''", "roles": ["member"], "claims": {"dns": "wallet.name"}}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		facts   Facts
+		allowed bool
+		reason  string
+		roles   []string
+	}{
+		{"allowed with name", Facts{Balance: 1000, Name: "alice.ton"}, true, "", []string{"member"}},
+		{"allowed without name", Facts{Balance: 5000}, true, "", nil},
+		{"low balance", Facts{Balance: 999, Name: "alice.ton"}, false, "allow", nil},
+		{"denied ip", Facts{Balance: 1000, IP: "10.0.0.1"}, false, "blocked", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := p.Evaluate(&tt.facts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Allowed != tt.allowed || d.Reason != tt.reason || strings.Join(d.Roles, ",") != strings.Join(tt.roles, ",") {
+				t.Fatalf("Evaluate() = %+v", d)
+			}
+			if tt.allowed && tt.facts.Name != "" && d.Claims["dns"] != tt.facts.Name {
+				t.Fatalf("claims = %v", d.Claims)
+			}
+		})
+	}
+}
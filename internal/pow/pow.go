@@ -0,0 +1,299 @@
+package pow
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrRequired is returned when a request carries no solution.
+	ErrRequired = errors.New("proof of work required")
+	// ErrInvalid is returned for forged, expired, reused or wrong solutions.
+	ErrInvalid = errors.New("invalid proof of work")
+)
+
+// Algorithm names the hash a solution is checked with.
+const Algorithm = "sha256"
+
+// window is the period challenges and failures are counted in for the difficulty.
+const window = time.Minute
+
+// A challenge is a random seed, the expiry and the difficulty, followed by a MAC over
+// them and the IP of the caller.
+const (
+	seedSize    = 16
+	payloadSize = seedSize + 8 + 1
+	macSize     = 16
+)
+
+type Config struct {
+	// Secret signs the challenges. Instances sharing it accept each other's challenges;
+	// a random secret is used when empty.
+	Secret []byte
+	// Difficulty is the number of leading zero bits a solution hash needs at rest.
+	Difficulty    int
+	MaxDifficulty int
+	// LoadThreshold is the number of challenges per minute across all callers above
+	// which the difficulty grows by one bit with every doubling.
+	LoadThreshold int
+	// IPThreshold is the same for the challenges and failed verifications of one IP.
+	IPThreshold int
+}
+
+// Puzzle is a challenge a caller has to solve before verifying a signature: find a
+// nonce so that the SHA-256 hash of "challenge:nonce" starts with Difficulty zero bits.
+type Puzzle struct {
+	Challenge  string
+	Difficulty int
+	ExpiresAt  time.Time
+}
+
+type counter struct {
+	start      time.Time
+	challenges int
+	failures   int
+}
+
+// Use is a call a solution is presented with. A solution pays for each use once.
+type Use uint8
+
+const (
+	// UseVerify is the /oauth/verify call of a login.
+	UseVerify Use = 1 << iota
+	// UseExchange is the call exchanging the login for a token or credential.
+	UseExchange
+
+	spent = UseVerify | UseExchange
+)
+
+// solution is a solved challenge, the login it was solved for and the uses it paid for.
+type solution struct {
+	expiresAt time.Time
+	// subject is the login challenge the solution may be presented again for.
+	subject string
+	used    Use
+}
+
+// Guard issues puzzles and checks their solutions. Challenges are signed instead of
+// stored; only solved challenges are remembered until they expire.
+type Guard struct {
+	cfg Config
+
+	mu     sync.Mutex
+	load   counter
+	ips    map[string]*counter
+	solved map[string]*solution
+}
+
+func NewGuard(cfg Config) (*Guard, error) {
+	if len(cfg.Secret) == 0 {
+		cfg.Secret = make([]byte, 32)
+		if _, err := rand.Read(cfg.Secret); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Difficulty < 1 || cfg.Difficulty > 64 {
+		return nil, errors.New("difficulty must be between 1 and 64 bits")
+	}
+	if cfg.MaxDifficulty < cfg.Difficulty {
+		cfg.MaxDifficulty = cfg.Difficulty
+	}
+	return &Guard{
+		cfg:    cfg,
+		ips:    make(map[string]*counter),
+		solved: make(map[string]*solution),
+	}, nil
+}
+
+// Issue creates a puzzle for the caller, valid for ttl and only from the same IP.
+func (g *Guard) Issue(ip string, ttl time.Duration) (*Puzzle, error) {
+	now := time.Now()
+	g.mu.Lock()
+	g.load.tick(now).challenges++
+	g.ip(ip, now).challenges++
+	difficulty := g.difficulty(ip)
+	g.mu.Unlock()
+
+	expiresAt := now.Add(ttl)
+	data := make([]byte, seedSize, payloadSize+macSize)
+	if _, err := rand.Read(data); err != nil {
+		return nil, err
+	}
+	data = binary.BigEndian.AppendUint64(data, uint64(expiresAt.Unix()))
+	data = append(data, byte(difficulty))
+	data = append(data, g.sign(data, ip)...)
+
+	return &Puzzle{
+		Challenge:  base64.RawURLEncoding.EncodeToString(data),
+		Difficulty: difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// Check accepts a solution of a puzzle issued to the IP for one use of the login
+// identified by subject, the challenge the puzzle was issued with. The verify and token
+// calls of a login present the same solution, so it is accepted once for each use of
+// the same subject, until it expires or Spend is called. Rejected solutions count as
+// failures of the IP.
+func (g *Guard) Check(ip, challenge, nonce, subject string, use Use) error {
+	err := g.check(ip, challenge, nonce, subject, use)
+	if err != nil && !errors.Is(err, ErrRequired) {
+		g.Failure(ip)
+	}
+	return err
+}
+
+func (g *Guard) check(ip, challenge, nonce, subject string, use Use) error {
+	if challenge == "" || nonce == "" {
+		return ErrRequired
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(challenge)
+	if err != nil || len(data) != payloadSize+macSize {
+		return ErrInvalid
+	}
+	payload, mac := data[:payloadSize], data[payloadSize:]
+	if !hmac.Equal(mac, g.sign(payload, ip)) {
+		return ErrInvalid
+	}
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[seedSize:seedSize+8])), 0)
+	if time.Now().After(expiresAt) {
+		return fmt.Errorf("%w: challenge expired", ErrInvalid)
+	}
+	if !Solves(challenge, nonce, int(payload[payloadSize-1])) {
+		return ErrInvalid
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s, ok := g.solved[challenge]
+	if !ok {
+		g.solved[challenge] = &solution{expiresAt: expiresAt, subject: subject, used: use}
+		return nil
+	}
+	// a solution without login cannot be told apart from a replay, it pays for one use
+	if s.subject == "" || s.subject != subject || s.used&use != 0 {
+		return fmt.Errorf("%w: challenge already used", ErrInvalid)
+	}
+	s.used |= use
+	return nil
+}
+
+// Spend stops accepting the solution of the challenge again, e.g. after the login it
+// was presented with failed.
+func (g *Guard) Spend(challenge string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if s, ok := g.solved[challenge]; ok {
+		s.used = spent
+	}
+}
+
+// Failure counts a failed verification of the IP towards its difficulty.
+func (g *Guard) Failure(ip string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ip(ip, time.Now()).failures++
+}
+
+// Run drops expired challenges and idle IPs every interval until stop is closed.
+func (g *Guard) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			g.mu.Lock()
+			for challenge, s := range g.solved {
+				if now.After(s.expiresAt) {
+					delete(g.solved, challenge)
+				}
+			}
+			for ip, c := range g.ips {
+				if now.Sub(c.start) > window {
+					delete(g.ips, ip)
+				}
+			}
+			g.mu.Unlock()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// difficulty adds a bit for every doubling of the load and of the activity of the IP
+// over their thresholds. Failures weigh twice as much as challenges.
+func (g *Guard) difficulty(ip string) int {
+	d := g.cfg.Difficulty
+	d += extraBits(g.load.challenges, g.cfg.LoadThreshold)
+	if c, ok := g.ips[ip]; ok {
+		d += extraBits(c.challenges+2*c.failures, g.cfg.IPThreshold)
+	}
+	return min(d, g.cfg.MaxDifficulty)
+}
+
+func extraBits(count, threshold int) int {
+	if threshold <= 0 || count <= threshold {
+		return 0
+	}
+	return bits.Len(uint(count / threshold))
+}
+
+// ip returns the counter of the IP for the current window. The caller holds the lock.
+func (g *Guard) ip(ip string, now time.Time) *counter {
+	c, ok := g.ips[ip]
+	if !ok {
+		c = &counter{start: now}
+		g.ips[ip] = c
+	}
+	return c.tick(now)
+}
+
+// tick starts a new window once the current one is over.
+func (c *counter) tick(now time.Time) *counter {
+	if now.Sub(c.start) > window {
+		*c = counter{start: now}
+	}
+	return c
+}
+
+func (g *Guard) sign(payload []byte, ip string) []byte {
+	h := hmac.New(sha256.New, g.cfg.Secret)
+	h.Write(payload)
+	h.Write([]byte(ip))
+	return h.Sum(nil)[:macSize]
+}
+
+// Solves reports whether the SHA-256 hash of "challenge:nonce" starts with difficulty zero bits.
+func Solves(challenge, nonce string, difficulty int) bool {
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	zeros := 0
+	for _, b := range sum {
+		if b != 0 {
+			zeros += bits.LeadingZeros8(b)
+			break
+		}
+		zeros += 8
+	}
+	return zeros >= difficulty
+}
+
+// Solve searches the nonce of a puzzle by counting up from zero.
+func Solve(challenge string, difficulty int) string {
+	for n := uint64(0); ; n++ {
+		nonce := strconv.FormatUint(n, 10)
+		if Solves(challenge, nonce, difficulty) {
+			return nonce
+		}
+	}
+}
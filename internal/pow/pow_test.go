@@ -0,0 +1,90 @@
+package pow
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSolves(t *testing.T) {
+	// SHA-256 of "challenge:0" starts with 0x58, of "challenge:162754" with 0x0000681a
+	// and of "q8Yk3n0cR1m2:65378" with 0x000006de
+	tests := []struct {
+		challenge  string
+		nonce      string
+		difficulty int
+		want       bool
+	}{
+		{"challenge", "0", 0, true},
+		{"challenge", "0", 1, true},
+		{"challenge", "0", 2, false},
+		{"challenge", "162754", 17, true},
+		{"challenge", "162754", 18, false},
+		{"q8Yk3n0cR1m2", "65378", 21, true},
+		{"q8Yk3n0cR1m2", "65378", 22, false},
+		{"q8Yk3n0cR1m2", "65379", 21, false},
+	}
+	for _, tt := range tests {
+		if got := Solves(tt.challenge, tt.nonce, tt.difficulty); got != tt.want {
+			t.Errorf("Solves(%q, %q, %d) = %v, want %v", tt.challenge, tt.nonce, tt.difficulty, got, tt.want)
+		}
+	}
+
+	if nonce := Solve("challenge", 17); !Solves("challenge", nonce, 17) {
+		t.Fatalf("Solve() = %s does not solve the puzzle", nonce)
+	}
+}
+
+func TestGuardCheck(t *testing.T) {
+	g, err := NewGuard(Config{Secret: []byte("secret"), Difficulty: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue := func() (string, string) {
+		p, err := g.Issue("10.0.0.1", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p.Challenge, Solve(p.Challenge, p.Difficulty)
+	}
+
+	challenge, nonce := issue()
+	if err := g.Check("10.0.0.1", challenge, "", "login", UseVerify); !errors.Is(err, ErrRequired) {
+		t.Fatalf("Check() without nonce error = %v, want %v", err, ErrRequired)
+	}
+	if err := g.Check("10.0.0.2", challenge, nonce, "login", UseVerify); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Check() from another IP error = %v, want %v", err, ErrInvalid)
+	}
+
+	// the verify and token calls of one login share the solution, once each
+	for _, use := range []Use{UseVerify, UseExchange} {
+		if err := g.Check("10.0.0.1", challenge, nonce, "login", use); err != nil {
+			t.Fatalf("Check() of use %d error = %v", use, err)
+		}
+	}
+	for _, use := range []Use{UseVerify, UseExchange} {
+		if err := g.Check("10.0.0.1", challenge, nonce, "login", use); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("Check() of a third use error = %v, want %v", err, ErrInvalid)
+		}
+	}
+
+	challenge, nonce = issue()
+	if err := g.Check("10.0.0.1", challenge, nonce, "login", UseVerify); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Check("10.0.0.1", challenge, nonce, "other login", UseExchange); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Check() for another login error = %v, want %v", err, ErrInvalid)
+	}
+	g.Spend(challenge)
+	if err := g.Check("10.0.0.1", challenge, nonce, "login", UseExchange); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Check() of a spent solution error = %v, want %v", err, ErrInvalid)
+	}
+
+	challenge, nonce = issue()
+	if err := g.Check("10.0.0.1", challenge, nonce, "", UseVerify); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Check("10.0.0.1", challenge, nonce, "", UseExchange); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Check() of a solution without login error = %v, want %v", err, ErrInvalid)
+	}
+}
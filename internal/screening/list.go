@@ -0,0 +1,103 @@
+package screening
+
+import (
+	"TON/pkg/address"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// list maps raw addresses to their labels.
+type list struct {
+	path      string
+	modTime   time.Time
+	addresses map[string]string
+	skipped   int
+}
+
+// loadList reads a CSV file with the address in the first column and an optional
+// label in the second, or a JSON array of addresses or of {"address", "label"} objects.
+// Addresses are accepted in any form; lines that are not addresses, such as a
+// header, are skipped.
+func loadList(path string, modTime time.Time) (*list, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &list{path: path, modTime: modTime, addresses: make(map[string]string)}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = l.parseJSON(data)
+	} else {
+		err = l.parseCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *list) add(addr, label string) {
+	raw, err := address.Normalize(strings.TrimSpace(addr))
+	if err != nil {
+		l.skipped++
+		return
+	}
+	l.addresses[raw] = strings.TrimSpace(label)
+}
+
+func (l *list) parseCSV(data []byte) error {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		var label string
+		if len(record) > 1 {
+			label = record[1]
+		}
+		l.add(record[0], label)
+	}
+}
+
+func (l *list) parseJSON(data []byte) error {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	for _, raw := range entries {
+		var addr string
+		if err := json.Unmarshal(raw, &addr); err == nil {
+			l.add(addr, "")
+			continue
+		}
+		var entry struct {
+			Address string `json:"address"`
+			Label   string `json:"label"`
+		}
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			l.skipped++
+			continue
+		}
+		l.add(entry.Address, entry.Label)
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package screening
+
+import (
+	"TON/internal/audit"
+	"TON/internal/chain"
+	"TON/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnknownAction is returned for actions other than block, flag and log.
+var ErrUnknownAction = errors.New("unknown screening action, expected block, flag or log")
+
+// Action is what happens when a wallet matches a list.
+type Action string
+
+const (
+	// Log only records the hit in the audit trail.
+	Log Action = "log"
+	// Flag records the hit and lists the list in the screening_flags claim.
+	Flag Action = "flag"
+	// Block records the hit and rejects the login.
+	Block Action = "block"
+)
+
+func ParseAction(s string) (Action, error) {
+	switch a := Action(strings.ToLower(strings.TrimSpace(s))); a {
+	case Log, Flag, Block:
+		return a, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownAction, s)
+	}
+}
+
+// ParseActions reads per list actions written as "list=action" pairs separated by commas.
+func ParseActions(s string) (map[string]Action, error) {
+	actions := make(map[string]Action)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid screening action %q, expected list=action", pair)
+		}
+		a, err := ParseAction(value)
+		if err != nil {
+			return nil, err
+		}
+		actions[strings.TrimSpace(name)] = a
+	}
+	return actions, nil
+}
+
+// severity orders actions from log to block.
+func (a Action) severity() int {
+	switch a {
+	case Block:
+		return 2
+	case Flag:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type Config struct {
+	// Dir holds the lists, one *.csv or *.json file per list named after the file.
+	Dir string
+	// Action applies to lists without an entry in Actions.
+	Action  Action
+	Actions map[string]Action
+	// Counterparties also screens the senders and recipients of the latest
+	// CounterpartyLimit transactions of the wallet.
+	Counterparties     bool
+	CounterpartyAction Action
+	CounterpartyLimit  int
+}
+
+// Hit is a match of a wallet, or one of its counterparties, on a list.
+type Hit struct {
+	List string
+	// Address that matched, the wallet itself or a counterparty.
+	Address string
+	Label   string
+	// Counterparty is set when Address is a counterparty of the wallet.
+	Counterparty bool
+	Action       Action
+}
+
+// Result of screening a wallet.
+type Result struct {
+	Hits []Hit
+	// Action is the most severe action of the hits, empty without hits.
+	Action Action
+}
+
+// Lists returns the names of the lists with a hit of the action.
+func (r *Result) Lists(action Action) []string {
+	var lists []string
+	for _, h := range r.Hits {
+		if h.Action == action && !slices.Contains(lists, h.List) {
+			lists = append(lists, h.List)
+		}
+	}
+	return lists
+}
+
+// Flags returns the names of the lists with a flag or block hit.
+func (r *Result) Flags() []string {
+	var flags []string
+	for _, h := range r.Hits {
+		if h.Action != Log && !slices.Contains(flags, h.List) {
+			flags = append(flags, h.List)
+		}
+	}
+	return flags
+}
+
+// Screener matches wallets against address lists loaded from local files and
+// records every hit in the audit trail.
+type Screener struct {
+	cfg   Config
+	log   logger.Logger
+	audit *audit.Log
+
+	mu    sync.RWMutex
+	lists map[string]*list
+}
+
+// New loads the lists of cfg.Dir. Screening without a single listed address would
+// silently admit every wallet, so that is an error.
+func New(cfg Config, log logger.Logger, auditLog *audit.Log) (*Screener, error) {
+	if cfg.CounterpartyLimit <= 0 {
+		cfg.CounterpartyLimit = 50
+	}
+	s := &Screener{
+		cfg:   cfg,
+		log:   log,
+		audit: auditLog,
+		lists: make(map[string]*list),
+	}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	addresses := 0
+	for _, l := range s.lists {
+		addresses += len(l.addresses)
+	}
+	if addresses == 0 {
+		return nil, fmt.Errorf("screening lists in %s hold no address", cfg.Dir)
+	}
+	log.Info(context.Background(), fmt.Sprintf("Screening enabled with %d lists and %d addresses", len(s.lists), addresses))
+	return s, nil
+}
+
+// Reload reads lists whose files changed since they were loaded, adds new files and
+// drops lists whose files are gone. A list that fails to load keeps its old content, and
+// all lists are kept when the directory is missing or holds no list.
+func (s *Screener) Reload() error {
+	if _, err := os.Stat(s.cfg.Dir); err != nil {
+		return fmt.Errorf("screening directory: %w", err)
+	}
+	var files []string
+	for _, pattern := range []string{"*.csv", "*.json"} {
+		matches, err := filepath.Glob(filepath.Join(s.cfg.Dir, pattern))
+		if err != nil {
+			return err
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no *.csv or *.json screening list in %s", s.cfg.Dir)
+	}
+
+	s.mu.RLock()
+	current := s.lists
+	s.mu.RUnlock()
+
+	lists := make(map[string]*list, len(files))
+	var errs []error
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		info, err := os.Stat(file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if old, ok := current[name]; ok && old.path == file && old.modTime.Equal(info.ModTime()) {
+			lists[name] = old
+			continue
+		}
+
+		l, err := loadList(file, info.ModTime())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("screening list %s: %w", name, err))
+			if old, ok := current[name]; ok {
+				lists[name] = old
+			}
+			continue
+		}
+		if _, ok := lists[name]; ok {
+			errs = append(errs, fmt.Errorf("screening list %s: defined by more than one file", name))
+			continue
+		}
+		lists[name] = l
+		msg := fmt.Sprintf("Loaded screening list %s with %d addresses", name, len(l.addresses))
+		if l.skipped > 0 {
+			msg += fmt.Sprintf(", skipped %d invalid lines", l.skipped)
+		}
+		s.log.Info(context.Background(), msg)
+	}
+
+	s.mu.Lock()
+	s.lists = lists
+	s.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// Run reloads the lists every interval until stop is closed.
+func (s *Screener) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Reload(); err != nil {
+				s.log.Error(context.Background(), "Failed to reload screening lists: "+err.Error())
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Screen matches the wallet and, when configured, its counterparties against the lists
+// and records the hits in the audit trail. A nil screener screens nothing.
+func (s *Screener) Screen(ctx context.Context, provider chain.ChainProvider, clientID, addr string) (*Result, error) {
+	res := &Result{}
+	if s == nil {
+		return res, nil
+	}
+
+	s.mu.RLock()
+	lists := s.lists
+	s.mu.RUnlock()
+
+	names := make([]string, 0, len(lists))
+	for name := range lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if label, ok := lists[name].addresses[addr]; ok {
+			res.add(Hit{List: name, Address: addr, Label: label, Action: s.action(name)})
+		}
+	}
+
+	if s.cfg.Counterparties {
+		counterparties, err := s.counterparties(ctx, provider, addr)
+		if err != nil {
+			return nil, err
+		}
+		for _, cp := range counterparties {
+			for _, name := range names {
+				if label, ok := lists[name].addresses[cp]; ok {
+					res.add(Hit{List: name, Address: cp, Label: label, Counterparty: true, Action: s.cfg.CounterpartyAction})
+				}
+			}
+		}
+	}
+
+	for _, h := range res.Hits {
+		s.record(ctx, clientID, addr, h)
+	}
+	return res, nil
+}
+
+func (s *Screener) action(list string) Action {
+	if a, ok := s.cfg.Actions[list]; ok {
+		return a
+	}
+	return s.cfg.Action
+}
+
+func (r *Result) add(h Hit) {
+	r.Hits = append(r.Hits, h)
+	if h.Action.severity() >= r.Action.severity() {
+		r.Action = h.Action
+	}
+}
+
+// counterparties returns the distinct senders and recipients of the latest transactions.
+func (s *Screener) counterparties(ctx context.Context, provider chain.ChainProvider, addr string) ([]string, error) {
+	txs, err := provider.GetTransactions(ctx, addr, nil, s.cfg.CounterpartyLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions for screening: %w", err)
+	}
+
+	seen := map[string]bool{addr: true}
+	var counterparties []string
+	for _, tx := range txs {
+		for _, a := range append([]string{tx.From}, tx.To...) {
+			if a != "" && !seen[a] {
+				seen[a] = true
+				counterparties = append(counterparties, a)
+			}
+		}
+	}
+	return counterparties, nil
+}
+
+// record writes the hit to the audit trail. Failing to do so is logged, the login
+// decision stands.
+func (s *Screener) record(ctx context.Context, clientID, addr string, h Hit) {
+	details := map[string]interface{}{
+		"list":   h.List,
+		"action": string(h.Action),
+	}
+	if h.Label != "" {
+		details["label"] = h.Label
+	}
+	if h.Counterparty {
+		details["counterparty"] = h.Address
+	}
+
+	err := s.audit.Record(audit.Event{
+		Actor:    "screening",
+		Action:   "screening.hit",
+		Target:   addr,
+		ClientID: clientID,
+		Details:  details,
+	})
+	if err != nil {
+		s.log.Error(ctx, "Failed to record screening hit: "+err.Error())
+	}
+}
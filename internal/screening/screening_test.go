@@ -0,0 +1,176 @@
+package screening
+
+import (
+	"TON/internal/audit"
+	"TON/internal/chain"
+	"TON/pkg/logger"
+	"TON/pkg/tonwallet"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	walletA   = "0:ba295e33b3c4c9b5265aa4ead1166a92931ce9abea120a8c5e91044a1257f89c"
+	friendlyA = "EQC6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nC1I"
+	walletB   = "0:930d5533980aba11fcd81845a954ebf5eb2a3e1f9570dc1d2b92d722773fd42c"
+	friendlyB = "EQCTDVUzmAq6EfzYGEWpVOv16yo-H5Vw3B0rktcidz_ULOUj"
+	walletC   = "0:960ab627408d5472d9d125b667cbe00ce17eeaa44e9dc6a86e93cdfef2c480d5"
+)
+
+// writeList writes a list file with a modification time after every earlier write.
+func writeList(t *testing.T, dir, name, content string, mod time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newScreener(t *testing.T, cfg Config) *Screener {
+	t.Helper()
+	auditLog, err := audit.Open("", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(cfg, logger.New("test"), auditLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestNewWithoutAddresses(t *testing.T) {
+	auditLog, err := audit.Open("", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty := t.TempDir()
+	headerOnly := t.TempDir()
+	writeList(t, headerOnly, "ofac.csv", "address,label\n", time.Now())
+
+	for name, dir := range map[string]string{
+		"missing directory": filepath.Join(empty, "missing"),
+		"empty directory":   empty,
+		"no addresses":      headerOnly,
+	} {
+		if _, err := New(Config{Dir: dir, Action: Block}, logger.New("test"), auditLog); err == nil {
+			t.Errorf("New() with %s succeeded", name)
+		}
+	}
+}
+
+func TestScreen(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	// addresses are matched whatever form the list writes them in
+	writeList(t, dir, "ofac.csv", "address,label\n"+friendlyA+",Sanctioned\n", now)
+	writeList(t, dir, "scam.json", `[{"address": "`+walletB+`", "label": "Drainer"}, "`+friendlyA+`"]`, now)
+	s := newScreener(t, Config{Dir: dir, Action: Block, Actions: map[string]Action{"scam": Flag}})
+	p := chain.NewFixtureProvider(tonwallet.Options{})
+
+	tests := []struct {
+		addr   string
+		lists  string
+		action Action
+		flags  string
+	}{
+		{walletA, "ofac,scam", Block, "ofac,scam"},
+		{walletB, "scam", Flag, "scam"},
+		{walletC, "", "", ""},
+	}
+	for _, tt := range tests {
+		res, err := s.Screen(context.Background(), p, "my-dapp", tt.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var lists []string
+		for _, h := range res.Hits {
+			lists = append(lists, h.List)
+		}
+		if strings.Join(lists, ",") != tt.lists || res.Action != tt.action || strings.Join(res.Flags(), ",") != tt.flags {
+			t.Errorf("Screen(%s) = lists %v, action %q, flags %v, want %s, %q, %s", tt.addr, lists, res.Action, res.Flags(), tt.lists, tt.action, tt.flags)
+		}
+	}
+
+	res, _ := s.Screen(context.Background(), p, "my-dapp", walletA)
+	if got := res.Lists(Block); len(got) != 1 || got[0] != "ofac" || res.Hits[0].Label != "Sanctioned" {
+		t.Fatalf("block hits = %v, %+v", got, res.Hits)
+	}
+	if events := s.audit.Recent(10, "screening."); len(events) != 5 {
+		t.Fatalf("audit trail has %d screening events, want 5", len(events))
+	}
+}
+
+func TestScreenCounterparties(t *testing.T) {
+	dir := t.TempDir()
+	writeList(t, dir, "ofac.csv", friendlyB+"\n", time.Now())
+	s := newScreener(t, Config{Dir: dir, Action: Block, Counterparties: true, CounterpartyAction: Flag})
+
+	p := chain.NewFixtureProvider(tonwallet.Options{})
+	p.AddTransaction(walletA, chain.Transaction{LT: 1, Time: time.Now(), From: walletB})
+
+	res, err := s.Screen(context.Background(), p, "", walletA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 1 || !res.Hits[0].Counterparty || res.Hits[0].Address != walletB || res.Action != Flag {
+		t.Fatalf("Screen() = %+v, want a flagged counterparty hit", res)
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+	writeList(t, dir, "ofac.csv", walletA+"\n", start)
+	writeList(t, dir, "scam.csv", walletC+"\n", start)
+	s := newScreener(t, Config{Dir: dir, Action: Block})
+	p := chain.NewFixtureProvider(tonwallet.Options{})
+
+	hit := func(addr string) bool {
+		t.Helper()
+		res, err := s.Screen(context.Background(), p, "", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(res.Hits) > 0
+	}
+
+	// the changed list replaces the previous one, the removed list is dropped
+	writeList(t, dir, "ofac.csv", friendlyB+"\n", start.Add(time.Minute))
+	if err := os.Remove(filepath.Join(dir, "scam.csv")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if hit(walletA) || !hit(walletB) || hit(walletC) {
+		t.Fatalf("after reload: A=%v B=%v C=%v, want only B", hit(walletA), hit(walletB), hit(walletC))
+	}
+
+	// a list that fails to load keeps its content
+	writeList(t, dir, "ofac.csv", `"unterminated`, start.Add(2*time.Minute))
+	if err := s.Reload(); err == nil {
+		t.Fatal("Reload() of a broken list succeeded")
+	}
+	if !hit(walletB) {
+		t.Fatal("broken list lost its previous content")
+	}
+
+	// so do all lists when the directory empties
+	if err := os.Remove(filepath.Join(dir, "ofac.csv")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reload(); err == nil {
+		t.Fatal("Reload() of an empty directory succeeded")
+	}
+	if !hit(walletB) {
+		t.Fatal("lists dropped with an empty directory")
+	}
+}
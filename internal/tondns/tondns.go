@@ -0,0 +1,91 @@
+package tondns
+
+import (
+	"TON/internal/chain"
+	"TON/pkg/cache"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Resolver finds the TON DNS name of a wallet. A name counts only when its forward
+// resolution points back to the wallet, so a domain cannot claim foreign wallets.
+type Resolver struct {
+	names  *cache.Cache[string]
+	maxAge time.Duration
+}
+
+// NewResolver creates a resolver that reuses names, and their absence, up to maxAge old.
+func NewResolver(maxAge time.Duration) *Resolver {
+	return &Resolver{
+		names:  cache.New[string](maxAge),
+		maxAge: maxAge,
+	}
+}
+
+// Run periodically drops old cache entries until stop is closed.
+func (r *Resolver) Run(interval time.Duration, stop <-chan struct{}) {
+	r.names.Run(interval, stop)
+}
+
+// Name returns the verified .ton or .t.me name of the wallet, "" when it has none.
+// Providers without reverse lookups yield no name.
+func (r *Resolver) Name(ctx context.Context, provider chain.ChainProvider, network chain.Network, addr string) (string, error) {
+	name, _, err := r.names.Fetch(string(network)+"/"+addr, r.maxAge, func() (string, error) {
+		return lookup(ctx, provider, addr)
+	})
+	return name, err
+}
+
+func lookup(ctx context.Context, provider chain.ChainProvider, addr string) (string, error) {
+	domains, err := provider.ReverseDNS(ctx, addr)
+	if errors.Is(err, chain.ErrNotSupported) || errors.Is(err, chain.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	candidates := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+		if rank(domain) >= 0 {
+			candidates = append(candidates, domain)
+		}
+	}
+	// .ton names before .t.me usernames, shorter names first
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if rank(candidates[i]) != rank(candidates[j]) {
+			return rank(candidates[i]) < rank(candidates[j])
+		}
+		return len(candidates[i]) < len(candidates[j])
+	})
+
+	for _, domain := range candidates {
+		target, err := provider.ResolveDNS(ctx, domain)
+		if errors.Is(err, chain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if target == addr {
+			return domain, nil
+		}
+	}
+	return "", nil
+}
+
+// rank orders the supported zones, -1 for other domains.
+func rank(domain string) int {
+	switch {
+	case strings.HasSuffix(domain, ".ton"):
+		return 0
+	case strings.HasSuffix(domain, ".t.me"):
+		return 1
+	default:
+		return -1
+	}
+}
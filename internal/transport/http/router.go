@@ -1,24 +1,178 @@
 package http
 
 import (
+	"TON/internal/audit"
+	"TON/internal/bridge"
+	"TON/internal/chain"
+	"TON/internal/client"
 	"TON/internal/config"
+	"TON/internal/credential"
+	"TON/internal/gating"
 	"TON/internal/handler"
+	"TON/internal/lists"
+	"TON/internal/multisig"
+	"TON/internal/policy"
+	"TON/internal/pow"
+	"TON/internal/screening"
+	"TON/internal/tondns"
+	"TON/internal/txauth"
 	"TON/internal/usecase"
+	"TON/pkg/address"
+	"TON/pkg/jwt"
 	"TON/pkg/logger"
+	"TON/pkg/tonwallet"
 	"TON/pkg/validator"
 	"crypto/rsa"
+	"fmt"
+	"io"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/xssnick/tonutils-go/tlb"
 )
 
-func SetupRoutes(e *echo.Echo, cfg *config.Config, log logger.Logger, privKey *rsa.PrivateKey, pubKey *rsa.PublicKey) {
+func SetupRoutes(e *echo.Echo, cfg *config.Config, log logger.Logger, privKey *rsa.PrivateKey, pubKey *rsa.PublicKey, clients *client.Registry) error {
+	walletOpts := tonwallet.Options{
+		Workchain:     cfg.WalletWorkchain,
+		SubwalletID:   cfg.WalletSubwalletID,
+		V5SubwalletID: cfg.WalletV5SubwalletID,
+	}
 
-	authorizeUC := usecase.NewAuthorizeUseCase(120, log)
-	verifyUC := usecase.NewVerifyUseCase(cfg.Issuer, 2*time.Minute, log, cfg.ApiURL, cfg.ApiKey)
-	tokenUC := usecase.NewTokenUseCase(cfg.Issuer, 5*time.Minute, privKey)
-	jwksUC := usecase.NewJWKSUseCase(cfg.KeyName, pubKey)
-	verifyTokenUC := usecase.NewTokenVerifyUseCase()
+	defaultNetwork, err := chain.ParseNetwork(cfg.DefaultNetwork)
+	if err != nil {
+		return err
+	}
+	preference, err := tonwallet.ParsePreference(cfg.WalletPreference)
+	if err != nil {
+		return fmt.Errorf("wallet preference: %w", err)
+	}
+	addressFormat, err := address.ParseFormat(cfg.AddressFormat)
+	if err != nil {
+		return err
+	}
+
+	providers, err := newChainProviders(cfg, walletOpts)
+	if err != nil {
+		return err
+	}
+	for _, provider := range providers {
+		if closer, ok := provider.(io.Closer); ok {
+			e.Server.RegisterOnShutdown(func() { _ = closer.Close() })
+		}
+	}
+	if _, err := providers.Get(defaultNetwork); err != nil {
+		return fmt.Errorf("default network: %w", err)
+	}
+
+	gate, err := gating.NewEvaluator(cfg.BalanceMaxAge, cfg.ActivityMaxAge, cfg.ActivityScanLimit, cfg.PaymentsMaxAge, cfg.PaymentsScanLimit)
+	if err != nil {
+		return fmt.Errorf("gating: %w", err)
+	}
+	stopGate := make(chan struct{})
+	go gate.Run(time.Minute, stopGate)
+	e.Server.RegisterOnShutdown(func() { close(stopGate) })
+
+	names := tondns.NewResolver(cfg.DNSMaxAge)
+	stopNames := make(chan struct{})
+	go names.Run(time.Minute, stopNames)
+	e.Server.RegisterOnShutdown(func() { close(stopNames) })
+
+	policies, err := policy.Load(cfg.PoliciesPath)
+	if err != nil {
+		return fmt.Errorf("policies: %w", err)
+	}
+
+	listStore, err := lists.Open(cfg.ListsPath)
+	if err != nil {
+		return fmt.Errorf("lists: %w", err)
+	}
+	auditLog, err := audit.Open(cfg.AuditLogPath, 1000)
+	if err != nil {
+		return fmt.Errorf("audit log: %w", err)
+	}
+	e.Server.RegisterOnShutdown(func() { _ = auditLog.Close() })
+
+	credentials, err := credential.Open(cfg.CredentialStatusPath, cfg.CredentialStatusSize)
+	if err != nil {
+		return fmt.Errorf("credentials: %w", err)
+	}
+	credentialKey, credentialKeyID, credentialPub, err := loadCredentialKey(cfg, privKey)
+	if err != nil {
+		return fmt.Errorf("credential key: %w", err)
+	}
+
+	screener, err := newScreener(cfg, log, auditLog)
+	if err != nil {
+		return fmt.Errorf("screening: %w", err)
+	}
+	if screener != nil {
+		stopScreening := make(chan struct{})
+		go screener.Run(cfg.ScreeningRefresh, stopScreening)
+		e.Server.RegisterOnShutdown(func() { close(stopScreening) })
+	}
+
+	var guard *pow.Guard
+	if cfg.PoWEnabled {
+		guard, err = pow.NewGuard(pow.Config{
+			Secret:        []byte(cfg.PoWSecret),
+			Difficulty:    cfg.PoWDifficulty,
+			MaxDifficulty: cfg.PoWMaxDifficulty,
+			LoadThreshold: cfg.PoWLoadThreshold,
+			IPThreshold:   cfg.PoWIPThreshold,
+		})
+		if err != nil {
+			return fmt.Errorf("proof of work: %w", err)
+		}
+		stopGuard := make(chan struct{})
+		go guard.Run(time.Minute, stopGuard)
+		e.Server.RegisterOnShutdown(func() { close(stopGuard) })
+	}
+
+	var watcher *txauth.Watcher
+	if cfg.TxAuthEnabled {
+		watcher, err = newWatcher(cfg, log, providers, defaultNetwork, gate)
+		if err != nil {
+			return fmt.Errorf("transaction auth: %w", err)
+		}
+		stopWatcher := make(chan struct{})
+		go watcher.Run(cfg.TxAuthPoll, stopWatcher)
+		e.Server.RegisterOnShutdown(func() { close(stopWatcher) })
+	}
+
+	var collector *multisig.Collector
+	if cfg.MultisigEnabled {
+		collector = multisig.NewCollector(cfg.Issuer, cfg.MultisigTTL, cfg.MultisigMaxSessions)
+		stopCollector := make(chan struct{})
+		go collector.Run(time.Minute, stopCollector)
+		e.Server.RegisterOnShutdown(func() { close(stopCollector) })
+	}
+
+	authorizeUC := usecase.NewAuthorizeUseCase(120, log, clients, guard, watcher)
+	verifyUC := usecase.NewVerifyUseCase(cfg.Issuer, 2*time.Minute, log, usecase.VerifyDeps{
+		Providers:      providers,
+		DefaultNetwork: defaultNetwork,
+		Clients:        clients,
+		WalletOpts:     walletOpts,
+		Preference:     preference,
+		AddressFormat:  addressFormat,
+		Gate:           gate,
+		Names:          names,
+		DNSClaims:      cfg.DNSClaims,
+		Policies:       policies,
+		Lists:          listStore,
+		AllowlistOnly:  cfg.AllowlistOnly,
+		Screener:       screener,
+		Guard:          guard,
+	})
+	walletsUC := usecase.NewWalletsUseCase(log, providers, defaultNetwork, clients, preference, addressFormat)
+	tokenUC := usecase.NewTokenUseCase(cfg.Issuer, 5*time.Minute, privKey, verifyUC, watcher, collector)
+	transactionUC := usecase.NewTransactionUseCase(watcher)
+	jwksUC := usecase.NewJWKSUseCase(cfg.KeyName, pubKey, credentialKeyID, credentialPub)
+	verifyTokenUC := usecase.NewTokenVerifyUseCase(cfg.Issuer)
+	manifestUC := usecase.NewManifestUseCase(cfg.PublicURL, clients)
+	signDataUC := usecase.NewSignDataUseCase(cfg.Issuer, cfg.SignDataAttestationTTL, cfg.SignDataMaxAge, privKey, log, providers, defaultNetwork, clients)
+	credentialUC := usecase.NewCredentialUseCase(cfg.PublicURL, credentialKeyID, cfg.CredentialTTL, credentialKey, log, verifyUC, credentials, auditLog)
+	didUC := usecase.NewDIDUseCase(log, providers, names)
 
 	val := validator.NewCustomValidator()
 
@@ -30,12 +184,217 @@ func SetupRoutes(e *echo.Echo, cfg *config.Config, log logger.Logger, privKey *r
 		tokenUC,
 		jwksUC,
 		verifyTokenUC,
+		walletsUC,
+		transactionUC,
 	)
 
 	api := e.Group("/oauth")
 	api.GET("/authorize", oauthHandler.AuthorizeHandler)
+	api.GET("/wallets", oauthHandler.WalletsHandler)
 	api.POST("/verify", oauthHandler.VerifyHandler)
 	api.POST("/token", oauthHandler.TokenHandler)
 	api.GET("/jwks", oauthHandler.JWKSHandler)
 	api.POST("/verify-token", oauthHandler.VerifyTokenHandler)
+	if watcher != nil {
+		api.GET("/transaction/:challenge", oauthHandler.TransactionStatusHandler)
+		api.POST("/transaction/:challenge/token", oauthHandler.TransactionTokenHandler)
+	}
+
+	signDataHandler := handler.NewSignDataHandler(log, val, signDataUC)
+	api.POST("/sign-data", signDataHandler.VerifyHandler)
+
+	credentialHandler := handler.NewCredentialHandler(log, val, credentialUC)
+	api.POST("/credentials", credentialHandler.IssueHandler)
+	api.GET("/credentials/status", credentialHandler.StatusListHandler)
+
+	if collector != nil {
+		multisigUC := usecase.NewMultisigUseCase(2*time.Minute, log, providers, defaultNetwork, clients, collector)
+		multisigHandler := handler.NewMultisigHandler(log, val, multisigUC, tokenUC)
+
+		api.POST("/multisig", multisigHandler.StartHandler)
+		api.GET("/multisig/:challenge", multisigHandler.SessionHandler)
+		api.POST("/multisig/:challenge/signatures", multisigHandler.SignHandler)
+		api.POST("/multisig/:challenge/token", multisigHandler.TokenHandler)
+	}
+
+	// DID resolution at the path of Universal Resolver drivers
+	didHandler := handler.NewDIDHandler(log, didUC)
+	e.GET("/1.0/identifiers/:did", didHandler.ResolveHandler)
+
+	clientHandler := handler.NewClientHandler(log, manifestUC)
+
+	clientsAPI := e.Group("/clients")
+	clientsAPI.GET("/:client_id/tonconnect-manifest.json", clientHandler.ManifestHandler)
+	clientsAPI.GET("/:client_id/icon", clientHandler.IconHandler)
+
+	if cfg.AdminToken != "" {
+		policyUC := usecase.NewPolicyUseCase(log, providers, defaultNetwork, clients, policies, gate, names)
+		listsUC := usecase.NewListsUseCase(log, listStore, clients, auditLog)
+		auditUC := usecase.NewAuditUseCase(auditLog)
+		var fixtureUC usecase.FixtureUseCase
+		if chain.Kind(cfg.ChainProvider) == chain.KindFixture {
+			fixtureUC = usecase.NewFixtureUseCase(providers, defaultNetwork)
+		}
+		adminHandler := handler.NewAdminHandler(log, val, policyUC, listsUC, auditUC, fixtureUC, credentialUC)
+
+		adminAPI := e.Group("/admin", handler.AdminAuth(cfg.AdminToken))
+		adminAPI.POST("/policies/dry-run", adminHandler.PolicyDryRunHandler)
+		adminAPI.GET("/lists/:kind", adminHandler.ListEntriesHandler)
+		adminAPI.POST("/lists/:kind", adminHandler.AddListEntryHandler)
+		adminAPI.DELETE("/lists/:kind/:address", adminHandler.RemoveListEntryHandler)
+		adminAPI.GET("/audit", adminHandler.AuditHandler)
+		adminAPI.GET("/credentials", adminHandler.CredentialsHandler)
+		adminAPI.DELETE("/credentials/:id", adminHandler.RevokeCredentialHandler)
+		if fixtureUC != nil {
+			adminAPI.POST("/fixture/transactions", adminHandler.FixtureTransactionHandler)
+		}
+	}
+
+	if cfg.BridgeEnabled {
+		setupBridge(e, cfg, log, val)
+	}
+
+	return nil
+}
+
+// newChainProviders builds the chain provider of mainnet and, when enabled, of testnet.
+func newChainProviders(cfg *config.Config, walletOpts tonwallet.Options) (chain.Providers, error) {
+	providers := chain.Providers{}
+
+	mainnet, err := newChainProvider(cfg, chain.Mainnet, walletOpts)
+	if err != nil {
+		return nil, err
+	}
+	providers[chain.Mainnet] = mainnet
+
+	if cfg.TestnetEnabled {
+		testnet, err := newChainProvider(cfg, chain.Testnet, walletOpts)
+		if err != nil {
+			return nil, fmt.Errorf("testnet: %w", err)
+		}
+		providers[chain.Testnet] = testnet
+	}
+
+	return providers, nil
+}
+
+func newChainProvider(cfg *config.Config, network chain.Network, walletOpts tonwallet.Options) (chain.ChainProvider, error) {
+	walletOpts.NetworkGlobalID = network.GlobalID()
+	providerCfg := chain.Config{
+		Kind:          chain.Kind(cfg.ChainProvider),
+		Network:       network,
+		FixturePath:   cfg.ChainFixturePath,
+		GlobalConfig:  cfg.LiteServerConfig,
+		ProofCheck:    chain.ProofCheck(cfg.LiteServerProofCheck),
+		WalletOptions: walletOpts,
+	}
+
+	switch providerCfg.Kind {
+	case chain.KindTonAPI:
+		providerCfg.URL, providerCfg.APIKey = cfg.ApiURL, cfg.ApiKey
+	case chain.KindToncenterV2, chain.KindToncenterV3:
+		providerCfg.URL, providerCfg.APIKey = cfg.ToncenterURL, cfg.ToncenterAPIKey
+	}
+
+	if network == chain.Testnet {
+		providerCfg.GlobalConfig = cfg.TestnetLiteServerConfig
+		providerCfg.DNSRoot = cfg.TestnetDNSRoot
+		switch providerCfg.Kind {
+		case chain.KindTonAPI:
+			providerCfg.URL = cfg.TestnetApiURL
+		case chain.KindToncenterV2, chain.KindToncenterV3:
+			providerCfg.URL, providerCfg.APIKey = cfg.TestnetToncenterURL, cfg.TestnetToncenterAPIKey
+		}
+	}
+
+	return chain.New(providerCfg)
+}
+
+// newScreener loads the screening lists, nil when screening is disabled.
+func newScreener(cfg *config.Config, log logger.Logger, auditLog *audit.Log) (*screening.Screener, error) {
+	if !cfg.ScreeningEnabled {
+		return nil, nil
+	}
+
+	action, err := screening.ParseAction(cfg.ScreeningAction)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := screening.ParseActions(cfg.ScreeningListActions)
+	if err != nil {
+		return nil, err
+	}
+	counterpartyAction, err := screening.ParseAction(cfg.ScreeningCounterpartyAction)
+	if err != nil {
+		return nil, err
+	}
+
+	return screening.New(screening.Config{
+		Dir:                cfg.ScreeningPath,
+		Action:             action,
+		Actions:            actions,
+		Counterparties:     cfg.ScreeningCounterparties,
+		CounterpartyAction: counterpartyAction,
+		CounterpartyLimit:  cfg.ScreeningCounterpartyLimit,
+	}, log, auditLog)
+}
+
+// loadCredentialKey returns the key credentials are signed with, its kid and the public
+// key to publish next to the service key. Without a credential key the service key is
+// used under its own name and nothing more is published.
+func loadCredentialKey(cfg *config.Config, serviceKey *rsa.PrivateKey) (*rsa.PrivateKey, string, *rsa.PublicKey, error) {
+	if cfg.CredentialKeyPath == "" {
+		return serviceKey, cfg.KeyName, nil, nil
+	}
+	if cfg.CredentialKeyName == cfg.KeyName {
+		return nil, "", nil, fmt.Errorf("name %q is the name of the service key", cfg.CredentialKeyName)
+	}
+
+	key, err := jwt.LoadPrivateKey(cfg.CredentialKeyPath)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return key, cfg.CredentialKeyName, &key.PublicKey, nil
+}
+
+// newWatcher builds the watcher of transaction mode logins.
+func newWatcher(cfg *config.Config, log logger.Logger, providers chain.Providers, defaultNetwork chain.Network, gate *gating.Evaluator) (*txauth.Watcher, error) {
+	addr, err := address.Normalize(cfg.TxAuthAddress)
+	if err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+	amount, err := tlb.FromTON(cfg.TxAuthAmount)
+	if err != nil {
+		return nil, fmt.Errorf("amount: %w", err)
+	}
+
+	return txauth.NewWatcher(txauth.Config{
+		Network:    defaultNetwork,
+		Address:    addr,
+		Amount:     amount.Nano().Int64(),
+		TTL:        cfg.TxAuthTTL,
+		MaxPending: cfg.TxAuthMaxPending,
+	}, log, providers, gate), nil
+}
+
+func setupBridge(e *echo.Echo, cfg *config.Config, log logger.Logger, val *validator.CustomValidator) {
+	hub := bridge.NewHub(bridge.Config{
+		MaxTTL:         cfg.BridgeMaxTTL,
+		MaxConnections: cfg.BridgeMaxConnections,
+		BufferSize:     cfg.BridgeBufferSize,
+		MaxClientIDs:   cfg.BridgeMaxClientIDs,
+	})
+
+	stop := make(chan struct{})
+	go hub.Run(time.Minute, stop)
+	e.Server.RegisterOnShutdown(func() {
+		close(stop)
+		hub.Close()
+	})
+
+	bridgeHandler := handler.NewBridgeHandler(log, val, hub, cfg.BridgeHeartbeat)
+
+	api := e.Group("/bridge")
+	api.GET("/events", bridgeHandler.EventsHandler)
+	api.POST("/message", bridgeHandler.MessageHandler)
 }
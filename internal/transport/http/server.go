@@ -1,14 +1,17 @@
 package http
 
 import (
+	"TON/internal/client"
 	"TON/internal/config"
 	"TON/pkg/logger"
 	"context"
 	"crypto/rsa"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -18,18 +21,51 @@ import (
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
-func New(logger logger.Logger, cfg *config.Config, privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) *echo.Echo {
+func New(logger logger.Logger, cfg *config.Config, privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey, clients *client.Registry) (*echo.Echo, error) {
 	e := echo.New()
+	extractor, err := ipExtractor(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	e.IPExtractor = extractor
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
-	SetupRoutes(e, cfg, logger, privateKey, publicKey)
-	return e
+	if err := SetupRoutes(e, cfg, logger, privateKey, publicKey, clients); err != nil {
+		return nil, err
+	}
+	return e, nil
 }
 
-func Start(server *echo.Echo, logger logger.Logger, port int) *http.Server {
-	httpServer := &http.Server{
-		Addr:    fmt.Sprintf(":%d", port),
-		Handler: server,
+// ipExtractor reads the client IP from X-Forwarded-For only when the request comes
+// from one of the trusted proxies, and from the connection otherwise.
+func ipExtractor(trustedProxies string) (echo.IPExtractor, error) {
+	if strings.TrimSpace(trustedProxies) == "" {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	opts := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range strings.Split(trustedProxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		// a single address is a range of one
+		cidr := proxy
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		opts = append(opts, echo.TrustIPRange(ipNet))
 	}
+	return echo.ExtractIPFromXFFHeader(opts...), nil
+}
+
+func Start(server *echo.Echo, logger logger.Logger, port int) *http.Server {
+	httpServer := server.Server
+	httpServer.Addr = fmt.Sprintf(":%d", port)
 
 	go func() {
 		logger.Info(context.Background(), fmt.Sprintf("Starting server on port :%d", port))
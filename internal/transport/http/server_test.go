@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPExtractor(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies string
+		remote  string
+		want    string
+	}{
+		{"no proxies ignore the header", "", "203.0.113.7:5000", "203.0.113.7"},
+		{"loopback is not trusted by default", "", "127.0.0.1:5000", "127.0.0.1"},
+		{"trusted range", "10.0.0.0/8", "10.1.2.3:5000", "198.51.100.1"},
+		{"trusted address", "192.0.2.10, 10.0.0.0/8", "192.0.2.10:5000", "198.51.100.1"},
+		{"untrusted proxy", "10.0.0.0/8", "192.0.2.10:5000", "192.0.2.10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extract, err := ipExtractor(tt.proxies)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remote
+			req.Header.Set("X-Forwarded-For", "198.51.100.1")
+			if got := extract(req); got != tt.want {
+				t.Fatalf("client IP = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ipExtractor("10.0.0.0/33"); err == nil {
+		t.Fatal("ipExtractor() accepted an invalid range")
+	}
+	if _, err := ipExtractor("proxy.local"); err == nil {
+		t.Fatal("ipExtractor() accepted a host name")
+	}
+}
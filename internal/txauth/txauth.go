@@ -0,0 +1,297 @@
+package txauth
+
+import (
+	"TON/internal/chain"
+	"TON/internal/gating"
+	"TON/pkg/logger"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrDisabled is returned when transaction mode is requested but not enabled.
+	ErrDisabled = errors.New("transaction mode is not enabled")
+	ErrNotFound = errors.New("transaction challenge not found")
+	ErrPending  = errors.New("transaction challenge is not approved yet")
+	ErrExpired  = errors.New("transaction challenge expired")
+	ErrUsed     = errors.New("transaction challenge already used")
+	// ErrTooMany is returned when the limit of pending challenges is reached.
+	ErrTooMany = errors.New("too many pending transaction challenges")
+)
+
+// CommentPrefix starts the comment of every login transfer.
+const CommentPrefix = "ton-oauth-"
+
+// skew is how much earlier than its challenge a transfer may be dated, for clocks that
+// run ahead of the chain.
+const skew = time.Minute
+
+type Status string
+
+const (
+	Pending  Status = "pending"
+	Approved Status = "approved"
+	Expired  Status = "expired"
+	Used     Status = "used"
+)
+
+type Config struct {
+	// Network of challenges of clients not bound to one.
+	Network chain.Network
+	// Address the login transfers are sent to, in the raw form.
+	Address string
+	// Amount is the least value of a login transfer in nanotons.
+	Amount int64
+	// TTL is how long a challenge waits for its transfer, and how long an approved
+	// challenge can be exchanged for a token.
+	TTL        time.Duration
+	MaxPending int
+}
+
+// Challenge is a login waiting for a transfer of at least Amount with Comment to Address.
+// The ID is only known to the client; the comment is public once the transfer is on chain.
+type Challenge struct {
+	ID        string
+	ClientID  string
+	Scope     string
+	Network   chain.Network
+	Address   string
+	Comment   string
+	Amount    int64
+	Status    Status
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// Wallet that sent the transfer and the hash of the transaction, set on approval.
+	Wallet     string
+	TxHash     string
+	ApprovedAt time.Time
+}
+
+type entry struct {
+	Challenge
+	// done is closed when the challenge leaves the pending state.
+	done chan struct{}
+}
+
+// Watcher keeps the pending challenges in memory and approves them when the chain
+// provider of their network reports the matching transfer.
+type Watcher struct {
+	cfg       Config
+	log       logger.Logger
+	providers chain.Providers
+	gate      *gating.Evaluator
+
+	mu         sync.Mutex
+	challenges map[string]*entry
+	// comments maps the comments of pending challenges to their ids.
+	comments map[string]string
+}
+
+func NewWatcher(cfg Config, log logger.Logger, providers chain.Providers, gate *gating.Evaluator) *Watcher {
+	return &Watcher{
+		cfg:        cfg,
+		log:        log,
+		providers:  providers,
+		gate:       gate,
+		challenges: make(map[string]*entry),
+		comments:   make(map[string]string),
+	}
+}
+
+// Start creates a challenge of the client on the network, the default one when empty.
+func (w *Watcher) Start(clientID string, network chain.Network, scope string) (*Challenge, error) {
+	if network == "" {
+		network = w.cfg.Network
+	}
+	if _, err := w.providers.Get(network); err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	code := make([]byte, 10)
+	if _, err := rand.Read(code); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	e := &entry{
+		Challenge: Challenge{
+			ID:        base64.RawURLEncoding.EncodeToString(id),
+			ClientID:  clientID,
+			Scope:     scope,
+			Network:   network,
+			Address:   w.cfg.Address,
+			Comment:   CommentPrefix + strings.ToLower(base32.StdEncoding.EncodeToString(code)),
+			Amount:    w.cfg.Amount,
+			Status:    Pending,
+			CreatedAt: now,
+			ExpiresAt: now.Add(w.cfg.TTL),
+		},
+		done: make(chan struct{}),
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cfg.MaxPending > 0 && len(w.comments) >= w.cfg.MaxPending {
+		return nil, ErrTooMany
+	}
+	w.challenges[e.ID] = e
+	w.comments[e.Comment] = e.ID
+
+	c := e.Challenge
+	return &c, nil
+}
+
+// Get returns the challenge with the id.
+func (w *Watcher) Get(id string) (*Challenge, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	e, ok := w.challenges[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	c := e.Challenge
+	return &c, nil
+}
+
+// Wait returns the challenge once it is no longer pending, or as it is when ctx is done first.
+func (w *Watcher) Wait(ctx context.Context, id string) (*Challenge, error) {
+	w.mu.Lock()
+	e, ok := w.challenges[id]
+	w.mu.Unlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+	}
+	return w.Get(id)
+}
+
+// Consume marks an approved challenge as used and returns it. A challenge is exchanged
+// for a token once.
+func (w *Watcher) Consume(id string) (*Challenge, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	e, ok := w.challenges[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	switch e.Status {
+	case Pending:
+		return nil, ErrPending
+	case Expired:
+		return nil, ErrExpired
+	case Used:
+		return nil, ErrUsed
+	}
+	if time.Now().After(e.ExpiresAt) {
+		return nil, ErrExpired
+	}
+	e.Status = Used
+	c := e.Challenge
+	return &c, nil
+}
+
+// Run expires challenges and looks for the transfers of the pending ones every interval
+// until stop is closed.
+func (w *Watcher) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			w.expire(now)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			w.poll(ctx, interval)
+			cancel()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// expire ends pending challenges past their expiry and forgets the others an expiry later.
+func (w *Watcher) expire(now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for id, e := range w.challenges {
+		if now.Before(e.ExpiresAt) {
+			continue
+		}
+		if e.Status == Pending {
+			e.Status = Expired
+			delete(w.comments, e.Comment)
+			close(e.done)
+		} else if now.Sub(e.ExpiresAt) > w.cfg.TTL {
+			delete(w.challenges, id)
+		}
+	}
+}
+
+// poll reads the transfers to the login address on every network with pending challenges
+// back to the oldest of them.
+func (w *Watcher) poll(ctx context.Context, interval time.Duration) {
+	w.mu.Lock()
+	since := make(map[chain.Network]time.Time)
+	for _, id := range w.comments {
+		e := w.challenges[id]
+		if t, ok := since[e.Network]; !ok || e.CreatedAt.Before(t) {
+			since[e.Network] = e.CreatedAt
+		}
+	}
+	w.mu.Unlock()
+
+	for network, t := range since {
+		provider, err := w.providers.Get(network)
+		if err != nil {
+			continue
+		}
+		txs, _, err := w.gate.Payments(ctx, provider, network, w.cfg.Address, t.Add(-skew), interval/2)
+		if err != nil {
+			w.log.Error(ctx, "Failed to read login transfers on "+network.Name()+": "+err.Error())
+			continue
+		}
+		for _, tx := range txs {
+			w.approve(network, tx)
+		}
+	}
+}
+
+// approve approves the pending challenge the transfer is for, if any.
+func (w *Watcher) approve(network chain.Network, tx chain.Transaction) {
+	if tx.Jetton != nil || tx.From == "" {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id, ok := w.comments[strings.ToLower(strings.TrimSpace(tx.Comment))]
+	if !ok {
+		return
+	}
+	e := w.challenges[id]
+	if e.Network != network || tx.Value < e.Amount || tx.Time.Before(e.CreatedAt.Add(-skew)) {
+		return
+	}
+
+	now := time.Now()
+	e.Status, e.Wallet, e.TxHash, e.ApprovedAt = Approved, tx.From, tx.Hash, now
+	e.ExpiresAt = now.Add(w.cfg.TTL)
+	delete(w.comments, e.Comment)
+	close(e.done)
+	w.log.Info(context.Background(), fmt.Sprintf("Transaction challenge of client %s approved by %s in %s", e.ClientID, e.Wallet, e.TxHash))
+}
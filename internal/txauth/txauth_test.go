@@ -0,0 +1,129 @@
+package txauth
+
+import (
+	"TON/internal/chain"
+	"TON/internal/gating"
+	"TON/pkg/logger"
+	"TON/pkg/tonwallet"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	loginAddress = "0:960ab627408d5472d9d125b667cbe00ce17eeaa44e9dc6a86e93cdfef2c480d5"
+	sender       = "0:83dfd552e63729b472fcbcc8c45ebcc6691702558b68ec7527e1ba403a0f31a8"
+)
+
+func newWatcher(t *testing.T, maxPending int) (*Watcher, *chain.FixtureProvider) {
+	t.Helper()
+	gate, err := gating.NewEvaluator(time.Minute, time.Minute, 100, time.Minute, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture := chain.NewFixtureProvider(tonwallet.Options{})
+	w := NewWatcher(Config{
+		Network:    chain.Mainnet,
+		Address:    loginAddress,
+		Amount:     10_000_000,
+		TTL:        10 * time.Minute,
+		MaxPending: maxPending,
+	}, logger.New("test"), chain.Providers{chain.Mainnet: fixture}, gate)
+	return w, fixture
+}
+
+func TestWatcherApproves(t *testing.T) {
+	tests := []struct {
+		name     string
+		transfer func(ch *Challenge) chain.Transaction
+		approved bool
+	}{
+		{"matching transfer", func(ch *Challenge) chain.Transaction {
+			return chain.Transaction{From: sender, Value: ch.Amount, Comment: " " + strings.ToUpper(ch.Comment) + " "}
+		}, true},
+		{"amount too small", func(ch *Challenge) chain.Transaction {
+			return chain.Transaction{From: sender, Value: ch.Amount - 1, Comment: ch.Comment}
+		}, false},
+		{"other comment", func(ch *Challenge) chain.Transaction {
+			return chain.Transaction{From: sender, Value: ch.Amount, Comment: CommentPrefix + "other"}
+		}, false},
+		{"older than the challenge", func(ch *Challenge) chain.Transaction {
+			return chain.Transaction{From: sender, Value: ch.Amount, Comment: ch.Comment, Time: ch.CreatedAt.Add(-2 * skew)}
+		}, false},
+		{"jetton notification", func(ch *Challenge) chain.Transaction {
+			return chain.Transaction{From: sender, Value: ch.Amount, Comment: ch.Comment, Jetton: &chain.JettonTransfer{Sender: sender, Comment: ch.Comment}}
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, fixture := newWatcher(t, 0)
+			ch, err := w.Start("app", "", "openid")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tx := tt.transfer(ch)
+			tx.Hash, tx.LT = "abcd", 1
+			if tx.Time.IsZero() {
+				tx.Time = time.Now()
+			}
+			fixture.AddTransaction(loginAddress, tx)
+			w.poll(context.Background(), time.Second)
+
+			got, err := w.Get(ch.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.approved {
+				if got.Status != Pending {
+					t.Fatalf("status = %s, want %s", got.Status, Pending)
+				}
+				if _, err := w.Consume(ch.ID); !errors.Is(err, ErrPending) {
+					t.Fatalf("Consume() error = %v, want %v", err, ErrPending)
+				}
+				return
+			}
+
+			if got.Status != Approved || got.Wallet != sender || got.TxHash != "abcd" {
+				t.Fatalf("challenge = %s by %s in %s, want approved by %s", got.Status, got.Wallet, got.TxHash, sender)
+			}
+			if _, err := w.Consume(ch.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Consume(ch.ID); !errors.Is(err, ErrUsed) {
+				t.Fatalf("second Consume() error = %v, want %v", err, ErrUsed)
+			}
+		})
+	}
+}
+
+func TestWatcherExpires(t *testing.T) {
+	w, _ := newWatcher(t, 1)
+	ch, err := w.Start("app", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Start("app", "", ""); !errors.Is(err, ErrTooMany) {
+		t.Fatalf("Start() over the limit error = %v, want %v", err, ErrTooMany)
+	}
+	if _, err := w.Start("app", chain.Testnet, ""); err == nil {
+		t.Fatal("Start() accepted a network without provider")
+	}
+
+	w.expire(ch.ExpiresAt)
+	got, err := w.Wait(context.Background(), ch.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != Expired {
+		t.Fatalf("status = %s, want %s", got.Status, Expired)
+	}
+	if _, err := w.Consume(ch.ID); !errors.Is(err, ErrExpired) {
+		t.Fatalf("Consume() error = %v, want %v", err, ErrExpired)
+	}
+	if _, err := w.Start("app", "", ""); err != nil {
+		t.Fatalf("Start() after the expiry error = %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"TON/internal/audit"
+	"TON/internal/dto"
+)
+
+type AuditUseCase interface {
+	// Recent returns up to limit of the latest events whose action starts with action.
+	Recent(limit int, action string) *dto.AuditResponseDTO
+}
+
+type AuditUseCaseImpl struct {
+	audit *audit.Log
+}
+
+func NewAuditUseCase(auditLog *audit.Log) AuditUseCase {
+	return &AuditUseCaseImpl{audit: auditLog}
+}
+
+func (u *AuditUseCaseImpl) Recent(limit int, action string) *dto.AuditResponseDTO {
+	events := u.audit.Recent(limit, action)
+	resp := &dto.AuditResponseDTO{Events: make([]dto.AuditEventDTO, 0, len(events))}
+	for _, e := range events {
+		resp.Events = append(resp.Events, dto.AuditEventDTO{
+			Time:     e.Time,
+			Actor:    e.Actor,
+			IP:       e.IP,
+			Action:   e.Action,
+			Target:   e.Target,
+			ClientID: e.ClientID,
+			Details:  e.Details,
+		})
+	}
+	return resp
+}
@@ -1,11 +1,18 @@
 package usecase
 
 import (
+	"TON/internal/chain"
+	"TON/internal/client"
 	"TON/internal/dto"
+	"TON/internal/pow"
+	"TON/internal/txauth"
+	tonaddr "TON/pkg/address"
 	"TON/pkg/logger"
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -14,24 +21,50 @@ type AuthorizeUseCase interface {
 }
 
 type AuthorizeUseCaseImpl struct {
-	TTL    int
-	logger logger.Logger
+	TTL     int
+	logger  logger.Logger
+	clients *client.Registry
+	guard   *pow.Guard
+	watcher *txauth.Watcher
 }
 
-func NewAuthorizeUseCase(ttl int, log logger.Logger) AuthorizeUseCase {
+func NewAuthorizeUseCase(ttl int, log logger.Logger, clients *client.Registry, guard *pow.Guard, watcher *txauth.Watcher) AuthorizeUseCase {
 	return &AuthorizeUseCaseImpl{
-		TTL:    ttl,
-		logger: log,
+		TTL:     ttl,
+		logger:  log,
+		clients: clients,
+		guard:   guard,
+		watcher: watcher,
 	}
 }
 
 func (u *AuthorizeUseCaseImpl) Authorize(req dto.AuthorizeRequestDTO) (*dto.AuthorizeResponseDTO, error) {
 	ctx := context.Background()
 
-	clientID, err := generateRandomString(16)
-	if err != nil {
-		u.logger.Error(ctx, "Failed to generate clientID: "+err.Error())
-		return nil, err
+	var network chain.Network
+	clientID := req.ClientID
+	if clientID != "" {
+		c, err := u.clients.Get(clientID)
+		if err != nil {
+			u.logger.Error(ctx, "Unknown client: "+clientID)
+			return nil, err
+		}
+		if !c.AllowsRedirect(req.RedirectURI) {
+			u.logger.Error(ctx, "Redirect URI is not registered for client "+clientID)
+			return nil, client.ErrRedirectNotAllowed
+		}
+		network = chain.Network(c.Network)
+	} else {
+		anonymousID, err := generateRandomString(16)
+		if err != nil {
+			u.logger.Error(ctx, "Failed to generate clientID: "+err.Error())
+			return nil, err
+		}
+		clientID = anonymousID
+	}
+
+	if req.Mode == "transaction" {
+		return u.authorizeTransaction(ctx, req, clientID, network)
 	}
 
 	nonce, err := generateRandomString(32)
@@ -47,11 +80,57 @@ func (u *AuthorizeUseCaseImpl) Authorize(req dto.AuthorizeRequestDTO) (*dto.Auth
 		ExpiresAt:   time.Now().Add(time.Duration(u.TTL) * time.Second),
 	}
 
-	u.logger.Info(ctx, "Generated anonymous challenge")
+	if u.guard != nil {
+		puzzle, err := u.guard.Issue(req.IP, time.Duration(u.TTL)*time.Second)
+		if err != nil {
+			u.logger.Error(ctx, "Failed to generate proof-of-work puzzle: "+err.Error())
+			return nil, err
+		}
+		challenge.PoW = &dto.PoWChallengeDTO{
+			Challenge:  puzzle.Challenge,
+			Difficulty: puzzle.Difficulty,
+			Algorithm:  pow.Algorithm,
+			ExpiresAt:  puzzle.ExpiresAt,
+		}
+	}
+
+	u.logger.Info(ctx, "Generated challenge for client "+clientID)
 
 	return challenge, nil
 }
 
+// authorizeTransaction starts a login approved by a transfer from the wallet instead of a
+// signature. The challenge is the secret the client polls the result with.
+func (u *AuthorizeUseCaseImpl) authorizeTransaction(ctx context.Context, req dto.AuthorizeRequestDTO, clientID string, network chain.Network) (*dto.AuthorizeResponseDTO, error) {
+	if u.watcher == nil {
+		return nil, txauth.ErrDisabled
+	}
+
+	ch, err := u.watcher.Start(clientID, network, req.Scope)
+	if err != nil {
+		u.logger.Error(ctx, "Failed to start transaction challenge: "+err.Error())
+		return nil, err
+	}
+
+	addr := renderAddress(ch.Address, tonaddr.FormatBounceable, ch.Network)
+	amount := strconv.FormatInt(ch.Amount, 10)
+	u.logger.Info(ctx, "Generated transaction challenge for client "+clientID)
+
+	return &dto.AuthorizeResponseDTO{
+		ClientID:    clientID,
+		RedirectURI: req.RedirectURI,
+		Challenge:   ch.ID,
+		ExpiresAt:   ch.ExpiresAt,
+		Transaction: &dto.TransactionChallengeDTO{
+			Address: addr,
+			Comment: ch.Comment,
+			Amount:  amount,
+			Link:    "ton://transfer/" + addr + "?" + url.Values{"amount": {amount}, "text": {ch.Comment}}.Encode(),
+			Network: string(ch.Network),
+		},
+	}, nil
+}
+
 func generateRandomString(n int) (string, error) {
 	b := make([]byte, n)
 	_, err := rand.Read(b)
@@ -2,44 +2,104 @@ package usecase
 
 import (
 	"TON/internal/dto"
+	"TON/internal/identity"
+	"TON/internal/multisig"
+	"TON/internal/txauth"
 	"crypto/rsa"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// AccessTokenType is the typ header of access tokens (RFC 9068), which keeps them apart
+// from attestations and credentials signed with the same key.
+const AccessTokenType = "at+jwt"
+
 type TokenUseCase interface {
 	CreateToken(req dto.TokenRequestDTO) (*dto.TokenResponseDTO, error)
+	// CreateTransactionToken exchanges an approved transaction challenge for a token, once.
+	CreateTransactionToken(req dto.TransactionTokenRequestDTO) (*dto.TokenResponseDTO, error)
+	// CreateMultisigToken exchanges a multisig session signed by enough owners for a
+	// token of the contract, once.
+	CreateMultisigToken(req dto.MultisigTokenRequestDTO) (*dto.TokenResponseDTO, error)
 }
 
 type TokenUseCaseImpl struct {
-	Issuer  string
-	TTL     time.Duration
-	PrivKey *rsa.PrivateKey
+	Issuer    string
+	TTL       time.Duration
+	PrivKey   *rsa.PrivateKey
+	verify    VerifyUseCase
+	watcher   *txauth.Watcher
+	collector *multisig.Collector
 }
 
-func NewTokenUseCase(issuer string, ttl time.Duration, priv *rsa.PrivateKey) TokenUseCase {
+func NewTokenUseCase(issuer string, ttl time.Duration, priv *rsa.PrivateKey, verify VerifyUseCase, watcher *txauth.Watcher, collector *multisig.Collector) TokenUseCase {
 	return &TokenUseCaseImpl{
-		Issuer:  issuer,
-		TTL:     ttl,
-		PrivKey: priv,
+		Issuer:    issuer,
+		TTL:       ttl,
+		PrivKey:   priv,
+		verify:    verify,
+		watcher:   watcher,
+		collector: collector,
 	}
 }
 
 func (u *TokenUseCaseImpl) CreateToken(req dto.TokenRequestDTO) (*dto.TokenResponseDTO, error) {
+	id, err := u.verify.Identify(dto.VerifyRequestDTO(req))
+	if err != nil {
+		return nil, err
+	}
+	return u.sign(id)
+}
+
+func (u *TokenUseCaseImpl) CreateTransactionToken(req dto.TransactionTokenRequestDTO) (*dto.TokenResponseDTO, error) {
+	if u.watcher == nil {
+		return nil, txauth.ErrDisabled
+	}
+	ch, err := u.watcher.Consume(req.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := u.verify.IdentifyTransfer(ch, req.IP)
+	if err != nil {
+		return nil, err
+	}
+	return u.sign(id)
+}
+
+func (u *TokenUseCaseImpl) CreateMultisigToken(req dto.MultisigTokenRequestDTO) (*dto.TokenResponseDTO, error) {
+	s, err := u.collector.Consume(req.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := u.verify.IdentifyMultisig(s, req.IP)
+	if err != nil {
+		return nil, err
+	}
+	return u.sign(id)
+}
+
+// sign issues a token with the claims of the identity.
+func (u *TokenUseCaseImpl) sign(id *identity.Identity) (*dto.TokenResponseDTO, error) {
 	tokenID, err := generateRandomString(16)
 	if err != nil {
 		return nil, err
 	}
 
-	claims := jwt.MapClaims{
-		"jti": tokenID,
-		"iss": u.Issuer,
-		"exp": time.Now().Add(u.TTL).Unix(),
-		"iat": time.Now().Unix(),
+	// registered claims last, so no claim of the identity replaces them
+	claims := jwt.MapClaims(id.Claims())
+	claims["jti"] = tokenID
+	claims["iss"] = u.Issuer
+	claims["exp"] = time.Now().Add(u.TTL).Unix()
+	claims["iat"] = time.Now().Unix()
+	if id.ClientID != "" {
+		claims["aud"] = id.ClientID
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["typ"] = AccessTokenType
 
 	tokenStr, err := token.SignedString(u.PrivKey)
 	if err != nil {
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"TON/internal/audit"
+	"TON/internal/chain"
+	"TON/internal/credential"
+	"TON/internal/dto"
+	"TON/internal/identity"
+	"TON/pkg/did"
+	"TON/pkg/logger"
+	"context"
+	"crypto/rsa"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// statusListTTL is how long a signed status list is valid, which bounds how long a
+// revocation can be hidden by replaying an older list.
+const statusListTTL = 10 * time.Minute
+
+// typ headers of credentials and status lists, which keep them apart from access tokens.
+const (
+	CredentialType = "vc+jwt"
+	StatusListType = "statuslist+jwt"
+)
+
+type CredentialUseCase interface {
+	// Issue verifies the wallet like the token request and issues a verifiable
+	// credential attesting its control.
+	Issue(req dto.CredentialRequestDTO) (*dto.CredentialResponseDTO, error)
+	// StatusList returns the signed StatusList2021 credential with the revoked credentials.
+	StatusList() (string, error)
+	ListCredentials(addr string) (*dto.CredentialsResponseDTO, error)
+	Revoke(id, reason, actor, ip string) error
+}
+
+type CredentialUseCaseImpl struct {
+	// Issuer is the public URL of the service, the issuer of the credentials.
+	Issuer  string
+	KeyID   string
+	TTL     time.Duration
+	PrivKey *rsa.PrivateKey
+	log     logger.Logger
+	verify  VerifyUseCase
+	store   *credential.Store
+	audit   *audit.Log
+}
+
+func NewCredentialUseCase(issuer, keyID string, ttl time.Duration, priv *rsa.PrivateKey, log logger.Logger, verify VerifyUseCase, store *credential.Store, auditLog *audit.Log) CredentialUseCase {
+	return &CredentialUseCaseImpl{
+		Issuer:  strings.TrimRight(issuer, "/"),
+		KeyID:   keyID,
+		TTL:     ttl,
+		PrivKey: priv,
+		log:     log,
+		verify:  verify,
+		store:   store,
+		audit:   auditLog,
+	}
+}
+
+func (u *CredentialUseCaseImpl) Issue(req dto.CredentialRequestDTO) (*dto.CredentialResponseDTO, error) {
+	ctx := context.Background()
+
+	id, err := u.verify.Identify(req.VerifyRequestDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	var subject string
+	if req.Subject == "key" {
+		subject, err = did.Key(id.PublicKey)
+	} else {
+		subject, err = did.TON(id.Address, id.Network == chain.Testnet)
+	}
+	if err != nil {
+		return nil, err
+	}
+	credentialID, err := credential.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	r, err := u.store.Issue(credential.Record{
+		ID:        credentialID,
+		Subject:   subject,
+		Address:   id.Address,
+		ClientID:  id.ClientID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(u.TTL),
+	})
+	if err != nil {
+		u.log.Error(ctx, "Failed to store credential: "+err.Error())
+		return nil, err
+	}
+
+	claims := credential.Claims(u.Issuer, u.statusListURL(), r, subjectFacts(id, req.Facts))
+	token, err := u.sign(claims, CredentialType)
+	if err != nil {
+		return nil, err
+	}
+
+	u.record(ctx, audit.Event{
+		Actor:    "credentials",
+		IP:       req.IP,
+		Action:   "credential.issue",
+		Target:   r.Address,
+		ClientID: r.ClientID,
+		Details:  map[string]interface{}{"id": r.ID, "subject": r.Subject},
+	})
+	u.log.Info(ctx, "Issued credential "+r.ID+" to "+subject)
+
+	return &dto.CredentialResponseDTO{
+		Credential:      token,
+		ID:              r.ID,
+		Subject:         r.Subject,
+		StatusListIndex: r.Index,
+		ExpiresAt:       r.ExpiresAt,
+	}, nil
+}
+
+func (u *CredentialUseCaseImpl) StatusList() (string, error) {
+	encoded, err := u.store.EncodedList()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	return u.sign(credential.StatusListClaims(u.Issuer, u.statusListURL(), encoded, now, now.Add(statusListTTL)), StatusListType)
+}
+
+func (u *CredentialUseCaseImpl) ListCredentials(addr string) (*dto.CredentialsResponseDTO, error) {
+	if addr != "" {
+		raw, err := chain.ParseAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		addr = raw.StringRaw()
+	}
+
+	records := u.store.List(addr)
+	resp := &dto.CredentialsResponseDTO{Credentials: make([]dto.CredentialRecordDTO, 0, len(records))}
+	for _, r := range records {
+		resp.Credentials = append(resp.Credentials, dto.CredentialRecordDTO{
+			ID:              r.ID,
+			StatusListIndex: r.Index,
+			Subject:         r.Subject,
+			Address:         r.Address,
+			ClientID:        r.ClientID,
+			IssuedAt:        r.IssuedAt,
+			ExpiresAt:       r.ExpiresAt,
+			RevokedAt:       r.RevokedAt,
+			Reason:          r.Reason,
+		})
+	}
+	return resp, nil
+}
+
+func (u *CredentialUseCaseImpl) Revoke(id, reason, actor, ip string) error {
+	ctx := context.Background()
+
+	r, err := u.store.Revoke(id, reason)
+	if err != nil {
+		return err
+	}
+
+	details := map[string]interface{}{"id": r.ID, "subject": r.Subject}
+	if reason != "" {
+		details["reason"] = reason
+	}
+	u.record(ctx, audit.Event{
+		Actor:    actor,
+		IP:       ip,
+		Action:   "credential.revoke",
+		Target:   r.Address,
+		ClientID: r.ClientID,
+		Details:  details,
+	})
+	return nil
+}
+
+func (u *CredentialUseCaseImpl) statusListURL() string {
+	return u.Issuer + "/oauth/credentials/status"
+}
+
+// sign signs the claims with the credential key, named in the kid header so verifiers
+// find it in the JWKS.
+func (u *CredentialUseCaseImpl) sign(claims map[string]interface{}, typ string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims(claims))
+	token.Header["kid"] = u.KeyID
+	token.Header["typ"] = typ
+	return token.SignedString(u.PrivKey)
+}
+
+// record writes the event to the audit trail. The change is already stored, so a
+// failure is only logged.
+func (u *CredentialUseCaseImpl) record(ctx context.Context, e audit.Event) {
+	if err := u.audit.Record(e); err != nil {
+		u.log.Error(ctx, "Failed to record audit event "+e.Action+": "+err.Error())
+	}
+}
+
+// subjectFacts describes the wallet in the credential subject, with the requested facts
+// it has.
+func subjectFacts(id *identity.Identity, facts []string) map[string]interface{} {
+	subject := map[string]interface{}{
+		"address": id.Address,
+		"network": string(id.Network),
+	}
+	if slices.Contains(facts, "dns") && id.Name != "" {
+		subject["dnsName"] = id.Name
+	}
+	if collections, ok := id.Extra["nft_collections"]; ok && slices.Contains(facts, "nft") {
+		subject["nftCollections"] = collections
+	}
+	return subject
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"TON/internal/chain"
+	"TON/internal/dto"
+	"TON/internal/tondns"
+	"TON/pkg/did"
+	"TON/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	// DIDContentType is the media type of DID documents.
+	DIDContentType = "application/did+ld+json"
+	// DIDResolutionContext is the JSON-LD context of DID resolution results.
+	DIDResolutionContext = "https://w3id.org/did-resolution/v1"
+)
+
+type DIDUseCase interface {
+	// Resolve builds the DID document of a did:ton identifier from the chain.
+	Resolve(id string) (*dto.DIDResolutionDTO, error)
+}
+
+type DIDUseCaseImpl struct {
+	log      logger.Logger
+	resolver *did.Resolver
+}
+
+func NewDIDUseCase(log logger.Logger, providers chain.Providers, names *tondns.Resolver) DIDUseCase {
+	resolver := &did.Resolver{}
+	if provider, err := providers.Get(chain.Mainnet); err == nil {
+		resolver.Mainnet = &didChain{provider: provider, network: chain.Mainnet, names: names}
+	}
+	if provider, err := providers.Get(chain.Testnet); err == nil {
+		resolver.Testnet = &didChain{provider: provider, network: chain.Testnet, names: names}
+	}
+
+	return &DIDUseCaseImpl{
+		log:      log,
+		resolver: resolver,
+	}
+}
+
+func (u *DIDUseCaseImpl) Resolve(id string) (*dto.DIDResolutionDTO, error) {
+	ctx := context.Background()
+
+	doc, meta, err := u.resolver.Resolve(ctx, id)
+	if err != nil {
+		if !errors.Is(err, did.ErrInvalidDID) && !errors.Is(err, did.ErrNotFound) && !errors.Is(err, did.ErrMethodNotSupported) {
+			u.log.Error(ctx, "Failed to resolve "+id+": "+err.Error())
+		}
+		return nil, err
+	}
+
+	return &dto.DIDResolutionDTO{
+		Context:               DIDResolutionContext,
+		DIDDocument:           doc,
+		DIDResolutionMetadata: dto.DIDResolutionMetadataDTO{ContentType: DIDContentType},
+		DIDDocumentMetadata:   *meta,
+	}, nil
+}
+
+// didChain reads did:ton documents from the chain provider of a network.
+type didChain struct {
+	provider chain.ChainProvider
+	network  chain.Network
+	names    *tondns.Resolver
+}
+
+func (c *didChain) Wallet(ctx context.Context, addr string) (*did.Wallet, error) {
+	acc, err := c.provider.GetAccount(ctx, addr)
+	if errors.Is(err, chain.ErrNotFound) {
+		return &did.Wallet{Status: string(chain.StatusNonexist)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet: %w", err)
+	}
+
+	w := &did.Wallet{Status: string(acc.Status)}
+	switch acc.Status {
+	case chain.StatusNonexist:
+		return w, nil
+	case chain.StatusActive:
+		// contracts that are no wallets have no key but may still own a name
+		key, version, err := chain.WalletPublicKey(ctx, c.provider, acc)
+		if err != nil && !errors.Is(err, chain.ErrUnknownWallet) {
+			return nil, fmt.Errorf("failed to read wallet public key: %w", err)
+		}
+		w.PublicKey, w.Version = key, string(version)
+	}
+
+	w.Name, err = c.names.Name(ctx, c.provider, c.network, acc.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve dns name: %w", err)
+	}
+	return w, nil
+}
+
+func (c *didChain) Records(ctx context.Context, domain string) (*did.Records, error) {
+	records, err := c.provider.DNSRecords(ctx, domain)
+	if errors.Is(err, chain.ErrNotFound) || errors.Is(err, chain.ErrNotSupported) {
+		return &did.Records{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dns records: %w", err)
+	}
+	return &did.Records{Sites: records.Sites, Storage: records.Storage}, nil
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"TON/internal/chain"
+	"TON/internal/dto"
+	tonaddr "TON/pkg/address"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrNotFixture is returned when the chain provider of the network is not the fixture.
+var ErrNotFixture = errors.New("network is not served by the fixture provider")
+
+type FixtureUseCase interface {
+	// AddTransaction records an incoming transfer in the fixture, after every other
+	// transaction of the account.
+	AddTransaction(req dto.FixtureTransactionRequestDTO) (*dto.FixtureTransactionResponseDTO, error)
+}
+
+type FixtureUseCaseImpl struct {
+	providers      chain.Providers
+	defaultNetwork chain.Network
+}
+
+func NewFixtureUseCase(providers chain.Providers, defaultNetwork chain.Network) FixtureUseCase {
+	return &FixtureUseCaseImpl{
+		providers:      providers,
+		defaultNetwork: defaultNetwork,
+	}
+}
+
+func (u *FixtureUseCaseImpl) AddTransaction(req dto.FixtureTransactionRequestDTO) (*dto.FixtureTransactionResponseDTO, error) {
+	network, err := selectNetwork(u.defaultNetwork, req.Network, nil)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := u.providers.Get(network)
+	if err != nil {
+		return nil, err
+	}
+	fixture, ok := provider.(*chain.FixtureProvider)
+	if !ok {
+		return nil, ErrNotFixture
+	}
+
+	addr, err := tonaddr.Normalize(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	from, err := tonaddr.Normalize(req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := make([]byte, 32)
+	if _, err := rand.Read(hash); err != nil {
+		return nil, err
+	}
+	tx := chain.Transaction{
+		Hash:    hex.EncodeToString(hash),
+		LT:      1,
+		Time:    time.Now().UTC().Truncate(time.Second),
+		From:    from,
+		Value:   req.Value,
+		Comment: req.Comment,
+	}
+	latest, err := fixture.GetTransactions(context.Background(), addr, nil, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(latest) > 0 {
+		tx.LT = latest[0].LT + 1
+	}
+	fixture.AddTransaction(addr, tx)
+
+	return &dto.FixtureTransactionResponseDTO{
+		Hash: tx.Hash,
+		LT:   tx.LT,
+		Time: tx.Time,
+	}, nil
+}
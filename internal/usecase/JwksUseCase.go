@@ -15,31 +15,44 @@ type JWKSUseCase interface {
 type JWKSUseCaseImpl struct {
 	KeyID string
 	Pub   *rsa.PublicKey
+	// CredentialKeyID and CredentialPub name the key credentials are signed with, nil
+	// when they are signed with the service key.
+	CredentialKeyID string
+	CredentialPub   *rsa.PublicKey
 }
 
-func NewJWKSUseCase(keyID string, pub *rsa.PublicKey) JWKSUseCase {
+func NewJWKSUseCase(keyID string, pub *rsa.PublicKey, credentialKeyID string, credentialPub *rsa.PublicKey) JWKSUseCase {
 	return &JWKSUseCaseImpl{
-		KeyID: keyID,
-		Pub:   pub,
+		KeyID:           keyID,
+		Pub:             pub,
+		CredentialKeyID: credentialKeyID,
+		CredentialPub:   credentialPub,
 	}
 }
 
 func (u *JWKSUseCaseImpl) GetJWKS() (*dto.JWKSResponseDTO, error) {
-	n := base64.RawURLEncoding.EncodeToString(u.Pub.N.Bytes())
-	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(u.Pub.E)).Bytes())
+	keys := []dto.JWK{jwk(u.KeyID, u.Pub)}
+	if u.CredentialPub != nil {
+		keys = append(keys, jwk(u.CredentialKeyID, u.CredentialPub))
+	}
+
+	return &dto.JWKSResponseDTO{
+		Keys: keys,
+	}, nil
+}
 
-	jwk := dto.JWK{
-		Kid: u.KeyID,
+func jwk(keyID string, pub *rsa.PublicKey) dto.JWK {
+	n := base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+
+	return dto.JWK{
+		Kid: keyID,
 		Kty: "RSA",
 		Alg: "RS256",
 		Use: "sig",
 		N:   n,
 		E:   e,
 	}
-
-	return &dto.JWKSResponseDTO{
-		Keys: []dto.JWK{jwk},
-	}, nil
 }
 
 func (u *JWKSUseCaseImpl) GetPublicKey() *rsa.PublicKey {
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"TON/internal/audit"
+	"TON/internal/client"
+	"TON/internal/dto"
+	"TON/internal/lists"
+	"TON/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidExpiry = errors.New("invalid expiry")
+
+type ListsUseCase interface {
+	AddEntry(kind string, req dto.ListEntryRequestDTO) (*dto.ListEntryDTO, error)
+	RemoveEntry(kind, addr, clientID, actor, ip string) error
+	ListEntries(kind, clientID string) (*dto.ListEntriesResponseDTO, error)
+}
+
+type ListsUseCaseImpl struct {
+	log     logger.Logger
+	store   *lists.Store
+	clients *client.Registry
+	audit   *audit.Log
+}
+
+func NewListsUseCase(log logger.Logger, store *lists.Store, clients *client.Registry, auditLog *audit.Log) ListsUseCase {
+	return &ListsUseCaseImpl{
+		log:     log,
+		store:   store,
+		clients: clients,
+		audit:   auditLog,
+	}
+}
+
+func (u *ListsUseCaseImpl) AddEntry(kind string, req dto.ListEntryRequestDTO) (*dto.ListEntryDTO, error) {
+	ctx := context.Background()
+
+	k, err := lists.ParseKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	if req.ClientID != "" {
+		if _, err := u.clients.Get(req.ClientID); err != nil {
+			return nil, err
+		}
+	}
+
+	expiresAt := req.ExpiresAt
+	if req.TTL != "" {
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("%w: ttl must be a positive duration", ErrInvalidExpiry)
+		}
+		at := time.Now().UTC().Add(ttl)
+		expiresAt = &at
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expires_at is in the past", ErrInvalidExpiry)
+	}
+
+	e, err := u.store.Add(lists.Entry{
+		Kind:      k,
+		Address:   req.Address,
+		ClientID:  req.ClientID,
+		Reason:    req.Reason,
+		ExpiresAt: expiresAt,
+		CreatedBy: req.Actor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]interface{}{"list": string(k)}
+	if e.Reason != "" {
+		details["reason"] = e.Reason
+	}
+	if e.ExpiresAt != nil {
+		details["expiresAt"] = e.ExpiresAt
+	}
+	u.record(ctx, audit.Event{
+		Actor:    req.Actor,
+		IP:       req.IP,
+		Action:   "list.add",
+		Target:   e.Address,
+		ClientID: e.ClientID,
+		Details:  details,
+	})
+
+	resp := listEntryDTO(*e)
+	return &resp, nil
+}
+
+func (u *ListsUseCaseImpl) RemoveEntry(kind, addr, clientID, actor, ip string) error {
+	ctx := context.Background()
+
+	k, err := lists.ParseKind(kind)
+	if err != nil {
+		return err
+	}
+	e, err := u.store.Remove(k, clientID, addr)
+	if err != nil {
+		return err
+	}
+
+	details := map[string]interface{}{"list": string(k)}
+	if e.Reason != "" {
+		details["reason"] = e.Reason
+	}
+	u.record(ctx, audit.Event{
+		Actor:    actor,
+		IP:       ip,
+		Action:   "list.remove",
+		Target:   e.Address,
+		ClientID: e.ClientID,
+		Details:  details,
+	})
+	return nil
+}
+
+func (u *ListsUseCaseImpl) ListEntries(kind, clientID string) (*dto.ListEntriesResponseDTO, error) {
+	k, err := lists.ParseKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := u.store.List(k, clientID)
+	resp := &dto.ListEntriesResponseDTO{Entries: make([]dto.ListEntryDTO, 0, len(entries))}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, listEntryDTO(e))
+	}
+	return resp, nil
+}
+
+// record writes the event to the audit trail. The change is already stored, so a
+// failure is only logged.
+func (u *ListsUseCaseImpl) record(ctx context.Context, e audit.Event) {
+	if err := u.audit.Record(e); err != nil {
+		u.log.Error(ctx, "Failed to record audit event "+e.Action+": "+err.Error())
+	}
+}
+
+func listEntryDTO(e lists.Entry) dto.ListEntryDTO {
+	return dto.ListEntryDTO{
+		Kind:      string(e.Kind),
+		Address:   e.Address,
+		ClientID:  e.ClientID,
+		Reason:    e.Reason,
+		ExpiresAt: e.ExpiresAt,
+		CreatedAt: e.CreatedAt,
+		CreatedBy: e.CreatedBy,
+	}
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"TON/internal/client"
+	"TON/internal/dto"
+	"errors"
+	"strings"
+)
+
+var ErrIconNotFound = errors.New("client has no local icon")
+
+type ManifestUseCase interface {
+	GetManifest(clientID string) (*dto.TonConnectManifestDTO, error)
+	GetIconPath(clientID string) (string, error)
+}
+
+type ManifestUseCaseImpl struct {
+	PublicURL string
+	clients   *client.Registry
+}
+
+func NewManifestUseCase(publicURL string, clients *client.Registry) ManifestUseCase {
+	return &ManifestUseCaseImpl{
+		PublicURL: strings.TrimRight(publicURL, "/"),
+		clients:   clients,
+	}
+}
+
+func (u *ManifestUseCaseImpl) GetManifest(clientID string) (*dto.TonConnectManifestDTO, error) {
+	c, err := u.clients.Get(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	iconURL := c.IconURL
+	if c.IconPath != "" {
+		iconURL = u.PublicURL + "/clients/" + c.ID + "/icon"
+	}
+
+	return &dto.TonConnectManifestDTO{
+		URL:              c.URL,
+		Name:             c.Name,
+		IconURL:          iconURL,
+		TermsOfUseURL:    c.TermsOfUseURL,
+		PrivacyPolicyURL: c.PrivacyPolicyURL,
+	}, nil
+}
+
+func (u *ManifestUseCaseImpl) GetIconPath(clientID string) (string, error) {
+	c, err := u.clients.Get(clientID)
+	if err != nil {
+		return "", err
+	}
+	if c.IconPath == "" {
+		return "", ErrIconNotFound
+	}
+	return c.IconPath, nil
+}
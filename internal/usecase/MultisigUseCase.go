@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"TON/internal/chain"
+	"TON/internal/client"
+	"TON/internal/dto"
+	"TON/internal/multisig"
+	tonaddr "TON/pkg/address"
+	"TON/pkg/logger"
+	"TON/pkg/tonproof"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature is returned for owner signatures that do not verify.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+type MultisigUseCase interface {
+	// Start reads the owners of the multisig contract and opens a session collecting
+	// their signatures.
+	Start(req dto.MultisigStartRequestDTO) (*dto.MultisigSessionDTO, error)
+	Session(challenge string) (*dto.MultisigSessionDTO, error)
+	// Sign verifies and records the signature of an owner.
+	Sign(req dto.MultisigSignRequestDTO) (*dto.MultisigSessionDTO, error)
+}
+
+type MultisigUseCaseImpl struct {
+	// TTL is how old a ton_proof may be.
+	TTL            time.Duration
+	log            logger.Logger
+	providers      chain.Providers
+	defaultNetwork chain.Network
+	clients        *client.Registry
+	collector      *multisig.Collector
+}
+
+func NewMultisigUseCase(ttl time.Duration, log logger.Logger, providers chain.Providers, defaultNetwork chain.Network, clients *client.Registry, collector *multisig.Collector) MultisigUseCase {
+	return &MultisigUseCaseImpl{
+		TTL:            ttl,
+		log:            log,
+		providers:      providers,
+		defaultNetwork: defaultNetwork,
+		clients:        clients,
+		collector:      collector,
+	}
+}
+
+func (u *MultisigUseCaseImpl) Start(req dto.MultisigStartRequestDTO) (*dto.MultisigSessionDTO, error) {
+	ctx := context.Background()
+
+	c, err := lookupClient(u.clients, req.ClientID, false)
+	if err != nil {
+		u.log.Error(ctx, "Unknown client: "+req.ClientID)
+		return nil, err
+	}
+	network, err := selectNetwork(u.defaultNetwork, req.Network, c)
+	if err != nil {
+		u.log.Error(ctx, "Invalid network: "+err.Error())
+		return nil, err
+	}
+	provider, err := u.providers.Get(network)
+	if err != nil {
+		u.log.Error(ctx, "No chain provider for network "+network.Name())
+		return nil, err
+	}
+	addr, err := tonaddr.Normalize(req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := multisig.ReadOwners(ctx, provider, addr)
+	if err != nil {
+		u.log.Error(ctx, "Failed to read owners of multisig "+addr+": "+err.Error())
+		return nil, err
+	}
+
+	s, err := u.collector.Start(req.ClientID, req.Scope, network, addr, owners)
+	if err != nil {
+		u.log.Error(ctx, "Failed to start multisig session: "+err.Error())
+		return nil, err
+	}
+	u.log.Info(ctx, fmt.Sprintf("Started multisig session for %s, %d of %d owners required", addr, owners.Threshold, len(owners.Signers)))
+	return sessionDTO(s), nil
+}
+
+func (u *MultisigUseCaseImpl) Session(challenge string) (*dto.MultisigSessionDTO, error) {
+	s, err := u.collector.Get(challenge)
+	if err != nil {
+		return nil, err
+	}
+	return sessionDTO(s), nil
+}
+
+func (u *MultisigUseCaseImpl) Sign(req dto.MultisigSignRequestDTO) (*dto.MultisigSessionDTO, error) {
+	ctx := context.Background()
+
+	s, err := u.collector.Get(req.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	var addr string
+	if req.Proof != nil {
+		if addr, err = u.verifyProof(ctx, s, req); err != nil {
+			return nil, err
+		}
+	} else if !ed25519.Verify(req.PublicKey, []byte(s.Message), req.Signature) {
+		u.log.Error(ctx, "Invalid owner signature for multisig "+s.Address)
+		return nil, ErrInvalidSignature
+	}
+
+	approved, err := u.collector.Approve(req.Challenge, req.PublicKey, addr)
+	if err != nil {
+		u.log.Error(ctx, "Signature for multisig "+s.Address+" rejected: "+err.Error())
+		return nil, err
+	}
+	u.log.Info(ctx, fmt.Sprintf("Owner signature for multisig %s recorded, %d of %d", s.Address, len(approved.Approvals), s.Owners.Threshold))
+	return sessionDTO(approved), nil
+}
+
+// verifyProof checks a ton_proof of an owner wallet with the session challenge as payload
+// against the manifest domain of the client and returns the wallet address.
+func (u *MultisigUseCaseImpl) verifyProof(ctx context.Context, s *multisig.Session, req dto.MultisigSignRequestDTO) (string, error) {
+	c, err := lookupClient(u.clients, s.ClientID, true)
+	if err != nil || c == nil {
+		u.log.Error(ctx, "ton_proof signature for a session without registered client")
+		return "", fmt.Errorf("%w: ton_proof requires a registered client", client.ErrClientNotFound)
+	}
+	if req.Proof.Payload != s.ID {
+		return "", fmt.Errorf("%w: proof payload is not the session challenge", ErrInvalidSignature)
+	}
+	if !strings.EqualFold(req.Proof.Domain.Value, c.Domain()) {
+		u.log.Error(ctx, fmt.Sprintf("Invalid proof domain: got %s, expected %s", req.Proof.Domain.Value, c.Domain()))
+		return "", fmt.Errorf("%w: proof domain does not match client manifest", ErrInvalidSignature)
+	}
+
+	addr, err := chain.ParseAddress(req.Address)
+	if err != nil {
+		return "", fmt.Errorf("invalid address: %w", err)
+	}
+	err = tonproof.Verify(req.PublicKey, tonproof.Proof{
+		Timestamp:   req.Proof.Timestamp,
+		DomainLen:   req.Proof.Domain.LengthBytes,
+		Domain:      req.Proof.Domain.Value,
+		Payload:     req.Proof.Payload,
+		Signature:   req.Proof.Signature,
+		AddressWC:   addr.Workchain(),
+		AddressHash: addr.Data(),
+	})
+	if err != nil {
+		u.log.Error(ctx, "Invalid proof: "+err.Error())
+		return "", fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	ts := time.Unix(req.Proof.Timestamp, 0)
+	if ts.After(time.Now()) || time.Since(ts) > u.TTL {
+		return "", fmt.Errorf("%w: proof timestamp out of range", ErrInvalidSignature)
+	}
+	return addr.StringRaw(), nil
+}
+
+func sessionDTO(s *multisig.Session) *dto.MultisigSessionDTO {
+	signed := make(map[int]bool, len(s.Approvals))
+	for _, a := range s.Approvals {
+		signed[a.Index] = true
+	}
+
+	signers := make([]dto.MultisigSignerDTO, 0, len(s.Owners.Signers))
+	for _, signer := range s.Owners.Signers {
+		signers = append(signers, dto.MultisigSignerDTO{
+			Index:     signer.Index,
+			PublicKey: hex.EncodeToString(signer.PublicKey),
+			Address:   signer.Address,
+			Signed:    signed[signer.Index],
+		})
+	}
+
+	return &dto.MultisigSessionDTO{
+		Challenge: s.ID,
+		Message:   s.Message,
+		Address:   s.Address,
+		Network:   string(s.Network),
+		Kind:      string(s.Owners.Kind),
+		Threshold: s.Owners.Threshold,
+		Signers:   signers,
+		Status:    string(s.Status),
+		ExpiresAt: s.ExpiresAt,
+	}
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"TON/internal/chain"
+	"TON/internal/client"
+	"TON/internal/dto"
+	"TON/internal/gating"
+	"TON/internal/policy"
+	"TON/internal/tondns"
+	tonaddr "TON/pkg/address"
+	"TON/pkg/logger"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoPolicy      = errors.New("client has no access policy")
+	ErrInvalidPolicy = errors.New("invalid access policy")
+)
+
+type PolicyUseCase interface {
+	// DryRun evaluates the policy of a client for a wallet without a login.
+	DryRun(req dto.PolicyDryRunRequestDTO) (*dto.PolicyDryRunResponseDTO, error)
+}
+
+type PolicyUseCaseImpl struct {
+	log            logger.Logger
+	providers      chain.Providers
+	defaultNetwork chain.Network
+	clients        *client.Registry
+	policies       *policy.Set
+	gate           *gating.Evaluator
+	names          *tondns.Resolver
+}
+
+func NewPolicyUseCase(log logger.Logger, providers chain.Providers, defaultNetwork chain.Network, clients *client.Registry, policies *policy.Set, gate *gating.Evaluator, names *tondns.Resolver) PolicyUseCase {
+	return &PolicyUseCaseImpl{
+		log:            log,
+		providers:      providers,
+		defaultNetwork: defaultNetwork,
+		clients:        clients,
+		policies:       policies,
+		gate:           gate,
+		names:          names,
+	}
+}
+
+func (u *PolicyUseCaseImpl) DryRun(req dto.PolicyDryRunRequestDTO) (*dto.PolicyDryRunResponseDTO, error) {
+	ctx := context.Background()
+
+	c, err := lookupClient(u.clients, req.ClientID, true)
+	if err != nil {
+		return nil, err
+	}
+	network, err := selectNetwork(u.defaultNetwork, req.Network, c)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := u.providers.Get(network)
+	if err != nil {
+		return nil, err
+	}
+
+	p := u.policies.Get(c.ID)
+	if len(req.Policy) > 0 {
+		if p, err = policy.Parse(req.Policy); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+		}
+	}
+	if p == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoPolicy, c.ID)
+	}
+
+	addr, err := tonaddr.Normalize(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := getAccount(ctx, provider, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	facts, err := policyFacts(ctx, u.gate, provider, network, addr, p)
+	if err != nil {
+		return nil, err
+	}
+	facts.Status = string(acc.Status)
+	facts.ClientID = c.ID
+	facts.IP = req.IP
+	facts.Scopes = strings.Fields(req.Scope)
+	if acc.Status == chain.StatusActive {
+		// the facts of a contract that is not a known wallet stay empty
+		if pub, version, err := chain.WalletPublicKey(ctx, provider, acc); err == nil {
+			facts.PublicKey = hex.EncodeToString(pub)
+			facts.Version = string(version)
+		}
+	}
+	if facts.Name, err = u.names.Name(ctx, provider, network, addr); err != nil {
+		return nil, fmt.Errorf("failed to resolve DNS name: %w", err)
+	}
+
+	decision, err := p.Evaluate(facts)
+	if err != nil {
+		u.log.Error(ctx, "Failed to evaluate access policy of client "+c.ID+": "+err.Error())
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+
+	return &dto.PolicyDryRunResponseDTO{
+		Allowed: decision.Allowed,
+		Reason:  decision.Reason,
+		Matched: decision.Matched,
+		Roles:   decision.Roles,
+		Scopes:  decision.Scopes,
+		Claims:  decision.Claims,
+		Facts:   facts,
+	}, nil
+}
+
+// policyFacts reads the chain facts of a wallet the policy needs: its balance, the
+// jettons and NFT collections listed in the policy and its activity when asked for.
+func policyFacts(ctx context.Context, gate *gating.Evaluator, provider chain.ChainProvider, network chain.Network, addr string, p *policy.Policy) (*policy.Facts, error) {
+	balance, _, err := gate.Balance(ctx, provider, network, addr, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	facts := &policy.Facts{
+		Address: addr,
+		Balance: balance,
+		Network: string(network),
+		Jettons: make(map[string]chain.JettonBalance, len(p.Jettons)),
+		NFTs:    []chain.NFTItem{},
+	}
+	for _, master := range p.Jettons {
+		j, err := gate.JettonBalance(ctx, provider, network, addr, master, 0)
+		if err != nil {
+			return nil, err
+		}
+		facts.Jettons[master] = *j
+	}
+	for _, collection := range p.Collections {
+		items, err := gate.NFTs(ctx, provider, network, addr, collection, 0)
+		if err != nil {
+			return nil, err
+		}
+		facts.NFTs = append(facts.NFTs, items...)
+	}
+	if p.Activity {
+		a, err := gate.Activity(ctx, provider, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		facts.Activity = &policy.Activity{AgeDays: a.AgeDays(time.Now()), TxCount: a.TxCount, Truncated: a.Truncated}
+		if !a.FirstTx.IsZero() {
+			facts.Activity.FirstTxAt = a.FirstTx.Unix()
+			facts.Activity.LastTxAt = a.LastTx.Unix()
+		}
+	}
+	return facts, nil
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"TON/internal/chain"
+	"TON/internal/client"
+	"TON/internal/dto"
+	"TON/pkg/logger"
+	"TON/pkg/signdata"
+	"TON/pkg/tonwallet"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// AttestationType is the typ header of sign data attestations, which keeps them apart
+// from access tokens signed with the same key.
+const AttestationType = "ton-sign-data+jwt"
+
+type SignDataUseCase interface {
+	// Verify checks a TonConnect signData result against the key of the wallet and
+	// returns an attestation that the wallet approved the payload.
+	Verify(req dto.SignDataRequestDTO) (*dto.SignDataResponseDTO, error)
+}
+
+type SignDataUseCaseImpl struct {
+	Issuer string
+	// TTL is how long an attestation is valid.
+	TTL time.Duration
+	// MaxAge is how old a signature may be.
+	MaxAge         time.Duration
+	PrivKey        *rsa.PrivateKey
+	log            logger.Logger
+	providers      chain.Providers
+	defaultNetwork chain.Network
+	clients        *client.Registry
+}
+
+func NewSignDataUseCase(issuer string, ttl, maxAge time.Duration, priv *rsa.PrivateKey, log logger.Logger, providers chain.Providers, defaultNetwork chain.Network, clients *client.Registry) SignDataUseCase {
+	return &SignDataUseCaseImpl{
+		Issuer:         issuer,
+		TTL:            ttl,
+		MaxAge:         maxAge,
+		PrivKey:        priv,
+		log:            log,
+		providers:      providers,
+		defaultNetwork: defaultNetwork,
+		clients:        clients,
+	}
+}
+
+func (u *SignDataUseCaseImpl) Verify(req dto.SignDataRequestDTO) (*dto.SignDataResponseDTO, error) {
+	ctx := context.Background()
+
+	c, err := lookupClient(u.clients, req.ClientID, true)
+	if err != nil {
+		u.log.Error(ctx, "Unknown client: "+req.ClientID)
+		return nil, err
+	}
+	network, err := selectNetwork(u.defaultNetwork, req.Network, c)
+	if err != nil {
+		u.log.Error(ctx, "Invalid network: "+err.Error())
+		return nil, err
+	}
+	provider, err := u.providers.Get(network)
+	if err != nil {
+		u.log.Error(ctx, "No chain provider for network "+network.Name())
+		return nil, err
+	}
+	addr, err := chain.ParseAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(req.Domain, c.Domain()) {
+		u.log.Error(ctx, fmt.Sprintf("Invalid sign data domain: got %s, expected %s", req.Domain, c.Domain()))
+		return nil, fmt.Errorf("%w: domain does not match client manifest", ErrInvalidSignature)
+	}
+	ts := time.Unix(req.Timestamp, 0)
+	if ts.After(time.Now()) || time.Since(ts) > u.MaxAge {
+		return nil, fmt.Errorf("%w: timestamp out of range", ErrInvalidSignature)
+	}
+
+	payload, err := signDataPayload(req.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := u.walletKey(ctx, provider, addr, req)
+	if err != nil {
+		return nil, err
+	}
+	err = signdata.Verify(pub, signdata.Data{
+		Payload:     payload,
+		Domain:      req.Domain,
+		Timestamp:   req.Timestamp,
+		Signature:   req.Signature,
+		AddressWC:   addr.Workchain(),
+		AddressHash: addr.Data(),
+	})
+	if err != nil {
+		u.log.Error(ctx, "Invalid sign data signature of "+addr.StringRaw()+": "+err.Error())
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	payloadHash, err := signdata.PayloadHash(payload)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.attest(addr.StringRaw(), network, pub, req, hex.EncodeToString(payloadHash))
+	if err != nil {
+		return nil, err
+	}
+
+	u.log.Info(ctx, "Sign data of type "+payload.Type+" verified for wallet "+addr.StringRaw()+" on "+network.Name())
+	return resp, nil
+}
+
+// walletKey returns the key controlling the wallet: the key read from a deployed wallet,
+// or the provided key embedded in the StateInit of a wallet that is not deployed yet.
+func (u *SignDataUseCaseImpl) walletKey(ctx context.Context, provider chain.ChainProvider, addr *address.Address, req dto.SignDataRequestDTO) (ed25519.PublicKey, error) {
+	acc, err := getAccount(ctx, provider, addr.StringRaw())
+	if err != nil {
+		u.log.Error(ctx, "Failed to read wallet: "+err.Error())
+		return nil, fmt.Errorf("failed to read wallet: %w", err)
+	}
+
+	switch acc.Status {
+	case chain.StatusActive:
+		key, version, err := chain.WalletPublicKey(ctx, provider, acc)
+		if err != nil {
+			u.log.Error(ctx, "Failed to read wallet public key: "+err.Error())
+			return nil, fmt.Errorf("failed to read wallet public key: %w", err)
+		}
+		if version == "" {
+			u.log.Error(ctx, "Unknown wallet contract: "+acc.Address)
+			return nil, fmt.Errorf("failed to read wallet public key: %w", chain.ErrUnknownWallet)
+		}
+		if len(req.PublicKey) > 0 && !bytes.Equal(key, req.PublicKey) {
+			return nil, fmt.Errorf("%w: public key does not control the wallet", ErrInvalidSignature)
+		}
+		return key, nil
+	case chain.StatusUninit, chain.StatusNonexist:
+		if len(req.StateInit) == 0 {
+			u.log.Error(ctx, "Wallet is not active and no state init was provided: "+acc.Address)
+			return nil, errors.New("wallet is not active")
+		}
+		state, err := tonwallet.ParseStateInit(req.StateInit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state init: %w", err)
+		}
+		if _, err := tonwallet.CheckStateInit(addr, state, req.PublicKey); err != nil {
+			u.log.Error(ctx, "State init rejected for "+acc.Address+": "+err.Error())
+			return nil, fmt.Errorf("invalid state init: %w", err)
+		}
+		return req.PublicKey, nil
+	default:
+		u.log.Error(ctx, "Wallet is "+string(acc.Status)+": "+acc.Address)
+		return nil, errors.New("wallet is " + string(acc.Status))
+	}
+}
+
+// attest signs a JWT stating that the wallet approved the payload. The payload itself is
+// not included, relying parties compare its hash with their copy.
+func (u *SignDataUseCaseImpl) attest(addr string, network chain.Network, pub ed25519.PublicKey, req dto.SignDataRequestDTO, payloadHash string) (*dto.SignDataResponseDTO, error) {
+	tokenID, err := generateRandomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := map[string]interface{}{
+		"type":         req.Payload.Type,
+		"domain":       req.Domain,
+		"timestamp":    req.Timestamp,
+		"payload_hash": payloadHash,
+		"signature":    base64.StdEncoding.EncodeToString(req.Signature),
+	}
+	if req.Payload.Type == signdata.TypeCell {
+		signed["schema"] = req.Payload.Schema
+	}
+
+	now := time.Now()
+	exp := now.Add(u.TTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"jti":        tokenID,
+		"iss":        u.Issuer,
+		"sub":        addr,
+		"aud":        req.ClientID,
+		"iat":        now.Unix(),
+		"exp":        exp.Unix(),
+		"network":    string(network),
+		"public_key": hex.EncodeToString(pub),
+		"sign_data":  signed,
+	})
+	token.Header["typ"] = AttestationType
+
+	tokenStr, err := token.SignedString(u.PrivKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.SignDataResponseDTO{
+		Valid:       true,
+		Address:     addr,
+		PayloadHash: payloadHash,
+		Attestation: tokenStr,
+		ExpiresAt:   exp,
+	}, nil
+}
+
+func signDataPayload(p dto.SignDataPayloadDTO) (signdata.Payload, error) {
+	payload := signdata.Payload{Type: p.Type, Text: p.Text, Bytes: p.Bytes, Schema: p.Schema}
+	if p.Type == signdata.TypeCell {
+		c, err := cell.FromBOC(p.Cell)
+		if err != nil {
+			return payload, fmt.Errorf("%w: invalid cell: %w", ErrInvalidSignature, err)
+		}
+		payload.Cell = c
+	}
+	return payload, nil
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"TON/internal/dto"
+	"TON/internal/txauth"
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxTransactionWait caps how long a status request waits for a pending challenge.
+const maxTransactionWait = time.Minute
+
+type TransactionUseCase interface {
+	// Status returns the state of a transaction challenge, waiting up to the requested
+	// time while it is pending and ctx is not done.
+	Status(ctx context.Context, req dto.TransactionStatusRequestDTO) (*dto.TransactionStatusResponseDTO, error)
+}
+
+type TransactionUseCaseImpl struct {
+	watcher *txauth.Watcher
+}
+
+func NewTransactionUseCase(watcher *txauth.Watcher) TransactionUseCase {
+	return &TransactionUseCaseImpl{
+		watcher: watcher,
+	}
+}
+
+func (u *TransactionUseCaseImpl) Status(ctx context.Context, req dto.TransactionStatusRequestDTO) (*dto.TransactionStatusResponseDTO, error) {
+	var wait time.Duration
+	if req.Wait != "" {
+		d, err := time.ParseDuration(req.Wait)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid wait %q", req.Wait)
+		}
+		wait = min(d, maxTransactionWait)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	ch, err := u.watcher.Wait(ctx, req.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TransactionStatusResponseDTO{
+		Status:    string(ch.Status),
+		Wallet:    ch.Wallet,
+		TxHash:    ch.TxHash,
+		ExpiresAt: ch.ExpiresAt,
+	}, nil
+}
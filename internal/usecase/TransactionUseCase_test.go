@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"TON/internal/chain"
+	"TON/internal/dto"
+	"TON/internal/gating"
+	"TON/internal/txauth"
+	"TON/pkg/logger"
+	"TON/pkg/tonwallet"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTransactionStatusStopsWithRequest(t *testing.T) {
+	gate, err := gating.NewEvaluator(time.Minute, time.Minute, 100, time.Minute, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher := txauth.NewWatcher(txauth.Config{
+		Network: chain.Mainnet,
+		Address: testWallet,
+		Amount:  10_000_000,
+		TTL:     10 * time.Minute,
+	}, logger.New("test"), chain.Providers{chain.Mainnet: chain.NewFixtureProvider(tonwallet.Options{})}, gate)
+	ch, err := watcher.Start("app", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the client goes away long before the requested wait is over
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	resp, err := NewTransactionUseCase(watcher).Status(ctx, dto.TransactionStatusRequestDTO{Challenge: ch.ID, Wait: "1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Status() waited %s after the request was done", elapsed)
+	}
+	if resp.Status != string(txauth.Pending) {
+		t.Fatalf("Status() = %s, want %s", resp.Status, txauth.Pending)
+	}
+}
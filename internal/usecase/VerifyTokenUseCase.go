@@ -8,23 +8,38 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// ErrNotAccessToken is returned for attestations, credentials and other tokens signed
+// with the key of the service that are no access tokens.
+var ErrNotAccessToken = errors.New("token is not an access token")
+
 type TokenVerifyUseCase interface {
+	// VerifyToken checks an access token issued by the service, and that it was issued to
+	// the client of the request when one is set.
 	VerifyToken(req dto.VerifyTokenRequestDTO, pub *rsa.PublicKey) (*dto.VerifyTokenResponseDTO, error)
 }
 
-type TokenVerifyUseCaseImpl struct{}
+type TokenVerifyUseCaseImpl struct {
+	Issuer string
+}
 
-func NewTokenVerifyUseCase() TokenVerifyUseCase {
-	return &TokenVerifyUseCaseImpl{}
+func NewTokenVerifyUseCase(issuer string) TokenVerifyUseCase {
+	return &TokenVerifyUseCaseImpl{Issuer: issuer}
 }
 
 func (u *TokenVerifyUseCaseImpl) VerifyToken(req dto.VerifyTokenRequestDTO, pub *rsa.PublicKey) (*dto.VerifyTokenResponseDTO, error) {
+	opts := []jwt.ParserOption{jwt.WithIssuer(u.Issuer), jwt.WithExpirationRequired()}
+	if req.ClientID != "" {
+		opts = append(opts, jwt.WithAudience(req.ClientID))
+	}
 	token, err := jwt.Parse(req.JWT, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
+		if typ, _ := token.Header["typ"].(string); typ != AccessTokenType {
+			return nil, ErrNotAccessToken
+		}
 		return pub, nil
-	})
+	}, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -40,10 +55,18 @@ func (u *TokenVerifyUseCaseImpl) VerifyToken(req dto.VerifyTokenRequestDTO, pub
 
 	iss, _ := claims["iss"].(string)
 	exp, _ := claims["exp"].(float64)
+	sub, _ := claims["sub"].(string)
+	network, _ := claims["network"].(string)
+	walletState, _ := claims["wallet_state"].(string)
+	username, _ := claims["preferred_username"].(string)
 
 	return &dto.VerifyTokenResponseDTO{
-		Valid:  true,
-		Issuer: iss,
-		Exp:    int64(exp),
+		Valid:             true,
+		Issuer:            iss,
+		Subject:           sub,
+		Network:           network,
+		WalletState:       walletState,
+		PreferredUsername: username,
+		Exp:               int64(exp),
 	}, nil
 }
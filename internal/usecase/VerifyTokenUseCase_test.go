@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"TON/internal/chain"
+	"TON/internal/dto"
+	"TON/internal/identity"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testWallet = "0:960ab627408d5472d9d125b667cbe00ce17eeaa44e9dc6a86e93cdfef2c480d5"
+
+func TestVerifyToken(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := &TokenUseCaseImpl{Issuer: "TON-OAUTH", TTL: time.Minute, PrivKey: priv}
+	access, err := tokens.sign(&identity.Identity{Address: testWallet, Network: chain.Mainnet, ClientID: "my-dapp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign, err := (&TokenUseCaseImpl{Issuer: "OTHER", TTL: time.Minute, PrivKey: priv}).sign(&identity.Identity{Address: testWallet})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, _, _ := ed25519.GenerateKey(nil)
+	signData := &SignDataUseCaseImpl{Issuer: "TON-OAUTH", TTL: time.Minute, PrivKey: priv}
+	attestation, err := signData.attest(testWallet, chain.Mainnet, pub, dto.SignDataRequestDTO{ClientID: "my-dapp"}, "00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	untyped := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"iss": "TON-OAUTH", "sub": testWallet, "exp": time.Now().Add(time.Minute).Unix()})
+	plain, err := untyped.SignedString(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		jwt      string
+		clientID string
+		wantErr  error
+	}{
+		{"access token", access.JWT, "", nil},
+		{"access token of the client", access.JWT, "my-dapp", nil},
+		{"access token of another client", access.JWT, "other", jwt.ErrTokenInvalidAudience},
+		{"other issuer", foreign.JWT, "", jwt.ErrTokenInvalidIssuer},
+		{"sign data attestation", attestation.Attestation, "", ErrNotAccessToken},
+		{"token without typ", plain, "", ErrNotAccessToken},
+	}
+	verify := NewTokenVerifyUseCase("TON-OAUTH")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := verify.VerifyToken(dto.VerifyTokenRequestDTO{JWT: tt.jwt, ClientID: tt.clientID}, &priv.PublicKey)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (resp.Subject != testWallet || resp.Issuer != "TON-OAUTH") {
+				t.Fatalf("VerifyToken() = %+v", resp)
+			}
+		})
+	}
+}
@@ -1,156 +1,717 @@
 package usecase
 
 import (
+	"TON/internal/chain"
+	"TON/internal/client"
 	"TON/internal/dto"
+	"TON/internal/gating"
+	"TON/internal/identity"
+	"TON/internal/lists"
+	"TON/internal/multisig"
+	"TON/internal/policy"
+	"TON/internal/pow"
+	"TON/internal/screening"
+	"TON/internal/tondns"
+	"TON/internal/txauth"
+	tonaddr "TON/pkg/address"
+	"TON/pkg/did"
 	"TON/pkg/logger"
+	"TON/pkg/tonproof"
+	"TON/pkg/tonwallet"
+	"bytes"
 	"context"
 	"crypto/ed25519"
-	"encoding/json"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"net/http"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/xssnick/tonutils-go/address"
 )
 
 type VerifyUseCase interface {
 	Verify(req dto.VerifyRequestDTO) (*dto.VerifyResponseDTO, error)
+	// Identify verifies the request like Verify and returns the proven wallet.
+	Identify(req dto.VerifyRequestDTO) (*identity.Identity, error)
+	// IdentifyTransfer admits the wallet that approved a transaction challenge, requested
+	// from the IP address.
+	IdentifyTransfer(ch *txauth.Challenge, ip string) (*identity.Identity, error)
+	// IdentifyMultisig admits the multisig contract whose owners signed the session,
+	// requested from the IP address.
+	IdentifyMultisig(s *multisig.Session, ip string) (*identity.Identity, error)
+}
+
+// VerifyDeps are the chain access, client settings and login checks of the verify use
+// case. Policies, Screener and Guard may be nil to turn those checks off.
+type VerifyDeps struct {
+	Providers      chain.Providers
+	DefaultNetwork chain.Network
+	Clients        *client.Registry
+	WalletOpts     tonwallet.Options
+	Preference     tonwallet.Preference
+	AddressFormat  tonaddr.Format
+	Gate           *gating.Evaluator
+	Names          *tondns.Resolver
+	// DNSClaims adds the verified TON DNS name of the wallet to tokens.
+	DNSClaims bool
+	Policies  *policy.Set
+	Lists     *lists.Store
+	// AllowlistOnly admits only wallets on an allow list, for every client.
+	AllowlistOnly bool
+	Screener      *screening.Screener
+	Guard         *pow.Guard
 }
 
 type VerifyUseCaseImpl struct {
-	Issuer string
-	TTL    time.Duration
-	log    logger.Logger
-	apiURL string
-	apiKey string
-	client *http.Client
+	Issuer         string
+	TTL            time.Duration
+	log            logger.Logger
+	providers      chain.Providers
+	defaultNetwork chain.Network
+	clients        *client.Registry
+	walletOpts     tonwallet.Options
+	preference     tonwallet.Preference
+	addressFormat  tonaddr.Format
+	gate           *gating.Evaluator
+	names          *tondns.Resolver
+	dnsClaims      bool
+	policies       *policy.Set
+	lists          *lists.Store
+	allowlistOnly  bool
+	screener       *screening.Screener
+	guard          *pow.Guard
 }
 
-func NewVerifyUseCase(issuer string, ttl time.Duration, log logger.Logger, apiURL, apiKey string) VerifyUseCase {
+func NewVerifyUseCase(issuer string, ttl time.Duration, log logger.Logger, deps VerifyDeps) VerifyUseCase {
 	return &VerifyUseCaseImpl{
-		Issuer: issuer,
-		TTL:    ttl,
-		log:    log,
-		apiURL: apiURL,
-		apiKey: apiKey,
-		client: &http.Client{Timeout: 10 * time.Second},
+		Issuer:         issuer,
+		TTL:            ttl,
+		log:            log,
+		providers:      deps.Providers,
+		defaultNetwork: deps.DefaultNetwork,
+		clients:        deps.Clients,
+		walletOpts:     deps.WalletOpts,
+		preference:     deps.Preference,
+		addressFormat:  deps.AddressFormat,
+		gate:           deps.Gate,
+		names:          deps.Names,
+		dnsClaims:      deps.DNSClaims,
+		policies:       deps.Policies,
+		lists:          deps.Lists,
+		allowlistOnly:  deps.AllowlistOnly,
+		screener:       deps.Screener,
+		guard:          deps.Guard,
 	}
 }
+
 func (u *VerifyUseCaseImpl) Verify(req dto.VerifyRequestDTO) (*dto.VerifyResponseDTO, error) {
 	ctx := context.Background()
 	u.log.Info(ctx, "Starting signature verification")
 
+	id, ts, err := u.identify(ctx, req, pow.UseVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce string
+	if req.Proof != nil {
+		nonce = req.Proof.Payload
+	}
+	c, _ := lookupClient(u.clients, id.ClientID, false)
+
+	return &dto.VerifyResponseDTO{
+		Valid:       true,
+		Wallet:      renderAddress(id.Address, addressFormatFor(c, u.addressFormat), id.Network),
+		Version:     string(id.Version),
+		Network:     string(id.Network),
+		WalletState: string(id.State),
+		Name:        id.Name,
+		Issuer:      u.Issuer,
+		Nonce:       nonce,
+		ExpiresAt:   ts.Add(u.TTL),
+	}, nil
+}
+
+func (u *VerifyUseCaseImpl) Identify(req dto.VerifyRequestDTO) (*identity.Identity, error) {
+	id, _, err := u.identify(context.Background(), req, pow.UseExchange)
+	return id, err
+}
+
+// IdentifyTransfer applies the same client rules as a signed login to the sender of the
+// transfer. The wallet proved control by sending it, whatever its contract, so the public
+// key and version are only known for regular wallets.
+func (u *VerifyUseCaseImpl) IdentifyTransfer(ch *txauth.Challenge, ip string) (*identity.Identity, error) {
+	ctx := context.Background()
+
+	c, err := lookupClient(u.clients, ch.ClientID, false)
+	if err != nil {
+		u.log.Error(ctx, "Unknown client: "+ch.ClientID)
+		return nil, err
+	}
+	provider, err := u.providers.Get(ch.Network)
+	if err != nil {
+		u.log.Error(ctx, "No chain provider for network "+ch.Network.Name())
+		return nil, err
+	}
+
+	allowlistOnly := u.allowlistOnly || (c != nil && c.AllowlistOnly)
+	if err := u.lists.Check(ch.ClientID, ch.Wallet, allowlistOnly); err != nil {
+		u.log.Error(ctx, "Wallet "+ch.Wallet+" rejected for client "+ch.ClientID+": "+err.Error())
+		return nil, fmt.Errorf("%w: %w", gating.ErrAccessDenied, err)
+	}
+
+	acc, err := getAccount(ctx, provider, ch.Wallet)
+	if err != nil {
+		u.log.Error(ctx, "Failed to read wallet account: "+err.Error())
+		return nil, fmt.Errorf("failed to read wallet account: %w", err)
+	}
+
+	id := &identity.Identity{
+		Address:  ch.Wallet,
+		Network:  ch.Network,
+		State:    acc.Status,
+		ClientID: ch.ClientID,
+	}
+	if acc.Status == chain.StatusActive {
+		if key, version, err := chain.WalletPublicKey(ctx, provider, acc); err == nil && version != "" {
+			id.PublicKey, id.Version = key, version
+		}
+	}
+	id.Grant(nil, nil, map[string]interface{}{"amr": []string{"transaction"}, "tx_hash": ch.TxHash})
+
+	req := dto.VerifyRequestDTO{ClientID: ch.ClientID, Scope: ch.Scope, IP: ip}
+	if err := u.admit(ctx, provider, c, id, req); err != nil {
+		return nil, err
+	}
+
+	u.log.Info(ctx, "Transfer login successful for wallet "+ch.Wallet+" on "+ch.Network.Name())
+	return id, nil
+}
+
+// IdentifyMultisig reads the owners of the contract again and admits it when the owners
+// that signed the session still reach the threshold. The signers are listed in the
+// signers claim, each with its wallet as sub when known and its public key, and as the
+// actors of the act claim.
+func (u *VerifyUseCaseImpl) IdentifyMultisig(s *multisig.Session, ip string) (*identity.Identity, error) {
+	ctx := context.Background()
+
+	c, err := lookupClient(u.clients, s.ClientID, false)
+	if err != nil {
+		u.log.Error(ctx, "Unknown client: "+s.ClientID)
+		return nil, err
+	}
+	provider, err := u.providers.Get(s.Network)
+	if err != nil {
+		u.log.Error(ctx, "No chain provider for network "+s.Network.Name())
+		return nil, err
+	}
+
+	owners, err := multisig.ReadOwners(ctx, provider, s.Address)
+	if err != nil {
+		u.log.Error(ctx, "Failed to read owners of multisig "+s.Address+": "+err.Error())
+		return nil, err
+	}
+	approvals := owners.Approved(s.Approvals)
+	if len(approvals) < owners.Threshold {
+		u.log.Error(ctx, fmt.Sprintf("Multisig %s has %d of %d owner signatures", s.Address, len(approvals), owners.Threshold))
+		return nil, fmt.Errorf("%w: %d of %d", multisig.ErrThreshold, len(approvals), owners.Threshold)
+	}
+
+	allowlistOnly := u.allowlistOnly || (c != nil && c.AllowlistOnly)
+	if err := u.lists.Check(s.ClientID, s.Address, allowlistOnly); err != nil {
+		u.log.Error(ctx, "Multisig "+s.Address+" rejected for client "+s.ClientID+": "+err.Error())
+		return nil, fmt.Errorf("%w: %w", gating.ErrAccessDenied, err)
+	}
+
+	acc, err := getAccount(ctx, provider, s.Address)
+	if err != nil {
+		u.log.Error(ctx, "Failed to read multisig account: "+err.Error())
+		return nil, fmt.Errorf("failed to read multisig account: %w", err)
+	}
+
+	signers := make([]map[string]interface{}, 0, len(approvals))
+	for _, a := range approvals {
+		signer := map[string]interface{}{"public_key": hex.EncodeToString(a.PublicKey)}
+		if a.Address != "" {
+			signer["sub"] = a.Address
+		}
+		signers = append(signers, signer)
+	}
+	act, err := actors(approvals)
+	if err != nil {
+		return nil, err
+	}
+
+	id := &identity.Identity{
+		Address:  s.Address,
+		Network:  s.Network,
+		State:    acc.Status,
+		ClientID: s.ClientID,
+	}
+	id.Grant(nil, nil, map[string]interface{}{
+		"amr":                []string{"multisig"},
+		"act":                act,
+		"signers":            signers,
+		"multisig_threshold": owners.Threshold,
+	})
+
+	req := dto.VerifyRequestDTO{ClientID: s.ClientID, Scope: s.Scope, IP: ip}
+	if err := u.admit(ctx, provider, c, id, req); err != nil {
+		return nil, err
+	}
+
+	u.log.Info(ctx, fmt.Sprintf("Multisig login successful for %s on %s with %d signers", s.Address, s.Network.Name(), len(approvals)))
+	return id, nil
+}
+
+// actors builds the act claim of RFC 8693 from the approvals: the signer that completed
+// the session is the current actor, and the ones that signed before it are nested as
+// prior actors. A signer is its wallet address, or the did:key of its key for v1 owners.
+func actors(approvals []multisig.Approval) (map[string]interface{}, error) {
+	ordered := slices.Clone(approvals)
+	slices.SortStableFunc(ordered, func(a, b multisig.Approval) int {
+		return a.SignedAt.Compare(b.SignedAt)
+	})
+
+	var act map[string]interface{}
+	for _, a := range ordered {
+		sub := a.Address
+		if sub == "" {
+			var err error
+			if sub, err = did.Key(a.PublicKey); err != nil {
+				return nil, err
+			}
+		}
+		actor := map[string]interface{}{"sub": sub}
+		if act != nil {
+			actor["act"] = act
+		}
+		act = actor
+	}
+	return act, nil
+}
+
+// identify checks the proof of work when it is enabled before anything is read from the
+// chain, then identifies the wallet. The solution is bound to the signed challenge, so
+// the verify and token calls of a login share it, each paying for one use. Failures
+// spend the solution and raise the puzzle difficulty of the caller.
+func (u *VerifyUseCaseImpl) identify(ctx context.Context, req dto.VerifyRequestDTO, use pow.Use) (*identity.Identity, time.Time, error) {
+	if u.guard == nil {
+		return u.identifyWallet(ctx, req)
+	}
+
+	var challenge, nonce string
+	if req.PoW != nil {
+		challenge, nonce = req.PoW.Challenge, req.PoW.Nonce
+	}
+	subject := req.Message
+	if req.Proof != nil {
+		subject = req.Proof.Payload
+	}
+	if err := u.guard.Check(req.IP, challenge, nonce, subject, use); err != nil {
+		u.log.Error(ctx, "Proof of work rejected for "+req.IP+": "+err.Error())
+		return nil, time.Time{}, err
+	}
+
+	id, ts, err := u.identifyWallet(ctx, req)
+	if err != nil {
+		u.guard.Spend(challenge)
+		u.guard.Failure(req.IP)
+	}
+	return id, ts, err
+}
+
+// identifyWallet checks the signature, resolves the wallet on the requested network and
+// returns it together with the time the request was signed.
+func (u *VerifyUseCaseImpl) identifyWallet(ctx context.Context, req dto.VerifyRequestDTO) (*identity.Identity, time.Time, error) {
+	c, err := lookupClient(u.clients, req.ClientID, req.Proof != nil)
+	if err != nil {
+		u.log.Error(ctx, "Unknown client: "+req.ClientID)
+		return nil, time.Time{}, err
+	}
+
+	network, err := selectNetwork(u.defaultNetwork, req.Network, c)
+	if err != nil {
+		u.log.Error(ctx, "Invalid network: "+err.Error())
+		return nil, time.Time{}, err
+	}
+	provider, err := u.providers.Get(network)
+	if err != nil {
+		u.log.Error(ctx, "No chain provider for network "+network.Name())
+		return nil, time.Time{}, err
+	}
+	opts := u.walletOpts
+	opts.NetworkGlobalID = network.GlobalID()
+
+	var ts time.Time
+	if req.Proof != nil {
+		ts, err = u.verifyProof(ctx, req, c)
+	} else {
+		ts, err = u.verifyMessage(ctx, req)
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	walletAddr, version, err := u.resolveWallet(ctx, provider, opts, network, preferenceFor(c, u.preference), req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	allowlistOnly := u.allowlistOnly || (c != nil && c.AllowlistOnly)
+	if err := u.lists.Check(req.ClientID, walletAddr, allowlistOnly); err != nil {
+		u.log.Error(ctx, "Wallet "+walletAddr+" rejected for client "+req.ClientID+": "+err.Error())
+		return nil, time.Time{}, fmt.Errorf("%w: %w", gating.ErrAccessDenied, err)
+	}
+
+	acc, err := getAccount(ctx, provider, walletAddr)
+	if err != nil {
+		u.log.Error(ctx, "Failed to check wallet activity: "+err.Error())
+		return nil, time.Time{}, fmt.Errorf("failed to check wallet activity: %w", err)
+	}
+
+	var provenVersion tonwallet.Version
+	switch acc.Status {
+	case chain.StatusActive:
+		provenVersion, err = u.verifyOwnership(ctx, provider, acc, req.PublicKey)
+	case chain.StatusUninit, chain.StatusNonexist:
+		provenVersion, err = u.verifyStateInit(ctx, acc, req)
+	default:
+		u.log.Error(ctx, "Wallet is "+string(acc.Status)+": "+walletAddr)
+		err = errors.New("wallet is " + string(acc.Status))
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if version == "" {
+		version = provenVersion
+	}
+
+	id := &identity.Identity{
+		Address:   walletAddr,
+		Network:   network,
+		PublicKey: req.PublicKey,
+		Version:   version,
+		State:     acc.Status,
+		ClientID:  req.ClientID,
+	}
+
+	if err := u.admit(ctx, provider, c, id, req); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	u.log.Info(ctx, "Signature and wallet verification successful for wallet "+walletAddr+" on "+network.Name())
+	return id, ts, nil
+}
+
+// admit applies the screening lists, DNS requirements, gating rules and access policy of
+// the client to a wallet whose control was proven, and grants what they decide.
+func (u *VerifyUseCaseImpl) admit(ctx context.Context, provider chain.ChainProvider, c *client.Client, id *identity.Identity, req dto.VerifyRequestDTO) error {
+	if err := u.screen(ctx, provider, id); err != nil {
+		return err
+	}
+
+	requireDNS := c != nil && c.RequireDNS
+	if u.dnsClaims || requireDNS {
+		var err error
+		id.Name, err = u.names.Name(ctx, provider, id.Network, id.Address)
+		if err != nil {
+			u.log.Error(ctx, "Failed to resolve DNS name of "+id.Address+": "+err.Error())
+			return fmt.Errorf("failed to resolve DNS name: %w", err)
+		}
+		if requireDNS && id.Name == "" {
+			u.log.Error(ctx, "Wallet "+id.Address+" has no DNS name required by client "+c.ID)
+			return fmt.Errorf("%w: wallet has no TON DNS name", gating.ErrAccessDenied)
+		}
+	}
+
+	if c != nil && c.Gating != nil {
+		granted, err := u.gate.Evaluate(ctx, provider, id.Network, id.Address, c.Gating)
+		if err != nil {
+			u.log.Error(ctx, "Wallet "+id.Address+" rejected by client rules: "+err.Error())
+			return err
+		}
+		id.Grant(granted.Roles, granted.Scopes, granted.Claims)
+	}
+
+	if p := u.policies.Get(id.ClientID); c != nil && p != nil {
+		if err := u.applyPolicy(ctx, provider, p, id, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// screen matches the proven wallet against the screening lists: a block hit rejects the
+// login, flag hits are listed in the screening_flags claim.
+func (u *VerifyUseCaseImpl) screen(ctx context.Context, provider chain.ChainProvider, id *identity.Identity) error {
+	res, err := u.screener.Screen(ctx, provider, id.ClientID, id.Address)
+	if err != nil {
+		u.log.Error(ctx, "Failed to screen wallet "+id.Address+": "+err.Error())
+		return err
+	}
+	if len(res.Hits) == 0 {
+		return nil
+	}
+
+	u.log.Info(ctx, fmt.Sprintf("Wallet %s matched %d screening entries, action %s", id.Address, len(res.Hits), res.Action))
+	if res.Action == screening.Block {
+		return fmt.Errorf("%w: wallet matched screening list %s", gating.ErrAccessDenied, strings.Join(res.Lists(screening.Block), ", "))
+	}
+	if flags := res.Flags(); len(flags) > 0 {
+		id.Grant(nil, nil, map[string]interface{}{"screening_flags": flags})
+	}
+	return nil
+}
+
+// applyPolicy evaluates the access policy of the client for the proven wallet and
+// grants what it decides.
+func (u *VerifyUseCaseImpl) applyPolicy(ctx context.Context, provider chain.ChainProvider, p *policy.Policy, id *identity.Identity, req dto.VerifyRequestDTO) error {
+	facts, err := policyFacts(ctx, u.gate, provider, id.Network, id.Address, p)
+	if err != nil {
+		u.log.Error(ctx, "Failed to read policy facts of "+id.Address+": "+err.Error())
+		return err
+	}
+	facts.Version = string(id.Version)
+	facts.Status = string(id.State)
+	facts.PublicKey = hex.EncodeToString(id.PublicKey)
+	facts.Name = id.Name
+	facts.ClientID = id.ClientID
+	facts.IP = req.IP
+	facts.Scopes = strings.Fields(req.Scope)
+
+	decision, err := p.Evaluate(facts)
+	if err != nil {
+		u.log.Error(ctx, "Failed to evaluate access policy of client "+id.ClientID+": "+err.Error())
+		return fmt.Errorf("failed to evaluate access policy: %w", err)
+	}
+	if !decision.Allowed {
+		u.log.Error(ctx, "Wallet "+id.Address+" denied by policy rule "+decision.Reason)
+		return fmt.Errorf("%w: denied by policy rule %s", gating.ErrAccessDenied, decision.Reason)
+	}
+	id.Grant(decision.Roles, decision.Scopes, decision.Claims)
+	return nil
+}
+
+// lookupClient returns the registered client with the id. Signed messages may come with
+// the anonymous client id issued by authorize, so unknown ids are only an error when required.
+func lookupClient(clients *client.Registry, id string, required bool) (*client.Client, error) {
+	if id == "" {
+		return nil, nil
+	}
+	c, err := clients.Get(id)
+	if errors.Is(err, client.ErrClientNotFound) && !required {
+		return nil, nil
+	}
+	return c, err
+}
+
+// selectNetwork picks the requested network, the one pinned by the client or the default.
+func selectNetwork(defaultNetwork chain.Network, requested string, c *client.Client) (chain.Network, error) {
+	network := defaultNetwork
+	if c != nil && c.Network != "" {
+		network = chain.Network(c.Network)
+	}
+	if requested == "" {
+		return network, nil
+	}
+
+	n, err := chain.ParseNetwork(requested)
+	if err != nil {
+		return "", err
+	}
+	if c != nil && c.Network != "" && n != network {
+		return "", fmt.Errorf("%w: client %s is bound to %s", chain.ErrNetworkNotSupported, c.ID, network.Name())
+	}
+	return n, nil
+}
+
+// verifyMessage checks a plain "issuer:timestamp" message signed with the wallet key.
+func (u *VerifyUseCaseImpl) verifyMessage(ctx context.Context, req dto.VerifyRequestDTO) (time.Time, error) {
 	if !ed25519.Verify(req.PublicKey, []byte(req.Message), req.Signature) {
 		u.log.Error(ctx, "Invalid signature")
-		return nil, errors.New("invalid signature")
+		return time.Time{}, errors.New("invalid signature")
 	}
 
-	parts := strings.Split(req.Message, ":")
+	parts := strings.SplitN(req.Message, ":", 2)
 	if len(parts) != 2 {
 		u.log.Error(ctx, "Invalid message format")
-		return nil, errors.New("invalid message format, expected 'issuer:timestamp'")
+		return time.Time{}, errors.New("invalid message format, expected 'issuer:timestamp'")
 	}
 	issuer, tsStr := parts[0], parts[1]
 	if issuer != u.Issuer {
 		u.log.Error(ctx, fmt.Sprintf("Invalid issuer: got %s, expected %s", issuer, u.Issuer))
-		return nil, errors.New("invalid issuer")
+		return time.Time{}, errors.New("invalid issuer")
 	}
 	ts, err := time.Parse(time.RFC3339, tsStr)
 	if err != nil {
 		u.log.Error(ctx, "Invalid timestamp format: "+err.Error())
-		return nil, fmt.Errorf("invalid timestamp format: %w", err)
-	}
-	now := time.Now()
-	if ts.After(now) {
-		u.log.Error(ctx, "Timestamp is from the future")
-		return nil, errors.New("timestamp is from the future")
+		return time.Time{}, fmt.Errorf("invalid timestamp format: %w", err)
 	}
-	if now.Sub(ts) > u.TTL {
-		u.log.Error(ctx, "Message expired")
-		return nil, errors.New("message expired")
+
+	return ts, u.checkTimestamp(ctx, ts)
+}
+
+// verifyProof checks a TonConnect ton_proof against the manifest domain of the requesting client.
+func (u *VerifyUseCaseImpl) verifyProof(ctx context.Context, req dto.VerifyRequestDTO, c *client.Client) (time.Time, error) {
+	if !strings.EqualFold(req.Proof.Domain.Value, c.Domain()) {
+		u.log.Error(ctx, fmt.Sprintf("Invalid proof domain: got %s, expected %s", req.Proof.Domain.Value, c.Domain()))
+		return time.Time{}, errors.New("proof domain does not match client manifest")
 	}
 
-	walletAddr, err := u.getWalletFromTonAPI(req.PublicKey)
+	addr, err := chain.ParseAddress(req.Address)
 	if err != nil {
-		u.log.Error(ctx, "Failed to resolve wallet from TonAPI: "+err.Error())
-		return nil, fmt.Errorf("failed to resolve wallet: %w", err)
+		u.log.Error(ctx, "Invalid address: "+err.Error())
+		return time.Time{}, fmt.Errorf("invalid address: %w", err)
 	}
 
-	active, err := u.isWalletActive(walletAddr)
+	err = tonproof.Verify(req.PublicKey, tonproof.Proof{
+		Timestamp:   req.Proof.Timestamp,
+		DomainLen:   req.Proof.Domain.LengthBytes,
+		Domain:      req.Proof.Domain.Value,
+		Payload:     req.Proof.Payload,
+		Signature:   req.Proof.Signature,
+		AddressWC:   addr.Workchain(),
+		AddressHash: addr.Data(),
+	})
 	if err != nil {
-		u.log.Error(ctx, "Failed to check wallet activity: "+err.Error())
-		return nil, fmt.Errorf("failed to check wallet activity: %w", err)
-	}
-	if !active {
-		u.log.Error(ctx, "Wallet is not active: "+walletAddr)
-		return nil, errors.New("wallet is not active")
+		u.log.Error(ctx, "Invalid proof: "+err.Error())
+		return time.Time{}, err
 	}
 
-	u.log.Info(ctx, "Signature and wallet verification successful for wallet "+walletAddr)
-	return &dto.VerifyResponseDTO{
-		Valid:     true,
-		Wallet:    walletAddr,
-		Issuer:    issuer,
-		ExpiresAt: ts.Add(u.TTL),
-	}, nil
+	ts := time.Unix(req.Proof.Timestamp, 0)
+	return ts, u.checkTimestamp(ctx, ts)
 }
 
-func (u *VerifyUseCaseImpl) getWalletFromTonAPI(publicKey []byte) (string, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v2/wallets?public_key=%x", u.apiURL, publicKey), nil)
+func (u *VerifyUseCaseImpl) checkTimestamp(ctx context.Context, ts time.Time) error {
+	now := time.Now()
+	if ts.After(now) {
+		u.log.Error(ctx, "Timestamp is from the future")
+		return errors.New("timestamp is from the future")
+	}
+	if now.Sub(ts) > u.TTL {
+		u.log.Error(ctx, "Message expired")
+		return errors.New("message expired")
+	}
+	return nil
+}
+
+// stateInitAddress returns the raw address a StateInit BoC deploys to in the workchain.
+func stateInitAddress(boc []byte, workchain int8) (string, error) {
+	state, err := tonwallet.ParseStateInit(boc)
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Authorization", "Bearer "+u.apiKey)
-
-	resp, err := u.client.Do(req)
+	hash, err := tonwallet.HashStateInit(state)
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
+	return address.NewAddress(0, byte(workchain), hash).StringRaw(), nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("TonAPI returned status %d", resp.StatusCode)
+// resolveWallet maps the public key to a wallet address. Without a claimed address the
+// address of the provided StateInit or the most preferred candidate wallet of the key is
+// used. A claimed address must be one of the standard wallets derived locally from the key
+// or a candidate listed by the chain provider.
+// The version is empty for wallets deployed with non-default parameters, which are
+// accepted only after their code was recognized on chain or in the StateInit.
+func (u *VerifyUseCaseImpl) resolveWallet(ctx context.Context, provider chain.ChainProvider, opts tonwallet.Options, network chain.Network, preference tonwallet.Preference, req dto.VerifyRequestDTO) (string, tonwallet.Version, error) {
+	var candidates []chain.Wallet
+	claimed := req.Address
+	if claimed == "" && len(req.StateInit) > 0 {
+		addr, err := stateInitAddress(req.StateInit, opts.Workchain)
+		if err != nil {
+			u.log.Error(ctx, "Invalid state init: "+err.Error())
+			return "", "", fmt.Errorf("invalid state init: %w", err)
+		}
+		claimed = addr
+	}
+	if claimed == "" {
+		var err error
+		candidates, err = candidateWallets(ctx, provider, req.PublicKey, preference)
+		if err != nil {
+			u.log.Error(ctx, "Failed to resolve wallet: "+err.Error())
+			return "", "", fmt.Errorf("failed to resolve wallet: %w", err)
+		}
+		if len(candidates) == 0 {
+			u.log.Error(ctx, "Wallet not found for public key")
+			return "", "", errors.New("wallet not found")
+		}
+		claimed = candidates[0].Address
 	}
 
-	var data struct {
-		Wallets []struct {
-			Address string `json:"address"`
-		} `json:"wallets"`
+	addr, err := chain.ParseAddress(claimed)
+	if err != nil {
+		u.log.Error(ctx, "Invalid wallet address: "+err.Error())
+		return "", "", fmt.Errorf("invalid address: %w", err)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", err
+	if addr.IsTestnetOnly() && network != chain.Testnet {
+		u.log.Error(ctx, "Testnet-only address used on "+network.Name()+": "+claimed)
+		return "", "", errors.New("address is testnet-only")
 	}
 
-	if len(data.Wallets) == 0 {
-		return "", errors.New("wallet not found")
+	version, ok := tonwallet.Match(addr, req.PublicKey, opts)
+	if !ok {
+		if candidates == nil {
+			candidates, err = candidateWallets(ctx, provider, req.PublicKey, preference)
+			if err != nil {
+				u.log.Error(ctx, "Failed to list wallets: "+err.Error())
+				return "", "", fmt.Errorf("failed to resolve wallet: %w", err)
+			}
+		}
+		if !containsWallet(candidates, addr) {
+			u.log.Error(ctx, "Address is not a wallet of the public key: "+claimed)
+			return "", "", errors.New("address does not belong to public key")
+		}
+		u.log.Info(ctx, "Address is not a standard wallet of the public key, checking on chain: "+claimed)
 	}
 
-	return data.Wallets[0].Address, nil
+	return addr.StringRaw(), version, nil
 }
 
-func (u *VerifyUseCaseImpl) isWalletActive(addr string) (bool, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v2/accounts/%s", u.apiURL, addr), nil)
+// verifyOwnership checks that the deployed wallet is controlled by the public key, by
+// running get_public_key or reading the key from the data of a known wallet contract.
+func (u *VerifyUseCaseImpl) verifyOwnership(ctx context.Context, provider chain.ChainProvider, acc *chain.Account, pub ed25519.PublicKey) (tonwallet.Version, error) {
+	key, version, err := chain.WalletPublicKey(ctx, provider, acc)
 	if err != nil {
-		return false, err
+		u.log.Error(ctx, "Failed to read wallet public key: "+err.Error())
+		return "", fmt.Errorf("failed to verify wallet ownership: %w", err)
 	}
-	req.Header.Set("Authorization", "Bearer "+u.apiKey)
-
-	resp, err := u.client.Do(req)
-	if err != nil {
-		return false, err
+	if !bytes.Equal(key, pub) {
+		u.log.Error(ctx, "Public key does not control wallet: "+acc.Address)
+		return "", errors.New("address does not belong to public key")
+	}
+	if version == "" {
+		u.log.Error(ctx, "Unknown wallet contract: "+acc.Address)
+		return "", fmt.Errorf("failed to verify wallet ownership: %w", chain.ErrUnknownWallet)
 	}
-	defer resp.Body.Close()
+	return version, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("TonAPI returned status %d", resp.StatusCode)
+// verifyStateInit accepts a wallet that is not deployed yet when the StateInit sent by
+// the wallet hashes to its address and embeds the public key.
+func (u *VerifyUseCaseImpl) verifyStateInit(ctx context.Context, acc *chain.Account, req dto.VerifyRequestDTO) (tonwallet.Version, error) {
+	if len(req.StateInit) == 0 {
+		u.log.Error(ctx, "Wallet is not active and no state init was provided: "+acc.Address)
+		return "", errors.New("wallet is not active")
 	}
 
-	var data struct {
-		Status string `json:"status"`
+	state, err := tonwallet.ParseStateInit(req.StateInit)
+	if err != nil {
+		u.log.Error(ctx, "Invalid state init: "+err.Error())
+		return "", fmt.Errorf("invalid state init: %w", err)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return false, err
+	addr, err := chain.ParseAddress(acc.Address)
+	if err != nil {
+		return "", fmt.Errorf("invalid address: %w", err)
 	}
 
-	return data.Status == "active", nil
+	version, err := tonwallet.CheckStateInit(addr, state, req.PublicKey)
+	if err != nil {
+		u.log.Error(ctx, "State init rejected for "+acc.Address+": "+err.Error())
+		return "", fmt.Errorf("invalid state init: %w", err)
+	}
+	return version, nil
 }
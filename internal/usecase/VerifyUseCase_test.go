@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"TON/internal/chain"
+	"TON/internal/dto"
+	"TON/internal/lists"
+	"TON/internal/multisig"
+	"TON/pkg/did"
+	"TON/pkg/logger"
+	"TON/pkg/tonwallet"
+	"crypto/ed25519"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+const testMultisig = "0:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+
+// setOwners defines the get-methods of a multisig wallet v1 owned by the keys.
+func setOwners(t *testing.T, p *chain.FixtureProvider, threshold int64, keys ...ed25519.PublicKey) {
+	t.Helper()
+	d := cell.NewDict(8)
+	for i, k := range keys {
+		if err := d.SetIntKey(big.NewInt(int64(i)), cell.BeginCell().MustStoreSlice(k, 256).EndCell()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.SetGetMethod(testMultisig, "get_n_k", []chain.StackEntry{
+		{Type: chain.StackNum, Num: big.NewInt(int64(len(keys)))},
+		{Type: chain.StackNum, Num: big.NewInt(threshold)},
+	})
+	p.SetGetMethod(testMultisig, "get_public_keys", []chain.StackEntry{
+		{Type: chain.StackCell, Cell: d.AsCell().ToBOC()},
+		{Type: chain.StackNum, Num: big.NewInt(0)},
+	})
+}
+
+func TestIdentifyMultisig(t *testing.T) {
+	keys := make([]ed25519.PublicKey, 3)
+	subs := make([]string, 3)
+	for i := range keys {
+		keys[i], _, _ = ed25519.GenerateKey(nil)
+		subs[i], _ = did.Key(keys[i])
+	}
+
+	p := chain.NewFixtureProvider(tonwallet.Options{})
+	p.SetAccount(chain.Account{Address: testMultisig, Status: chain.StatusActive})
+	setOwners(t, p, 2, keys...)
+	store, err := lists.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := NewVerifyUseCase("TON-OAUTH", time.Minute, logger.New("test"), VerifyDeps{
+		Providers:      chain.Providers{chain.Mainnet: p},
+		DefaultNetwork: chain.Mainnet,
+		Lists:          store,
+	})
+
+	owners, err := multisig.ReadOwners(t.Context(), p, testMultisig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collector := multisig.NewCollector("TON-OAUTH", time.Minute, 0)
+	s, err := collector.Start("", "", chain.Mainnet, testMultisig, owners)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the third owner completes the session after the first one signed
+	for _, k := range []ed25519.PublicKey{keys[0], keys[2]} {
+		if s, err = collector.Approve(s.ID, k, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	id, err := u.IdentifyMultisig(s, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"sub": subs[2], "act": map[string]interface{}{"sub": subs[0]}}
+	if act := id.Claims()["act"]; !reflect.DeepEqual(act, want) {
+		t.Fatalf("act = %v, want %v", act, want)
+	}
+	if signers, _ := id.Claims()["signers"].([]map[string]interface{}); len(signers) != 2 {
+		t.Fatalf("signers = %v, want two signers", id.Claims()["signers"])
+	}
+
+	// the owners are read again: without the third owner the threshold is missed
+	setOwners(t, p, 2, keys[0], keys[1])
+	if _, err := u.IdentifyMultisig(s, ""); !errors.Is(err, multisig.ErrThreshold) {
+		t.Fatalf("IdentifyMultisig() after removing an owner error = %v, want %v", err, multisig.ErrThreshold)
+	}
+}
+
+func TestIdentifyUndeployedWallet(t *testing.T) {
+	// TonAPI answers 404 for accounts it has never seen
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	state, err := tonwallet.StateInit(pub, tonwallet.V4R2, tonwallet.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	boc, err := tonwallet.StateInitBoC(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := lists.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := NewVerifyUseCase("TON-OAUTH", time.Minute, logger.New("test"), VerifyDeps{
+		Providers:      chain.Providers{chain.Mainnet: chain.NewTonAPIProvider(srv.URL, "", time.Second)},
+		DefaultNetwork: chain.Mainnet,
+		Lists:          store,
+	})
+
+	msg := "TON-OAUTH:" + time.Now().UTC().Format(time.RFC3339)
+	req := dto.VerifyRequestDTO{Message: msg, Signature: ed25519.Sign(priv, []byte(msg)), PublicKey: pub}
+	if _, err := u.Identify(req); err == nil {
+		t.Fatal("Identify() without state init succeeded, want an error")
+	}
+
+	req.StateInit = boc
+	id, err := u.Identify(req)
+	if err != nil {
+		t.Fatalf("Identify() error = %v", err)
+	}
+	want, _ := tonwallet.Address(pub, tonwallet.V4R2, tonwallet.Options{})
+	if id.Address != want.StringRaw() || id.State != chain.StatusNonexist || id.Version != tonwallet.V4R2 {
+		t.Fatalf("Identify() = %s %s %s, want %s nonexist v4r2", id.Address, id.State, id.Version, want.StringRaw())
+	}
+}
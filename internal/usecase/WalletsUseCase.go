@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"TON/internal/chain"
+	"TON/internal/client"
+	"TON/internal/dto"
+	tonaddr "TON/pkg/address"
+	"TON/pkg/logger"
+	"TON/pkg/tonwallet"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/xssnick/tonutils-go/address"
+)
+
+type WalletsUseCase interface {
+	ListWallets(req dto.WalletsRequestDTO) (*dto.WalletsResponseDTO, error)
+}
+
+type WalletsUseCaseImpl struct {
+	log            logger.Logger
+	providers      chain.Providers
+	defaultNetwork chain.Network
+	clients        *client.Registry
+	preference     tonwallet.Preference
+	addressFormat  tonaddr.Format
+}
+
+func NewWalletsUseCase(log logger.Logger, providers chain.Providers, defaultNetwork chain.Network, clients *client.Registry, preference tonwallet.Preference, addressFormat tonaddr.Format) WalletsUseCase {
+	return &WalletsUseCaseImpl{
+		log:            log,
+		providers:      providers,
+		defaultNetwork: defaultNetwork,
+		clients:        clients,
+		preference:     preference,
+		addressFormat:  addressFormat,
+	}
+}
+
+func (u *WalletsUseCaseImpl) ListWallets(req dto.WalletsRequestDTO) (*dto.WalletsResponseDTO, error) {
+	ctx := context.Background()
+
+	pub, err := hex.DecodeString(req.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid public key")
+	}
+
+	c, err := lookupClient(u.clients, req.ClientID, false)
+	if err != nil {
+		return nil, err
+	}
+	network, err := selectNetwork(u.defaultNetwork, req.Network, c)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := u.providers.Get(network)
+	if err != nil {
+		return nil, err
+	}
+
+	wallets, err := candidateWallets(ctx, provider, pub, preferenceFor(c, u.preference))
+	if err != nil {
+		u.log.Error(ctx, "Failed to list wallets: "+err.Error())
+		return nil, fmt.Errorf("failed to list wallets: %w", err)
+	}
+
+	resp := &dto.WalletsResponseDTO{
+		Network: string(network),
+		Wallets: make([]dto.WalletDTO, 0, len(wallets)),
+	}
+	format := addressFormatFor(c, u.addressFormat)
+	for i, w := range wallets {
+		resp.Wallets = append(resp.Wallets, dto.WalletDTO{
+			Address:   renderAddress(w.Address, format, network),
+			Version:   w.Version,
+			Balance:   w.Balance,
+			Status:    string(w.Status),
+			Preferred: i == 0,
+		})
+	}
+	return resp, nil
+}
+
+// preferenceFor returns the wallet version preference of the client or the default one.
+func preferenceFor(c *client.Client, def tonwallet.Preference) tonwallet.Preference {
+	if c != nil && c.Preference() != nil {
+		return c.Preference()
+	}
+	return def
+}
+
+// addressFormatFor returns the address format of the client or the default one.
+func addressFormatFor(c *client.Client, def tonaddr.Format) tonaddr.Format {
+	if c != nil && c.AddressFormat != "" {
+		return tonaddr.Format(c.AddressFormat)
+	}
+	return def
+}
+
+// renderAddress renders a raw address in the format, user-friendly testnet addresses
+// carry the testnet flag. Unparsable addresses are returned as is.
+func renderAddress(raw string, format tonaddr.Format, network chain.Network) string {
+	a, err := tonaddr.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return a.Format(format, network == chain.Testnet)
+}
+
+// candidateWallets lists the wallets of the key known to the provider, the preferred
+// version first and wallets of the same version by descending balance.
+func candidateWallets(ctx context.Context, provider chain.ChainProvider, pub ed25519.PublicKey, preference tonwallet.Preference) ([]chain.Wallet, error) {
+	wallets, err := provider.GetWallets(ctx, pub)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(wallets, func(i, j int) bool {
+		ri := preference.Rank(tonwallet.Version(wallets[i].Version))
+		rj := preference.Rank(tonwallet.Version(wallets[j].Version))
+		if ri != rj {
+			return ri < rj
+		}
+		return wallets[i].Balance > wallets[j].Balance
+	})
+	return wallets, nil
+}
+
+// getAccount reads the account from the provider. Providers differ on accounts they have
+// never seen: some return an empty account and some ErrNotFound, both mean nonexist.
+func getAccount(ctx context.Context, provider chain.ChainProvider, addr string) (*chain.Account, error) {
+	acc, err := provider.GetAccount(ctx, addr)
+	if errors.Is(err, chain.ErrNotFound) {
+		return &chain.Account{Address: addr, Status: chain.StatusNonexist}, nil
+	}
+	return acc, err
+}
+
+func containsWallet(wallets []chain.Wallet, addr *address.Address) bool {
+	for _, w := range wallets {
+		a, err := chain.ParseAddress(w.Address)
+		if err == nil && a.Workchain() == addr.Workchain() && bytes.Equal(a.Data(), addr.Data()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package address
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	flagBounceable    = 0x11
+	flagNonBounceable = 0x51
+	flagTestnet       = 0x80
+
+	friendlyLen = 48
+	hashLen     = 32
+)
+
+var (
+	ErrInvalidAddress = errors.New("invalid address")
+	ErrChecksum       = errors.New("address checksum mismatch")
+	ErrUnknownFormat  = errors.New("unknown address format")
+)
+
+// Address is a TON account address. Bounceable and Testnet are only carried by
+// the user-friendly form; raw addresses parse as bounceable mainnet addresses.
+type Address struct {
+	Workchain  int8
+	Hash       [hashLen]byte
+	Bounceable bool
+	Testnet    bool
+}
+
+// Format selects how an address is rendered.
+type Format string
+
+const (
+	// FormatRaw renders "wc:hex", the canonical form used for storage and the sub claim.
+	FormatRaw Format = "raw"
+	// FormatBounceable renders the base64url user-friendly form with the bounceable flag (EQ…).
+	FormatBounceable Format = "bounceable"
+	// FormatNonBounceable renders the base64url user-friendly form without the bounceable flag (UQ…).
+	FormatNonBounceable Format = "non-bounceable"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatRaw, FormatBounceable, FormatNonBounceable:
+		return f, nil
+	case "":
+		return FormatRaw, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, s)
+	}
+}
+
+// Parse accepts the raw "wc:hex" form and the user-friendly form in base64 or base64url.
+func Parse(s string) (*Address, error) {
+	if strings.Contains(s, ":") {
+		return ParseRaw(s)
+	}
+	return ParseFriendly(s)
+}
+
+// ParseRaw parses the "wc:hex" form.
+func ParseRaw(s string) (*Address, error) {
+	wcStr, hashStr, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing workchain separator", ErrInvalidAddress)
+	}
+	wc, err := strconv.ParseInt(wcStr, 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid workchain %q", ErrInvalidAddress, wcStr)
+	}
+	hash, err := hex.DecodeString(hashStr)
+	if err != nil || len(hash) != hashLen {
+		return nil, fmt.Errorf("%w: account id must be 64 hex characters", ErrInvalidAddress)
+	}
+
+	a := &Address{Workchain: int8(wc), Bounceable: true}
+	copy(a.Hash[:], hash)
+	return a, nil
+}
+
+// ParseFriendly parses the 48 character user-friendly form in base64 or base64url
+// and checks its CRC16.
+func ParseFriendly(s string) (*Address, error) {
+	if len(s) != friendlyLen {
+		return nil, fmt.Errorf("%w: user-friendly address must be %d characters", ErrInvalidAddress, friendlyLen)
+	}
+	data, err := base64.URLEncoding.DecodeString(strings.NewReplacer("+", "-", "/", "_").Replace(s))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid base64", ErrInvalidAddress)
+	}
+
+	if binary.BigEndian.Uint16(data[34:]) != crc16(data[:34]) {
+		return nil, ErrChecksum
+	}
+
+	flags := data[0]
+	a := &Address{
+		Workchain: int8(data[1]),
+		Testnet:   flags&flagTestnet != 0,
+	}
+	switch flags &^ flagTestnet {
+	case flagBounceable:
+		a.Bounceable = true
+	case flagNonBounceable:
+	default:
+		return nil, fmt.Errorf("%w: unknown flags 0x%02x", ErrInvalidAddress, flags)
+	}
+	copy(a.Hash[:], data[2:34])
+	return a, nil
+}
+
+// Normalize converts an address in any supported form to the raw form.
+func Normalize(s string) (string, error) {
+	a, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return a.Raw(), nil
+}
+
+// Raw returns the "wc:hex" form.
+func (a *Address) Raw() string {
+	return fmt.Sprintf("%d:%x", a.Workchain, a.Hash[:])
+}
+
+// Friendly returns the user-friendly form with the given flags.
+func (a *Address) Friendly(bounceable, testnet, urlSafe bool) string {
+	data := make([]byte, 36)
+	data[0] = flagNonBounceable
+	if bounceable {
+		data[0] = flagBounceable
+	}
+	if testnet {
+		data[0] |= flagTestnet
+	}
+	data[1] = byte(a.Workchain)
+	copy(data[2:34], a.Hash[:])
+	binary.BigEndian.PutUint16(data[34:], crc16(data[:34]))
+
+	if urlSafe {
+		return base64.URLEncoding.EncodeToString(data)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// Format renders the address in the format. User-friendly forms are base64url and carry
+// the testnet flag when testnet is set.
+func (a *Address) Format(f Format, testnet bool) string {
+	switch f {
+	case FormatBounceable:
+		return a.Friendly(true, testnet, true)
+	case FormatNonBounceable:
+		return a.Friendly(false, testnet, true)
+	default:
+		return a.Raw()
+	}
+}
+
+// Equal reports whether both addresses point to the same account, ignoring flags.
+func (a *Address) Equal(b *Address) bool {
+	return a.Workchain == b.Workchain && bytes.Equal(a.Hash[:], b.Hash[:])
+}
+
+func (a *Address) String() string {
+	return a.Raw()
+}
+
+// crc16 is CRC-16/XMODEM, the checksum of user-friendly addresses.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
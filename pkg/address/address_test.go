@@ -0,0 +1,110 @@
+package address
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	hashA = "ba295e33b3c4c9b5265aa4ead1166a92931ce9abea120a8c5e91044a1257f89c"
+	hashB = "930d5533980aba11fcd81845a954ebf5eb2a3e1f9570dc1d2b92d722773fd42c"
+)
+
+func TestCRC16(t *testing.T) {
+	// check value of CRC-16/XMODEM
+	if got := crc16([]byte("123456789")); got != 0x31c3 {
+		t.Fatalf("crc16() = %#04x, want 0x31c3", got)
+	}
+}
+
+func TestFriendly(t *testing.T) {
+	tests := []struct {
+		raw        string
+		bounceable bool
+		testnet    bool
+		want       string
+	}{
+		{"0:" + hashA, true, false, "EQC6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nC1I"},
+		{"0:" + hashB, true, false, "EQCTDVUzmAq6EfzYGEWpVOv16yo-H5Vw3B0rktcidz_ULOUj"},
+		{"0:" + hashA, false, false, "UQC6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nHCN"},
+		{"0:" + hashB, false, false, "UQCTDVUzmAq6EfzYGEWpVOv16yo-H5Vw3B0rktcidz_ULLjm"},
+		{"0:" + hashA, true, true, "kQC6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nJbC"},
+		{"0:" + hashA, false, true, "0QC6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nMsH"},
+		{"1:" + hashA, false, true, "0QG6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nEbb"},
+		{"1:" + hashB, false, true, "0QGTDVUzmAq6EfzYGEWpVOv16yo-H5Vw3B0rktcidz_ULI6w"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			a, err := ParseRaw(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := a.Friendly(tt.bounceable, tt.testnet, true); got != tt.want {
+				t.Fatalf("Friendly() = %s, want %s", got, tt.want)
+			}
+
+			parsed, err := Parse(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Raw() != tt.raw || parsed.Bounceable != tt.bounceable || parsed.Testnet != tt.testnet {
+				t.Fatalf("Parse() = %s bounceable=%v testnet=%v", parsed.Raw(), parsed.Bounceable, parsed.Testnet)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr error
+	}{
+		{"raw", "0:" + hashA, "0:" + hashA, nil},
+		{"raw masterchain", "-1:" + hashB, "-1:" + hashB, nil},
+		{"standard base64", "EQCTDVUzmAq6EfzYGEWpVOv16yo+H5Vw3B0rktcidz/ULOUj", "0:" + hashB, nil},
+		{"checksum mismatch", "EQC6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nC1J", "", ErrChecksum},
+		{"short", "EQC6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nC1", "", ErrInvalidAddress},
+		{"short hash", "0:" + hashA[:62], "", ErrInvalidAddress},
+		{"bad workchain", "x:" + hashA, "", ErrInvalidAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Normalize() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("Normalize() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	a, err := ParseRaw("0:" + hashA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format  Format
+		testnet bool
+		want    string
+	}{
+		{FormatRaw, false, "0:" + hashA},
+		{FormatRaw, true, "0:" + hashA},
+		{FormatBounceable, false, "EQC6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nC1I"},
+		{FormatNonBounceable, true, "0QC6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nMsH"},
+	}
+	for _, tt := range tests {
+		if got := a.Format(tt.format, tt.testnet); got != tt.want {
+			t.Errorf("Format(%s, %v) = %s, want %s", tt.format, tt.testnet, got, tt.want)
+		}
+	}
+
+	if _, err := ParseFormat("hex"); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("ParseFormat() error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
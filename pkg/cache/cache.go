@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	fetchedAt time.Time
+}
+
+// Cache keeps values together with the time they were fetched. Readers decide how
+// stale a value may be, so one cache can serve callers with different requirements.
+type Cache[V any] struct {
+	mu      sync.RWMutex
+	entries map[string]entry[V]
+	maxAge  time.Duration
+}
+
+// New creates a cache whose entries are dropped by Cleanup once older than maxAge.
+func New[V any](maxAge time.Duration) *Cache[V] {
+	return &Cache[V]{
+		entries: make(map[string]entry[V]),
+		maxAge:  maxAge,
+	}
+}
+
+// Get returns the value of key if it was fetched at most maxAge ago.
+func (c *Cache[V]) Get(key string, maxAge time.Duration) (V, time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Since(e.fetchedAt) > maxAge {
+		var zero V
+		return zero, time.Time{}, false
+	}
+	return e.value, e.fetchedAt, true
+}
+
+func (c *Cache[V]) Set(key string, value V) time.Time {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry[V]{value: value, fetchedAt: now}
+	return now
+}
+
+// Fetch returns the cached value of key if it is at most maxAge old and otherwise
+// stores and returns the result of fetch. Errors are not cached.
+func (c *Cache[V]) Fetch(key string, maxAge time.Duration, fetch func() (V, error)) (V, time.Time, error) {
+	if v, at, ok := c.Get(key, maxAge); ok {
+		return v, at, nil
+	}
+
+	v, err := fetch()
+	if err != nil {
+		var zero V
+		return zero, time.Time{}, err
+	}
+	return v, c.Set(key, v), nil
+}
+
+// Cleanup drops entries older than the max age of the cache.
+func (c *Cache[V]) Cleanup() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, e := range c.entries {
+		if time.Since(e.fetchedAt) > c.maxAge {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Run periodically cleans up old entries until stop is closed.
+func (c *Cache[V]) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.Cleanup()
+		case <-stop:
+			return
+		}
+	}
+}
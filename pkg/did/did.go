@@ -0,0 +1,89 @@
+package did
+
+import (
+	"TON/pkg/address"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidDID is returned for identifiers that are no did:ton or did:key DIDs.
+var ErrInvalidDID = errors.New("invalid DID")
+
+const (
+	prefixTON = "did:ton:"
+	prefixKey = "did:key:"
+	// testnetSegment marks did:ton identifiers of testnet wallets.
+	testnetSegment = "testnet:"
+)
+
+// ed25519Codec is the multicodec prefix of ed25519 public keys, varint encoded.
+var ed25519Codec = []byte{0xed, 0x01}
+
+// TON returns the did:ton identifier of the wallet: did:ton:<wc>:<hex> on mainnet and
+// did:ton:testnet:<wc>:<hex> on testnet.
+func TON(addr string, testnet bool) (string, error) {
+	raw, err := address.Normalize(addr)
+	if err != nil {
+		return "", err
+	}
+	if testnet {
+		return prefixTON + testnetSegment + raw, nil
+	}
+	return prefixTON + raw, nil
+}
+
+// ParseTON returns the raw address and network of a did:ton identifier. The address may
+// be given in any format address.Parse accepts.
+func ParseTON(id string) (addr string, testnet bool, err error) {
+	rest, ok := strings.CutPrefix(id, prefixTON)
+	if !ok {
+		return "", false, fmt.Errorf("%w: %q is no did:ton identifier", ErrInvalidDID, id)
+	}
+	rest, testnet = strings.CutPrefix(rest, testnetSegment)
+	addr, err = address.Normalize(rest)
+	if err != nil {
+		return "", false, fmt.Errorf("%w: %w", ErrInvalidDID, err)
+	}
+	return addr, testnet, nil
+}
+
+// Key returns the did:key identifier of an ed25519 public key, did:key:z6Mk...
+func Key(pub ed25519.PublicKey) (string, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return "", fmt.Errorf("%w: invalid public key length", ErrInvalidDID)
+	}
+	return prefixKey + Multibase(pub), nil
+}
+
+// Multibase encodes an ed25519 public key as multicodec key in base58btc multibase,
+// as used by did:key and Ed25519VerificationKey2020 verification methods.
+func Multibase(pub ed25519.PublicKey) string {
+	return "z" + base58(append(append([]byte{}, ed25519Codec...), pub...))
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58 encodes data with the bitcoin alphabet, leading zero bytes as '1'.
+func base58(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	base, mod := big.NewInt(58), new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
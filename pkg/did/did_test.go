@@ -0,0 +1,90 @@
+package did
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	// vectors of the bitcoin base58 encoder
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"61", "2g"},
+		{"626262", "a3gV"},
+		{"636363", "aPEr"},
+		{"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+		{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+		{"516b6fcd0f", "ABnLTmg"},
+		{"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+		{"572e4794", "3EFU7m"},
+		{"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+		{"10c8511e", "Rt5zm"},
+		{"00000000000000000000", "1111111111"},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.in)
+		if got := base58(data); got != tt.want {
+			t.Errorf("base58(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	// did:key test vector of the ed25519 key with the all-zero seed
+	pub := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)).Public().(ed25519.PublicKey)
+	const want = "did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"
+
+	got, err := Key(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("Key() = %s, want %s", got, want)
+	}
+	if mb := Multibase(pub); "did:key:"+mb != want {
+		t.Fatalf("Multibase() = %s", mb)
+	}
+	if _, err := Key(pub[:31]); !errors.Is(err, ErrInvalidDID) {
+		t.Fatalf("Key() of a short key error = %v, want %v", err, ErrInvalidDID)
+	}
+}
+
+func TestParseTON(t *testing.T) {
+	const raw = "0:ba295e33b3c4c9b5265aa4ead1166a92931ce9abea120a8c5e91044a1257f89c"
+
+	tests := []struct {
+		id        string
+		addr      string
+		testnet   bool
+		canonical string
+		wantErr   error
+	}{
+		{"did:ton:" + raw, raw, false, "did:ton:" + raw, nil},
+		{"did:ton:testnet:" + raw, raw, true, "did:ton:testnet:" + raw, nil},
+		{"did:ton:EQC6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nC1I", raw, false, "did:ton:" + raw, nil},
+		{"did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp", "", false, "", ErrInvalidDID},
+		{"did:ton:0:1234", "", false, "", ErrInvalidDID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			addr, testnet, err := ParseTON(tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseTON() error = %v, want %v", err, tt.wantErr)
+			}
+			if addr != tt.addr || testnet != tt.testnet {
+				t.Fatalf("ParseTON() = %s testnet=%v, want %s testnet=%v", addr, testnet, tt.addr, tt.testnet)
+			}
+			if err != nil {
+				return
+			}
+			if id, err := TON(addr, testnet); err != nil || id != tt.canonical {
+				t.Fatalf("TON() = %s, %v, want %s", id, err, tt.canonical)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package did
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned for did:ton identifiers of wallets that do not exist.
+	ErrNotFound = errors.New("DID not found")
+	// ErrMethodNotSupported is returned for DIDs of methods other than did:ton.
+	ErrMethodNotSupported = errors.New("DID method not supported")
+)
+
+const (
+	contextDID     = "https://www.w3.org/ns/did/v1"
+	contextEd25519 = "https://w3id.org/security/suites/ed25519-2020/v1"
+
+	// VerificationKeyType is the type of the ed25519 verification methods.
+	VerificationKeyType = "Ed25519VerificationKey2020"
+	// ServiceTonSite is the type of the services of TON DNS site records.
+	ServiceTonSite = "TonSite"
+	// ServiceTonStorage is the type of the services of TON DNS storage records.
+	ServiceTonStorage = "TonStorage"
+)
+
+// Document is a DID document.
+type Document struct {
+	Context            []string             `json:"@context"`
+	ID                 string               `json:"id"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod,omitempty"`
+	Authentication     []string             `json:"authentication,omitempty"`
+	AssertionMethod    []string             `json:"assertionMethod,omitempty"`
+	Service            []Service            `json:"service,omitempty"`
+}
+
+type VerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyMultibase string `json:"publicKeyMultibase"`
+}
+
+type Service struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// Metadata describes the wallet behind a resolved document.
+type Metadata struct {
+	// Deactivated is set for frozen wallets, which can no longer sign.
+	Deactivated bool `json:"deactivated,omitempty"`
+	// CanonicalID is the did:ton with the raw address, set when the DID was resolved
+	// from another address format.
+	CanonicalID string `json:"canonicalId,omitempty"`
+	// AccountStatus is the on-chain status: active, uninit or frozen.
+	AccountStatus string `json:"accountStatus,omitempty"`
+	// WalletVersion is the detected wallet contract version, empty when unknown.
+	WalletVersion string `json:"walletVersion,omitempty"`
+	// DNSName is the TON DNS name of the wallet the services are read from.
+	DNSName string `json:"dnsName,omitempty"`
+}
+
+// Wallet is the on-chain state of a wallet.
+type Wallet struct {
+	// Status is the account status: active, uninit, frozen or nonexist.
+	Status string
+	// PublicKey is the key controlling the wallet, nil when it cannot be read.
+	PublicKey ed25519.PublicKey
+	Version   string
+	// Name is the TON DNS name of the wallet, "" when it has none. It must resolve
+	// back to the wallet.
+	Name string
+}
+
+// Records are the TON DNS records of a domain.
+type Records struct {
+	// Sites are the ADNL addresses of the site record in hex.
+	Sites []string
+	// Storage is the TON Storage bag ID in hex, "" when the domain has none.
+	Storage string
+}
+
+// Chain reads the wallets and DNS records of a TON network.
+type Chain interface {
+	Wallet(ctx context.Context, addr string) (*Wallet, error)
+	// Records returns the records of the domain, empty records when it has none.
+	Records(ctx context.Context, domain string) (*Records, error)
+}
+
+// Resolver builds did:ton documents from the chain.
+type Resolver struct {
+	Mainnet Chain
+	// Testnet resolves did:ton:testnet identifiers, nil when testnet is not supported.
+	Testnet Chain
+}
+
+// Resolve returns the document of a did:ton identifier: the on-chain public key of the
+// wallet as verification method and the records of its TON DNS name as services.
+func (r *Resolver) Resolve(ctx context.Context, id string) (*Document, *Metadata, error) {
+	rest, ok := strings.CutPrefix(id, "did:")
+	method, _, found := strings.Cut(rest, ":")
+	if !ok || !found || method == "" {
+		return nil, nil, fmt.Errorf("%w: %q", ErrInvalidDID, id)
+	}
+	if method != "ton" {
+		return nil, nil, fmt.Errorf("%w: %s", ErrMethodNotSupported, method)
+	}
+
+	addr, testnet, err := ParseTON(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	c := r.Mainnet
+	if testnet {
+		c = r.Testnet
+	}
+	if c == nil {
+		return nil, nil, fmt.Errorf("%w: network is not supported", ErrNotFound)
+	}
+
+	w, err := c.Wallet(ctx, addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if w.Status == "nonexist" {
+		return nil, nil, fmt.Errorf("%w: wallet does not exist", ErrNotFound)
+	}
+
+	canonical, err := TON(addr, testnet)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc := &Document{
+		Context: []string{contextDID, contextEd25519},
+		ID:      canonical,
+	}
+	meta := &Metadata{
+		Deactivated:   w.Status == "frozen",
+		AccountStatus: w.Status,
+		WalletVersion: w.Version,
+		DNSName:       w.Name,
+	}
+	if canonical != id {
+		meta.CanonicalID = canonical
+	}
+
+	if len(w.PublicKey) == ed25519.PublicKeySize && !meta.Deactivated {
+		keyID := canonical + "#key-1"
+		doc.VerificationMethod = []VerificationMethod{{
+			ID:                 keyID,
+			Type:               VerificationKeyType,
+			Controller:         canonical,
+			PublicKeyMultibase: Multibase(w.PublicKey),
+		}}
+		doc.Authentication = []string{keyID}
+		doc.AssertionMethod = []string{keyID}
+	}
+
+	if w.Name != "" {
+		records, err := c.Records(ctx, w.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		doc.Service = services(canonical, records)
+	}
+
+	return doc, meta, nil
+}
+
+// services describes the site and storage records: sites by adnl:// URIs of their ADNL
+// addresses, storage by the tonstorage:// URI of its bag.
+func services(id string, records *Records) []Service {
+	var out []Service
+	for i, site := range records.Sites {
+		out = append(out, Service{
+			ID:              id + "#site-" + strconv.Itoa(i+1),
+			Type:            ServiceTonSite,
+			ServiceEndpoint: "adnl://" + strings.ToLower(site),
+		})
+	}
+	if records.Storage != "" {
+		out = append(out, Service{
+			ID:              id + "#storage",
+			Type:            ServiceTonStorage,
+			ServiceEndpoint: "tonstorage://" + strings.ToUpper(records.Storage),
+		})
+	}
+	return out
+}
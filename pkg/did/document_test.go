@@ -0,0 +1,112 @@
+package did
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const (
+	rawA = "0:ba295e33b3c4c9b5265aa4ead1166a92931ce9abea120a8c5e91044a1257f89c"
+	rawB = "0:930d5533980aba11fcd81845a954ebf5eb2a3e1f9570dc1d2b92d722773fd42c"
+)
+
+// fakeChain serves wallets and DNS records from maps.
+type fakeChain struct {
+	wallets map[string]*Wallet
+	records map[string]*Records
+}
+
+func (c *fakeChain) Wallet(_ context.Context, addr string) (*Wallet, error) {
+	if w, ok := c.wallets[addr]; ok {
+		return w, nil
+	}
+	return &Wallet{Status: "nonexist"}, nil
+}
+
+func (c *fakeChain) Records(_ context.Context, domain string) (*Records, error) {
+	if r, ok := c.records[domain]; ok {
+		return r, nil
+	}
+	return &Records{}, nil
+}
+
+func TestResolve(t *testing.T) {
+	pub := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)).Public().(ed25519.PublicKey)
+	r := &Resolver{Mainnet: &fakeChain{
+		wallets: map[string]*Wallet{
+			rawA: {Status: "active", PublicKey: pub, Version: "v4r2", Name: "alice.ton"},
+			rawB: {Status: "frozen", PublicKey: pub},
+		},
+		records: map[string]*Records{
+			"alice.ton": {Sites: []string{"AB12"}, Storage: "cd34"},
+		},
+	}}
+	ctx := context.Background()
+
+	id := "did:ton:" + rawA
+	doc, meta, err := r.Resolve(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID := id + "#key-1"
+	want := &Document{
+		Context: []string{contextDID, contextEd25519},
+		ID:      id,
+		VerificationMethod: []VerificationMethod{{
+			ID:                 keyID,
+			Type:               VerificationKeyType,
+			Controller:         id,
+			PublicKeyMultibase: "z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp",
+		}},
+		Authentication:  []string{keyID},
+		AssertionMethod: []string{keyID},
+		Service: []Service{
+			{ID: id + "#site-1", Type: ServiceTonSite, ServiceEndpoint: "adnl://ab12"},
+			{ID: id + "#storage", Type: ServiceTonStorage, ServiceEndpoint: "tonstorage://CD34"},
+		},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Fatalf("Resolve() document = %+v, want %+v", doc, want)
+	}
+	wantMeta := &Metadata{AccountStatus: "active", WalletVersion: "v4r2", DNSName: "alice.ton"}
+	if !reflect.DeepEqual(meta, wantMeta) {
+		t.Fatalf("Resolve() metadata = %+v, want %+v", meta, wantMeta)
+	}
+
+	// a friendly address resolves to the document of the raw address
+	doc, meta, err = r.Resolve(ctx, "did:ton:EQC6KV4zs8TJtSZapOrRFmqSkxzpq-oSCoxekQRKElf4nC1I")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.ID != id || meta.CanonicalID != id {
+		t.Fatalf("Resolve() of a friendly address = %s canonicalId=%s, want %s", doc.ID, meta.CanonicalID, id)
+	}
+
+	// frozen wallets can no longer sign
+	doc, meta, err = r.Resolve(ctx, "did:ton:"+rawB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !meta.Deactivated || doc.VerificationMethod != nil || doc.Authentication != nil {
+		t.Fatalf("Resolve() of a frozen wallet = %+v %+v, want deactivated without key", doc, meta)
+	}
+
+	tests := []struct {
+		id      string
+		wantErr error
+	}{
+		{"did:ton:0:1111111111111111111111111111111111111111111111111111111111111111", ErrNotFound},
+		{"did:ton:testnet:" + rawA, ErrNotFound},
+		{"did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp", ErrMethodNotSupported},
+		{"did:ton", ErrInvalidDID},
+		{"did:ton:0:1234", ErrInvalidDID},
+	}
+	for _, tt := range tests {
+		if _, _, err := r.Resolve(ctx, tt.id); !errors.Is(err, tt.wantErr) {
+			t.Errorf("Resolve(%s) error = %v, want %v", tt.id, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package signdata
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"strings"
+
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// Payload types of the TonConnect signData request.
+const (
+	TypeText   = "text"
+	TypeBinary = "binary"
+	TypeCell   = "cell"
+)
+
+const (
+	messagePrefix = "ton-connect/sign-data/"
+	cellPrefix    = 0x75569022
+	maxDomainLen  = 253
+)
+
+// Payload is the data a TonConnect wallet was asked to sign.
+type Payload struct {
+	Type string
+	// Text of text payloads.
+	Text string
+	// Bytes of binary payloads.
+	Bytes []byte
+	// Schema is the TL-B schema of cell payloads.
+	Schema string
+	// Cell of cell payloads.
+	Cell *cell.Cell
+}
+
+// Data is the signData result returned by a TonConnect wallet.
+type Data struct {
+	Payload     Payload
+	Domain      string
+	Timestamp   int64
+	Signature   []byte
+	AddressWC   int32
+	AddressHash []byte
+}
+
+// Hash returns the hash the wallet signs for the data:
+//
+//	text, binary: sha256(0xffff ++ utf8("ton-connect/sign-data/") ++ workchain(BE) ++ hash ++
+//	              domainLen(BE) ++ domain ++ timestamp(BE) ++ "txt"|"bin" ++ payloadLen(BE) ++ payload)
+//	cell:         hash of cell(0x75569022 ++ crc32(schema) ++ timestamp ++ address ++
+//	              ref(dns encoded domain) ++ ref(payload))
+func Hash(d Data) ([]byte, error) {
+	if len(d.AddressHash) != 32 {
+		return nil, errors.New("address hash must be 32 bytes")
+	}
+	if d.Domain == "" || len(d.Domain) > maxDomainLen {
+		return nil, errors.New("invalid domain length")
+	}
+	if d.Timestamp < 0 {
+		return nil, errors.New("invalid timestamp")
+	}
+
+	switch d.Payload.Type {
+	case TypeText:
+		return messageHash(d, "txt", []byte(d.Payload.Text)), nil
+	case TypeBinary:
+		return messageHash(d, "bin", d.Payload.Bytes), nil
+	case TypeCell:
+		return cellHash(d)
+	default:
+		return nil, errors.New("unknown payload type " + d.Payload.Type)
+	}
+}
+
+func messageHash(d Data, prefix string, payload []byte) []byte {
+	var msg bytes.Buffer
+	msg.Write([]byte{0xff, 0xff})
+	msg.WriteString(messagePrefix)
+	_ = binary.Write(&msg, binary.BigEndian, d.AddressWC)
+	msg.Write(d.AddressHash)
+	_ = binary.Write(&msg, binary.BigEndian, uint32(len(d.Domain)))
+	msg.WriteString(d.Domain)
+	_ = binary.Write(&msg, binary.BigEndian, uint64(d.Timestamp))
+	msg.WriteString(prefix)
+	_ = binary.Write(&msg, binary.BigEndian, uint32(len(payload)))
+	msg.Write(payload)
+
+	hash := sha256.Sum256(msg.Bytes())
+	return hash[:]
+}
+
+func cellHash(d Data) ([]byte, error) {
+	if d.Payload.Cell == nil {
+		return nil, errors.New("cell payload is empty")
+	}
+	if d.Payload.Schema == "" {
+		return nil, errors.New("cell payload has no schema")
+	}
+
+	domain := cell.BeginCell()
+	if err := domain.StoreBinarySnake(EncodeDomain(d.Domain)); err != nil {
+		return nil, err
+	}
+
+	msg := cell.BeginCell()
+	if err := msg.StoreUInt(cellPrefix, 32); err != nil {
+		return nil, err
+	}
+	if err := msg.StoreUInt(uint64(SchemaHash(d.Payload.Schema)), 32); err != nil {
+		return nil, err
+	}
+	if err := msg.StoreUInt(uint64(d.Timestamp), 64); err != nil {
+		return nil, err
+	}
+	if err := msg.StoreAddr(address.NewAddress(0, byte(d.AddressWC), d.AddressHash)); err != nil {
+		return nil, err
+	}
+	if err := msg.StoreRef(domain.EndCell()); err != nil {
+		return nil, err
+	}
+	if err := msg.StoreRef(d.Payload.Cell); err != nil {
+		return nil, err
+	}
+	return msg.EndCell().Hash(), nil
+}
+
+// SchemaHash is the crc32 of the TL-B schema of a cell payload.
+func SchemaHash(schema string) uint32 {
+	return crc32.ChecksumIEEE([]byte(schema))
+}
+
+// EncodeDomain encodes a domain like TON DNS: its labels in reverse order, each followed
+// by a zero byte, so "app.example.com" becomes "com\0example\0app\0".
+func EncodeDomain(domain string) []byte {
+	labels := strings.Split(domain, ".")
+	var out bytes.Buffer
+	for i := len(labels) - 1; i >= 0; i-- {
+		out.WriteString(labels[i])
+		out.WriteByte(0)
+	}
+	return out.Bytes()
+}
+
+// PayloadHash returns the hash identifying the payload itself: sha256 of text and binary
+// payloads and the representation hash of cell payloads.
+func PayloadHash(p Payload) ([]byte, error) {
+	switch p.Type {
+	case TypeText:
+		hash := sha256.Sum256([]byte(p.Text))
+		return hash[:], nil
+	case TypeBinary:
+		hash := sha256.Sum256(p.Bytes)
+		return hash[:], nil
+	case TypeCell:
+		if p.Cell == nil {
+			return nil, errors.New("cell payload is empty")
+		}
+		return p.Cell.Hash(), nil
+	default:
+		return nil, errors.New("unknown payload type " + p.Type)
+	}
+}
+
+// Verify checks the wallet signature over the hash of the data.
+func Verify(pub ed25519.PublicKey, d Data) error {
+	if len(pub) != ed25519.PublicKeySize {
+		return errors.New("invalid public key length")
+	}
+	if len(d.Signature) != ed25519.SignatureSize {
+		return errors.New("invalid signature length")
+	}
+
+	hash, err := Hash(d)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(pub, hash, d.Signature) {
+		return errors.New("invalid sign data signature")
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package signdata
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+const (
+	vectorAddress   = "960ab627408d5472d9d125b667cbe00ce17eeaa44e9dc6a86e93cdfef2c480d5"
+	vectorDomain    = "ton-connect.github.io"
+	vectorTimestamp = 1747303893
+	vectorText      = "Confirm the transfer of 10 TON to Alice"
+	vectorSchema    = "message#_ text:^Cell = Message;"
+)
+
+func vectorData(t *testing.T, p Payload) Data {
+	t.Helper()
+	hash, _ := hex.DecodeString(vectorAddress)
+	return Data{Payload: p, Domain: vectorDomain, Timestamp: vectorTimestamp, AddressHash: hash}
+}
+
+func vectorCell() *cell.Cell {
+	return cell.BeginCell().MustStoreUInt(0, 32).MustStoreStringSnake("Hello, TON!").EndCell()
+}
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		payload Payload
+		want    string
+	}{
+		{Payload{Type: TypeText, Text: vectorText}, "6ef9c3280431c72e52bb3a9c6910036e7040f382dac696206de47693dcc9f5ba"},
+		{Payload{Type: TypeBinary, Bytes: []byte("hello world")}, "0ae5b8b5b9042bebd1e3b746350942f5b5de74b4f268cee7bf11476d20a8ffc6"},
+		{Payload{Type: TypeCell, Schema: vectorSchema, Cell: vectorCell()}, "be8a7dac9fbee4143d5dba66995bebb799f7741d72feaa578c6becb36c84799a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.payload.Type, func(t *testing.T) {
+			got, err := Hash(vectorData(t, tt.payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Fatalf("Hash() = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashMessageLayout(t *testing.T) {
+	d := vectorData(t, Payload{Type: TypeText, Text: vectorText})
+
+	var msg []byte
+	msg = append(msg, 0xff, 0xff)
+	msg = append(msg, "ton-connect/sign-data/"...)
+	msg = append(msg, 0, 0, 0, 0)
+	msg = append(msg, d.AddressHash...)
+	msg = binary.BigEndian.AppendUint32(msg, uint32(len(vectorDomain)))
+	msg = append(msg, vectorDomain...)
+	msg = binary.BigEndian.AppendUint64(msg, vectorTimestamp)
+	msg = append(msg, "txt"...)
+	msg = binary.BigEndian.AppendUint32(msg, uint32(len(vectorText)))
+	msg = append(msg, vectorText...)
+	want := sha256.Sum256(msg)
+
+	got, err := Hash(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want[:]) {
+		t.Fatalf("Hash() = %x, want %x", got, want)
+	}
+}
+
+func TestHashErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(d *Data)
+	}{
+		{"short address hash", func(d *Data) { d.AddressHash = d.AddressHash[:31] }},
+		{"empty domain", func(d *Data) { d.Domain = "" }},
+		{"negative timestamp", func(d *Data) { d.Timestamp = -1 }},
+		{"unknown type", func(d *Data) { d.Payload.Type = "json" }},
+		{"cell without schema", func(d *Data) { d.Payload = Payload{Type: TypeCell, Cell: vectorCell()} }},
+		{"cell without cell", func(d *Data) { d.Payload = Payload{Type: TypeCell, Schema: vectorSchema} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := vectorData(t, Payload{Type: TypeText, Text: vectorText})
+			tt.modify(&d)
+			if _, err := Hash(d); err == nil {
+				t.Fatal("Hash() accepted invalid data")
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{10}, 32))
+	pub := priv.Public().(ed25519.PublicKey)
+
+	for _, p := range []Payload{
+		{Type: TypeText, Text: vectorText},
+		{Type: TypeBinary, Bytes: []byte("hello world")},
+		{Type: TypeCell, Schema: vectorSchema, Cell: vectorCell()},
+	} {
+		t.Run(p.Type, func(t *testing.T) {
+			d := vectorData(t, p)
+			hash, err := Hash(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d.Signature = ed25519.Sign(priv, hash)
+			if err := Verify(pub, d); err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+
+			d.Timestamp++
+			if err := Verify(pub, d); err == nil {
+				t.Fatal("Verify() accepted a signature over another timestamp")
+			}
+		})
+	}
+}
+
+func TestPayloadHash(t *testing.T) {
+	got, err := PayloadHash(Payload{Type: TypeBinary, Bytes: []byte("hello world")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"; hex.EncodeToString(got) != want {
+		t.Fatalf("PayloadHash() = %x, want %s", got, want)
+	}
+
+	c := vectorCell()
+	got, err = PayloadHash(Payload{Type: TypeCell, Cell: c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, c.Hash()) {
+		t.Fatalf("PayloadHash() = %x, want the cell hash %x", got, c.Hash())
+	}
+}
+
+func TestEncodeDomain(t *testing.T) {
+	if got := EncodeDomain("app.example.com"); string(got) != "com\x00example\x00app\x00" {
+		t.Fatalf("EncodeDomain() = %q", got)
+	}
+	// check value of CRC-32/IEEE
+	if got := SchemaHash("123456789"); got != 0xcbf43926 {
+		t.Fatalf("SchemaHash() = %#08x, want 0xcbf43926", got)
+	}
+}
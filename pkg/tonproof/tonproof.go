@@ -0,0 +1,79 @@
+package tonproof
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	itemPrefix    = "ton-proof-item-v2/"
+	connectPrefix = "ton-connect"
+	maxDomainLen  = 2048
+)
+
+// Proof is the ton_proof item returned by a TonConnect wallet.
+type Proof struct {
+	Timestamp   int64
+	DomainLen   uint32
+	Domain      string
+	Payload     string
+	Signature   []byte
+	AddressWC   int32
+	AddressHash []byte
+}
+
+// Message builds the ton_proof message:
+// utf8("ton-proof-item-v2/") ++ workchain(BE) ++ hash ++ domainLen(LE) ++ domain ++ timestamp(LE) ++ payload
+func Message(p Proof) ([]byte, error) {
+	if len(p.AddressHash) != 32 {
+		return nil, errors.New("address hash must be 32 bytes")
+	}
+	if p.DomainLen > maxDomainLen || len(p.Domain) > maxDomainLen {
+		return nil, errors.New("domain is too long")
+	}
+	if int(p.DomainLen) != len(p.Domain) {
+		return nil, errors.New("domain length mismatch")
+	}
+
+	var msg bytes.Buffer
+	msg.WriteString(itemPrefix)
+	_ = binary.Write(&msg, binary.BigEndian, p.AddressWC)
+	msg.Write(p.AddressHash)
+	_ = binary.Write(&msg, binary.LittleEndian, p.DomainLen)
+	msg.WriteString(p.Domain)
+	_ = binary.Write(&msg, binary.LittleEndian, p.Timestamp)
+	msg.WriteString(p.Payload)
+
+	return msg.Bytes(), nil
+}
+
+// Verify checks the wallet signature over the ton_proof message:
+// signature = Ed25519Sign(sha256(0xffff ++ utf8("ton-connect") ++ sha256(message)))
+func Verify(pub ed25519.PublicKey, p Proof) error {
+	if len(pub) != ed25519.PublicKeySize {
+		return errors.New("invalid public key length")
+	}
+	if len(p.Signature) != ed25519.SignatureSize {
+		return errors.New("invalid signature length")
+	}
+
+	msg, err := Message(p)
+	if err != nil {
+		return err
+	}
+	msgHash := sha256.Sum256(msg)
+
+	var full bytes.Buffer
+	full.Write([]byte{0xff, 0xff})
+	full.WriteString(connectPrefix)
+	full.Write(msgHash[:])
+	fullHash := sha256.Sum256(full.Bytes())
+
+	if !ed25519.Verify(pub, fullHash[:], p.Signature) {
+		return errors.New("invalid proof signature")
+	}
+	return nil
+}
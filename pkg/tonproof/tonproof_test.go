@@ -0,0 +1,98 @@
+package tonproof
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+)
+
+// Proof of a v4r2 wallet for ton-connect.github.io, signed by the wallet app.
+const (
+	vectorPublicKey = "a7a90c382278cf441d29db4abcbbe87e55e626f8ff826c36ea2cf1e089869d8b"
+	vectorAddress   = "960ab627408d5472d9d125b667cbe00ce17eeaa44e9dc6a86e93cdfef2c480d5"
+	vectorSignature = "3973665ce6ba325e66831d7709756e9e5e538d0888976a18abc767f4c51c0624ba3a365cb8961c72aa95325bb2d1dbb23979282581e0e15242cb273092374904"
+)
+
+func vectorProof(t *testing.T) (ed25519.PublicKey, Proof) {
+	t.Helper()
+	pub, _ := hex.DecodeString(vectorPublicKey)
+	hash, _ := hex.DecodeString(vectorAddress)
+	sig, _ := hex.DecodeString(vectorSignature)
+	return pub, Proof{
+		Timestamp:   1747303893,
+		DomainLen:   21,
+		Domain:      "ton-connect.github.io",
+		Payload:     "1747303885:",
+		Signature:   sig,
+		AddressWC:   0,
+		AddressHash: hash,
+	}
+}
+
+func TestVerify(t *testing.T) {
+	pub, valid := vectorProof(t)
+
+	tests := []struct {
+		name   string
+		modify func(p *Proof)
+		ok     bool
+	}{
+		{"valid", func(p *Proof) {}, true},
+		{"other payload", func(p *Proof) { p.Payload = "1747303886:" }, false},
+		{"other timestamp", func(p *Proof) { p.Timestamp++ }, false},
+		{"other domain", func(p *Proof) { p.Domain, p.DomainLen = "ton-connect.github.iO", 21 }, false},
+		{"other workchain", func(p *Proof) { p.AddressWC = -1 }, false},
+		{"domain length mismatch", func(p *Proof) { p.DomainLen = 20 }, false},
+		{"short address hash", func(p *Proof) { p.AddressHash = p.AddressHash[:31] }, false},
+		{"short signature", func(p *Proof) { p.Signature = p.Signature[:63] }, false},
+		{"flipped signature bit", func(p *Proof) {
+			p.Signature = append([]byte{}, p.Signature...)
+			p.Signature[0] ^= 1
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+			err := Verify(pub, p)
+			if tt.ok && err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatal("Verify() accepted an invalid proof")
+			}
+		})
+	}
+
+	if err := Verify(pub[:31], valid); err == nil {
+		t.Fatal("Verify() accepted a short public key")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	hash := bytes.Repeat([]byte{0xab}, 32)
+	msg, err := Message(Proof{
+		Timestamp:   0x0102030405060708,
+		DomainLen:   3,
+		Domain:      "a.b",
+		Payload:     "nonce",
+		AddressWC:   -1,
+		AddressHash: hash,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want []byte
+	want = append(want, "ton-proof-item-v2/"...)
+	want = append(want, 0xff, 0xff, 0xff, 0xff)
+	want = append(want, hash...)
+	want = append(want, 3, 0, 0, 0)
+	want = append(want, "a.b"...)
+	want = append(want, 8, 7, 6, 5, 4, 3, 2, 1)
+	want = append(want, "nonce"...)
+	if !bytes.Equal(msg, want) {
+		t.Fatalf("Message() = %x, want %x", msg, want)
+	}
+}
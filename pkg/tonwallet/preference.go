@@ -0,0 +1,45 @@
+package tonwallet
+
+import "strings"
+
+// Preference orders wallet versions from the most to the least preferred.
+// Versions missing from the list rank after all listed ones.
+type Preference []Version
+
+// DefaultPreference prefers the newest contracts.
+var DefaultPreference = Preference(Versions)
+
+// ParsePreference reads a comma separated list of versions, such as "v5r1,v4r2".
+// An empty list yields DefaultPreference.
+func ParsePreference(s string) (Preference, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultPreference, nil
+	}
+	return ParsePreferenceList(strings.Split(s, ","))
+}
+
+// ParsePreferenceList reads a list of versions. An empty list yields DefaultPreference.
+func ParsePreferenceList(list []string) (Preference, error) {
+	if len(list) == 0 {
+		return DefaultPreference, nil
+	}
+	p := make(Preference, 0, len(list))
+	for _, item := range list {
+		v, err := ParseVersion(item)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, v)
+	}
+	return p, nil
+}
+
+// Rank returns the position of the version in the preference, lower is better.
+func (p Preference) Rank(v Version) int {
+	for i, preferred := range p {
+		if preferred == v {
+			return i
+		}
+	}
+	return len(p)
+}
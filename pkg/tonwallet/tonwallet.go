@@ -0,0 +1,264 @@
+package tonwallet
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xssnick/tonutils-go/address"
+	"github.com/xssnick/tonutils-go/tlb"
+	"github.com/xssnick/tonutils-go/ton/wallet"
+	"github.com/xssnick/tonutils-go/tvm/cell"
+)
+
+// Version is a standard wallet contract supported for offline derivation.
+type Version string
+
+const (
+	V3R1 Version = "v3r1"
+	V3R2 Version = "v3r2"
+	V4R2 Version = "v4r2"
+	V5R1 Version = "v5r1"
+)
+
+const (
+	MainnetGlobalID int32 = -239
+	TestnetGlobalID int32 = -3
+)
+
+// Versions lists the supported contracts from the newest to the oldest.
+var Versions = []Version{V5R1, V4R2, V3R2, V3R1}
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported wallet version")
+	ErrStateInitMismatch  = errors.New("state init does not match the address")
+	ErrKeyMismatch        = errors.New("state init holds another public key")
+)
+
+// Options describe how a wallet contract was deployed.
+type Options struct {
+	// Workchain of the wallet, 0 for the basechain.
+	Workchain int8
+	// SubwalletID of v3 and v4 wallets, 0 selects the default 698983191 + workchain.
+	SubwalletID uint32
+	// V5SubwalletID is the subwallet number of W5 wallets.
+	V5SubwalletID uint16
+	// NetworkGlobalID of W5 wallets, 0 selects the mainnet.
+	NetworkGlobalID int32
+}
+
+// Wallet is a contract derived from a public key.
+type Wallet struct {
+	Version   Version
+	Address   *address.Address
+	StateInit *tlb.StateInit
+}
+
+func ParseVersion(s string) (Version, error) {
+	v := Version(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range Versions {
+		if v == known {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedVersion, s)
+}
+
+// StateInit builds the initial code and data of the wallet contract.
+func StateInit(pub ed25519.PublicKey, v Version, opts Options) (*tlb.StateInit, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid public key length")
+	}
+
+	cfg, subwallet, err := versionConfig(v, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet.GetStateInit(pub, cfg, subwallet)
+}
+
+// StateInitHash returns the representation hash of the StateInit cell, which is the account id.
+func StateInitHash(pub ed25519.PublicKey, v Version, opts Options) ([]byte, error) {
+	state, err := StateInit(pub, v, opts)
+	if err != nil {
+		return nil, err
+	}
+	return HashStateInit(state)
+}
+
+// HashStateInit returns the representation hash of an arbitrary StateInit.
+func HashStateInit(state *tlb.StateInit) ([]byte, error) {
+	c, err := tlb.ToCell(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize state init: %w", err)
+	}
+	return c.Hash(), nil
+}
+
+// Address derives the address of the wallet contract for the public key.
+func Address(pub ed25519.PublicKey, v Version, opts Options) (*address.Address, error) {
+	hash, err := StateInitHash(pub, v, opts)
+	if err != nil {
+		return nil, err
+	}
+	return address.NewAddress(0, byte(opts.Workchain), hash), nil
+}
+
+// Derive computes the wallets of every supported version for the public key.
+func Derive(pub ed25519.PublicKey, opts Options) ([]Wallet, error) {
+	wallets := make([]Wallet, 0, len(Versions))
+	for _, v := range Versions {
+		state, err := StateInit(pub, v, opts)
+		if err != nil {
+			return nil, err
+		}
+		hash, err := HashStateInit(state)
+		if err != nil {
+			return nil, err
+		}
+		wallets = append(wallets, Wallet{
+			Version:   v,
+			Address:   address.NewAddress(0, byte(opts.Workchain), hash),
+			StateInit: state,
+		})
+	}
+	return wallets, nil
+}
+
+// Match reports which wallet version of the public key has the given address.
+// The workchain of the address overrides the one in opts.
+func Match(addr *address.Address, pub ed25519.PublicKey, opts Options) (Version, bool) {
+	opts.Workchain = int8(addr.Workchain())
+
+	wallets, err := Derive(pub, opts)
+	if err != nil {
+		return "", false
+	}
+	for _, w := range wallets {
+		if bytes.Equal(w.Address.Data(), addr.Data()) {
+			return w.Version, true
+		}
+	}
+	return "", false
+}
+
+// VersionByCode reports which supported wallet version runs the given contract code.
+func VersionByCode(code *cell.Cell) (Version, bool) {
+	key := make(ed25519.PublicKey, ed25519.PublicKeySize)
+	for _, v := range Versions {
+		state, err := StateInit(key, v, Options{})
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(state.Code.Hash(), code.Hash()) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// PublicKeyFromData reads the public key from the persistent data of a wallet contract.
+func PublicKeyFromData(v Version, data *cell.Cell) (ed25519.PublicKey, error) {
+	// Fields stored before the key: seqno and subwallet id for v3/v4, the signature
+	// flag, seqno and wallet id for W5.
+	var prefix uint
+	switch v {
+	case V3R1, V3R2, V4R2:
+		prefix = 32 + 32
+	case V5R1:
+		prefix = 1 + 32 + 32
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedVersion, v)
+	}
+
+	s := data.BeginParse()
+	if _, err := s.LoadSlice(prefix); err != nil {
+		return nil, fmt.Errorf("failed to parse wallet data: %w", err)
+	}
+	key, err := s.LoadSlice(256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return key, nil
+}
+
+// CheckStateInit verifies that the StateInit deploys a known wallet contract of the
+// public key at addr and returns its version.
+func CheckStateInit(addr *address.Address, state *tlb.StateInit, pub ed25519.PublicKey) (Version, error) {
+	hash, err := HashStateInit(state)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(hash, addr.Data()) {
+		return "", ErrStateInitMismatch
+	}
+	if state.Code == nil || state.Data == nil {
+		return "", fmt.Errorf("%w: state init without code or data", ErrUnsupportedVersion)
+	}
+
+	v, ok := VersionByCode(state.Code)
+	if !ok {
+		return "", fmt.Errorf("%w: unknown contract code", ErrUnsupportedVersion)
+	}
+	key, err := PublicKeyFromData(v, state.Data)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(key, pub) {
+		return "", ErrKeyMismatch
+	}
+	return v, nil
+}
+
+// ParseStateInit decodes a BoC with a StateInit, as sent by TonConnect wallets.
+func ParseStateInit(boc []byte) (*tlb.StateInit, error) {
+	c, err := cell.FromBOC(boc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse BoC: %w", err)
+	}
+
+	var state tlb.StateInit
+	if err := tlb.LoadFromCell(&state, c.BeginParse()); err != nil {
+		return nil, fmt.Errorf("failed to parse state init: %w", err)
+	}
+	return &state, nil
+}
+
+// StateInitBoC serializes a StateInit into a BoC.
+func StateInitBoC(state *tlb.StateInit) ([]byte, error) {
+	c, err := tlb.ToCell(state)
+	if err != nil {
+		return nil, err
+	}
+	return c.ToBOC(), nil
+}
+
+func versionConfig(v Version, opts Options) (wallet.VersionConfig, uint32, error) {
+	subwallet := opts.SubwalletID
+	if subwallet == 0 {
+		subwallet = uint32(wallet.DefaultSubwallet + int64(opts.Workchain))
+	}
+
+	switch v {
+	case V3R1:
+		return wallet.V3R1, subwallet, nil
+	case V3R2:
+		return wallet.V3R2, subwallet, nil
+	case V4R2:
+		return wallet.V4R2, subwallet, nil
+	case V5R1:
+		network := opts.NetworkGlobalID
+		if network == 0 {
+			network = MainnetGlobalID
+		}
+		return wallet.ConfigV5R1Final{
+			NetworkGlobalID: network,
+			Workchain:       opts.Workchain,
+		}, uint32(opts.V5SubwalletID), nil
+	default:
+		return nil, 0, fmt.Errorf("%w: %s", ErrUnsupportedVersion, v)
+	}
+}
@@ -0,0 +1,144 @@
+package tonwallet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/xssnick/tonutils-go/address"
+)
+
+// StateInit sent by a v4r2 wallet app along with its ton_proof.
+const vectorStateInit = "te6cckECFgEAAwQAAgE0AgEAUQAAAAApqaMXp6kMOCJ4z0QdKdtKvLvoflXmJvj/gmw26izx4ImGnYtAART/APSkE/S88sgLAwIBIAgEBPjygwjXGCDTH9Mf0x8C+CO78mTtRNDTH9Mf0//0BNFRQ7ryoVFRuvKiBfkBVBBk+RDyo/gAJKTIyx9SQMsfUjDL/1IQ9ADJ7VT4DwHTByHAAJ9sUZMg10qW0wfUAvsA6DDgIcAB4wAhwALjAAHAA5Ew4w0DpMjLHxLLH8v/BwYQBQAK9ADJ7VQAcIEBCNcY+gDTP8hUIEeBAQj0UfKnghBub3RlcHSAGMjLBcsCUAbPFlAE+gIUy2oSyx/LP8lz+wACAG7SB/oA1NQi+QAFyMoHFcv/ydB3dIAYyMsFywIizxZQBfoCFMtrEszMyXP7AMhAFIEBCPRR8qcCAgFIDQkCASALCgBZvSQrb2omhAgKBrkPoCGEcNQICEekk30pkQzmkD6f+YN4EoAbeBAUiYcVnzGEAgEgEQwAEbjJftRNDXCx+ALm0AHQ0wMhcbCSXwTgItdJwSCSXwTgAtMfIYIQcGx1Z70ighBkc3RyvbCSXwXgA/pAMCD6RAHIygfL/8nQ7UTQgQFA1yH0BDBcgQEI9ApvoTGzkl8H4AXTP8glghBwbHVnupI4MOMNA4IQZHN0crqSXwbjDQ8OAIpQBIEBCPRZMO1E0IEBQNcgyAHPFvQAye1UAXKwjiOCEGRzdHKDHrFwgBhQBcsFUAPPFiP6AhPLassfyz/JgED7AJJfA+IAeAH6APQEMPgnbyIwUAqhIb7y4FCCEHBsdWeDHrFwgBhQBMsFJs8WWPoCGfQAy2kXyx9SYMs/IMmAQPsABgBsgQEI1xj6ANM/MFIkgQEI9Fnyp4IQZHN0cnB0gBjIywXLAlAFzxZQA/oCE8tqyx8Syz/Jc/sAAgFYFRICASAUEwAZrx32omhAEGuQ64WPwAAZrc52omhAIGuQ64X/wAA9sp37UTQgQFA1yH0BDACyMoHy//J0AGBAQj0Cm+hMYHrKDBA="
+
+func mustKey(t *testing.T, s string) []byte {
+	t.Helper()
+	key, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		version Version
+		want    string
+	}{
+		{"v3r2", "dcc39550bb494f4b493e7efe1aa18ea31470f33a2553c568cb74a17ed56790c1", V3R2, "EQCvoBT5Keb46oUhI_DpX0WXFDdX9ZyxXBfX3FC9cZa90nQP"},
+		{"v4r2", "a7a90c382278cf441d29db4abcbbe87e55e626f8ff826c36ea2cf1e089869d8b", V4R2, "0:960ab627408d5472d9d125b667cbe00ce17eeaa44e9dc6a86e93cdfef2c480d5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Address(mustKey(t, tt.key), tt.version, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := address.ParseAddr(tt.want)
+			if err != nil {
+				want, err = address.ParseRawAddr(tt.want)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got.Data(), want.Data()) || got.Workchain() != want.Workchain() {
+				t.Fatalf("Address() = %s, want %s", got.StringRaw(), want.StringRaw())
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	key := mustKey(t, "a7a90c382278cf441d29db4abcbbe87e55e626f8ff826c36ea2cf1e089869d8b")
+	for _, v := range Versions {
+		addr, err := Address(key, v, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := Match(addr, key, Options{}); !ok || got != v {
+			t.Fatalf("Match() = %s, %v, want %s", got, ok, v)
+		}
+	}
+
+	other := make([]byte, 32)
+	addr, _ := Address(key, V4R2, Options{})
+	if _, ok := Match(addr, other, Options{}); ok {
+		t.Fatal("Match() matched the wallet of another key")
+	}
+}
+
+func TestCheckStateInit(t *testing.T) {
+	boc, err := base64.StdEncoding.DecodeString(vectorStateInit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := ParseStateInit(boc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := mustKey(t, "a7a90c382278cf441d29db4abcbbe87e55e626f8ff826c36ea2cf1e089869d8b")
+	addr := address.MustParseRawAddr("0:960ab627408d5472d9d125b667cbe00ce17eeaa44e9dc6a86e93cdfef2c480d5")
+	other := address.MustParseRawAddr("0:1111111111111111111111111111111111111111111111111111111111111111")
+
+	tests := []struct {
+		name    string
+		addr    *address.Address
+		key     []byte
+		want    Version
+		wantErr error
+	}{
+		{"wallet of the key", addr, key, V4R2, nil},
+		{"other address", other, key, "", ErrStateInitMismatch},
+		{"other key", addr, make([]byte, 32), "", ErrKeyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckStateInit(tt.addr, state, tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckStateInit() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("CheckStateInit() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublicKeyFromData(t *testing.T) {
+	key := mustKey(t, "dcc39550bb494f4b493e7efe1aa18ea31470f33a2553c568cb74a17ed56790c1")
+	for _, v := range Versions {
+		t.Run(string(v), func(t *testing.T) {
+			state, err := StateInit(key, v, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, ok := VersionByCode(state.Code); !ok || got != v {
+				t.Fatalf("VersionByCode() = %s, %v", got, ok)
+			}
+			got, err := PublicKeyFromData(v, state.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, key) {
+				t.Fatalf("PublicKeyFromData() = %x, want %x", got, key)
+			}
+		})
+	}
+}
+
+func TestParsePreference(t *testing.T) {
+	p, err := ParsePreference(" v4r2, V3R2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Rank(V4R2) != 0 || p.Rank(V3R2) != 1 || p.Rank(V5R1) != 2 {
+		t.Fatalf("ParsePreference() = %v", p)
+	}
+	if _, err := ParsePreference("v4r2,v2"); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("ParsePreference() error = %v, want %v", err, ErrUnsupportedVersion)
+	}
+}
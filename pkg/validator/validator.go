@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"TON/pkg/address"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -9,11 +11,20 @@ type CustomValidator struct {
 }
 
 func NewCustomValidator() *CustomValidator {
+	v := validator.New()
+	_ = v.RegisterValidation("ton_address", validateTonAddress)
+
 	return &CustomValidator{
-		validator: validator.New(),
+		validator: v,
 	}
 }
 
 func (cv *CustomValidator) Validate(i interface{}) error {
 	return cv.validator.Struct(i)
 }
+
+// validateTonAddress accepts raw and user-friendly TON addresses with a valid checksum.
+func validateTonAddress(fl validator.FieldLevel) bool {
+	_, err := address.Parse(fl.Field().String())
+	return err == nil
+}